@@ -9,6 +9,9 @@ import (
 	handler "main-server/pkg/handler"
 	repository "main-server/pkg/repository"
 	"main-server/pkg/service"
+	authService "main-server/pkg/service/auth"
+	"main-server/pkg/service/keyring"
+	"main-server/pkg/service/password"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,7 +19,6 @@ import (
 
 	// excel_analysis "main-server/pkg/module/excel_analysis"
 
-	"github.com/casbin/casbin/v2"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -176,14 +178,16 @@ func main() {
 	defer fileFatal.Close()
 
 	// Создание нового подключения к БД
-	db, err := repository.NewPostgresDB(repository.Config{
+	dbConfig := repository.Config{
 		Host:     viper.GetString("db.host"),
 		Port:     viper.GetString("db.port"),
 		Username: viper.GetString("db.username"),
 		DBName:   viper.GetString("db.dbname"),
 		SSLMode:  viper.GetString("db.sslmode"),
 		Password: os.Getenv("DB_PASSWORD"),
-	})
+	}
+
+	db, err := repository.NewPostgresDB(dbConfig)
 
 	// Создание строки DNS
 	dns := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
@@ -206,7 +210,7 @@ func main() {
 		logrus.Fatalf("failed to initialize adapter by db with custom table: %s", err.Error())
 	}
 
-	enforcer, err := casbin.NewEnforcer(viper.GetString("paths.perm_model"), adapter)
+	enforcer, err := repository.NewEnforcer(viper.GetString("paths.perm_model"), adapter)
 
 	if err != nil {
 		logrus.Fatalf("failed to initialize new enforcer: %s", err.Error())
@@ -216,13 +220,77 @@ func main() {
 		logrus.Fatalf("failed to initialize db: %s", err.Error())
 	}
 
+	/* Синхронизация правил доступа между экземплярами сервера (Postgres LISTEN/NOTIFY) */
+	config.InitPolicyWatcherConfig()
+
+	watcher, err := repository.NewPolicyWatcher(dbConfig, db, enforcer)
+	if err != nil {
+		logrus.Fatalf("failed to initialize policy watcher: %s", err.Error())
+	}
+
+	if err := enforcer.SetWatcher(watcher); err != nil {
+		logrus.Fatalf("failed to set policy watcher: %s", err.Error())
+	}
+
+	/* Init HTTP server settings (trusted reverse proxies) */
+	if err := config.InitHTTPConfig(); err != nil {
+		logrus.Fatalf("error initializing http config: %s", err.Error())
+	}
+
+	/* Init request domain resolution settings */
+	config.InitDomainConfig()
+
+	/* Init account activation settings */
+	config.InitActivationConfig()
+
+	/* Init account deletion settings */
+	config.InitAccountConfig()
+
+	/* Init password hashing and password policy settings */
+	config.InitPasswordConfig()
+
+	if err := password.LoadBlocklist(); err != nil {
+		logrus.Fatalf("error loading password blocklist: %s", err.Error())
+	}
+
+	/* Init access token signing keys settings */
+	if err := config.InitKeyringConfig(); err != nil {
+		logrus.Fatalf("error initializing signing keys config: %s", err.Error())
+	}
+
 	/* Init oauth2 services */
 	config.InitOAuth2Config()
 	config.InitVKAuthConfig()
 
+	if err := config.InitOIDCConfig(); err != nil {
+		logrus.Fatalf("error initializing oidc providers config: %s", err.Error())
+	}
+
+	if err := authService.InitProviders(); err != nil {
+		logrus.Fatalf("error initializing oauth2 providers: %s", err.Error())
+	}
+
 	/* Dependency injection */
-	repos := repository.NewRepository(db, enforcer)
+	repos := repository.NewRepository(db, enforcer, watcher)
+
+	/* Загрузка ключей подписи токенов доступа и запуск их плановой ротации */
+	if err := keyring.Init(repos.SigningKey); err != nil {
+		logrus.Fatalf("error initializing signing keyring: %s", err.Error())
+	}
+	keyring.StartRotation()
+
 	service := service.NewService(repos)
+
+	/* Регистрация типов аутентификации для подключённых провайдеров */
+	for _, name := range authService.ProviderNames() {
+		if err := service.AuthType.AddAuthType(name); err != nil {
+			logrus.Fatalf("error registering auth type %s: %s", name, err.Error())
+		}
+	}
+
+	/* Удаление аккаунтов, период ожидания удаления которых истёк */
+	service.Account.StartPurge()
+
 	handlers := handler.NewHandler(service)
 
 	srv := new(mainserver.Server)
@@ -252,6 +320,8 @@ func main() {
 		logrus.Errorf("error occured on server shutting down: %s", err.Error())
 	}
 
+	watcher.Close()
+
 	if err := db.Close(); err != nil {
 		logrus.Errorf("error occured on db connection close: %s", err.Error())
 	}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"main-server/pkg/migration"
+	repository "main-server/pkg/repository"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const usage = `Использование: migrate <команда> [аргумент]
+
+Команды:
+  up [N]          применение N следующих миграций (по-умолчанию - всех)
+  down [N]        откат N последних миграций (по-умолчанию - одной)
+  down all        откат всех применённых миграций
+  status          вывод информации о состоянии схемы базы данных
+  force VERSION   принудительная установка версии схемы (снятие флага dirty)`
+
+/* Утилита управления миграциями схемы базы данных */
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	// Инициализация конфигурации (аналогично основному серверу)
+	if err := initConfig(); err != nil {
+		logrus.Fatalf("error initializing configs: %s", err.Error())
+	}
+
+	if err := godotenv.Load(); err != nil {
+		logrus.Fatalf("error loading env variable: %s", err.Error())
+	}
+
+	// Создание нового подключения к БД
+	db, err := repository.NewPostgresDB(repository.Config{
+		Host:     viper.GetString("db.host"),
+		Port:     viper.GetString("db.port"),
+		Username: viper.GetString("db.username"),
+		DBName:   viper.GetString("db.dbname"),
+		SSLMode:  viper.GetString("db.sslmode"),
+		Password: os.Getenv("DB_PASSWORD"),
+	})
+
+	if err != nil {
+		logrus.Fatalf("failed to initialize db: %s", err.Error())
+	}
+
+	migrator, err := migration.NewMigrator(db.DB)
+	if err != nil {
+		logrus.Fatalf("failed to initialize migrator: %s", err.Error())
+	}
+
+	defer migrator.Close()
+
+	command := os.Args[1]
+	argument := ""
+
+	if len(os.Args) > 2 {
+		argument = os.Args[2]
+	}
+
+	switch command {
+	case "up":
+		steps, err := parseSteps(argument, 0)
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+
+		if err := migrator.Up(steps); err != nil {
+			logrus.Fatalf("error applying migrations: %s", err.Error())
+		}
+
+	case "down":
+		steps := 0
+
+		if argument != "all" {
+			steps, err = parseSteps(argument, 1)
+			if err != nil {
+				logrus.Fatal(err.Error())
+			}
+		}
+
+		if err := migrator.Down(steps); err != nil {
+			logrus.Fatalf("error rolling back migrations: %s", err.Error())
+		}
+
+	case "force":
+		version, err := strconv.Atoi(argument)
+		if err != nil {
+			logrus.Fatalf("invalid version: %s", argument)
+		}
+
+		if err := migrator.Force(version); err != nil {
+			logrus.Fatalf("error forcing version: %s", err.Error())
+		}
+
+	case "status":
+
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	printStatus(migrator)
+}
+
+/* Вывод информации о состоянии схемы */
+func printStatus(migrator *migration.Migrator) {
+	status, err := migrator.Status()
+	if err != nil {
+		logrus.Fatalf("error getting migration status: %s", err.Error())
+	}
+
+	fmt.Printf("Текущая версия схемы: %d", status.Version)
+	if status.Dirty {
+		fmt.Print(" (dirty)")
+	}
+	fmt.Println()
+
+	for _, item := range status.Migrations {
+		state := "pending"
+		if item.Applied {
+			state = "applied"
+		}
+
+		fmt.Printf("  %06d  %-8s %s\n", item.Version, state, item.Identifier)
+	}
+}
+
+/* Разбор количества шагов миграции */
+func parseSteps(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	steps, err := strconv.Atoi(value)
+	if err != nil || steps <= 0 {
+		return 0, fmt.Errorf("invalid number of steps: %s", value)
+	}
+
+	return steps, nil
+}
+
+/* Инициализация файлов конфигурации */
+func initConfig() error {
+	viper.AddConfigPath("config")
+	viper.SetConfigName("config")
+
+	return viper.ReadInConfig()
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type AccountConfig struct {
+	// Время, в течение которого запрос на удаление аккаунта можно отменить
+	DeletionGrace time.Duration
+
+	// Интервал проверки аккаунтов, срок удаления которых наступил
+	PurgeInterval time.Duration
+}
+
+var AppAccountConfig AccountConfig
+
+/* Инициализация настроек удаления аккаунтов (секция account в конфигурации) */
+func InitAccountConfig() {
+	grace := viper.GetDuration("account.deletion_grace")
+	if grace <= 0 {
+		grace = 14 * 24 * time.Hour
+	}
+
+	interval := viper.GetDuration("account.purge_interval")
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	AppAccountConfig = AccountConfig{
+		DeletionGrace: grace,
+		PurgeInterval: interval,
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+/* Режимы проверки активации аккаунта */
+const (
+	ACTIVATION_MODE_OFF       = "off"       // Активация не проверяется
+	ACTIVATION_MODE_BLOCK     = "block"     // Вход и доступ к API запрещены до активации
+	ACTIVATION_MODE_READ_ONLY = "read_only" // До активации доступны только маршруты получения данных
+)
+
+type ActivationConfig struct {
+	Mode string
+
+	// Срок действия ссылки активации
+	TTL time.Duration
+
+	// Минимальный интервал между повторными отправками письма
+	ResendCooldown time.Duration
+}
+
+var AppActivationConfig ActivationConfig
+
+/* Инициализация настроек активации аккаунта (секция activation в конфигурации) */
+func InitActivationConfig() {
+	mode := viper.GetString("activation.mode")
+	if mode != ACTIVATION_MODE_BLOCK && mode != ACTIVATION_MODE_READ_ONLY {
+		mode = ACTIVATION_MODE_OFF
+	}
+
+	ttl := viper.GetDuration("activation.ttl")
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	cooldown := viper.GetDuration("activation.resend_cooldown")
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+
+	AppActivationConfig = ActivationConfig{
+		Mode:           mode,
+		TTL:            ttl,
+		ResendCooldown: cooldown,
+	}
+}
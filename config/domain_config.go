@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type DomainConfig struct {
+	// Домен по умолчанию (используется, если домен не указан в заголовке и имя хоста не привязано к домену)
+	Default string
+
+	// Заголовок запроса, в котором клиент может явно указать домен
+	Header string
+
+	// Привязка имён хостов к доменам (имя хоста -> значение домена)
+	Hosts map[string]string
+
+	// Время хранения найденного домена в кэше
+	CacheTTL time.Duration
+}
+
+var AppDomainConfig DomainConfig
+
+/* Инициализация настроек определения домена запроса (ключ domain и секция domains в конфигурации) */
+func InitDomainConfig() {
+	header := viper.GetString("domains.header")
+	if header == "" {
+		header = "X-Domain"
+	}
+
+	// Имена хостов не чувствительны к регистру
+	hosts := map[string]string{}
+	for host, domain := range viper.GetStringMapString("domains.hosts") {
+		hosts[strings.ToLower(host)] = domain
+	}
+
+	ttl := viper.GetDuration("domains.cache_ttl")
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	AppDomainConfig = DomainConfig{
+		Default:  viper.GetString("domain"),
+		Header:   header,
+		Hosts:    hosts,
+		CacheTTL: ttl,
+	}
+}
+
+/* Проверка того, что домен задан в конфигурации (основной домен или домен, привязанный к имени хоста) */
+func IsConfiguredDomain(value string) bool {
+	if value == AppDomainConfig.Default {
+		return true
+	}
+
+	for _, domain := range AppDomainConfig.Hosts {
+		if domain == value {
+			return true
+		}
+	}
+
+	return false
+}
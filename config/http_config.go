@@ -1,7 +1,46 @@
 package config
 
-import "net/http"
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/viper"
+)
 
 const (
 	HTTPSameSite = http.SameSiteStrictMode
 )
+
+type HTTPConfig struct {
+	// Адреса (IP или CIDR) обратных прокси, от которых принимается адрес клиента из X-Forwarded-For.
+	// Пустой список - адрес клиента всегда берётся из соединения
+	TrustedProxies []string
+}
+
+var AppHTTPConfig HTTPConfig
+
+/* Инициализация настроек HTTP-сервера (ключ trusted_proxies в конфигурации) */
+func InitHTTPConfig() error {
+	var proxies []string
+
+	for _, proxy := range viper.GetStringSlice("trusted_proxies") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("trusted_proxies: invalid IP address or CIDR %q", proxy)
+		}
+
+		proxies = append(proxies, proxy)
+	}
+
+	AppHTTPConfig = HTTPConfig{
+		TrustedProxies: proxies,
+	}
+
+	return nil
+}
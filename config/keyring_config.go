@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	authConstant "main-server/pkg/constant/auth"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+/* Алгоритмы подписи токенов доступа */
+const (
+	KEYRING_ALG_RS256 = "RS256"
+	KEYRING_ALG_EDDSA = "EdDSA"
+)
+
+/* Переменная окружения с ключом шифрования закрытых ключей подписи (32 байта в base64) */
+const KEYRING_ENCRYPTION_KEY_ENV = "SIGNING_KEY_ENCRYPTION_KEY"
+
+type KeyringConfig struct {
+	// Алгоритм подписи новых ключей
+	Algorithm string
+
+	// Интервал плановой ротации ключа подписи
+	RotationInterval time.Duration
+
+	// Срок, в течение которого выведенный из ротации ключ остаётся доступным для проверки токенов
+	Retention time.Duration
+
+	// Ключ AES-256 для шифрования закрытых ключей подписи в БД
+	EncryptionKey []byte
+}
+
+var AppKeyringConfig KeyringConfig
+
+/*
+* Инициализация настроек ключей подписи (секция token.keyring в конфигурации).
+* Ключ шифрования закрытых ключей передаётся только через переменную окружения и обязателен
+ */
+func InitKeyringConfig() error {
+	encryptionKey, err := base64.StdEncoding.DecodeString(os.Getenv(KEYRING_ENCRYPTION_KEY_ENV))
+	if err != nil || len(encryptionKey) != 32 {
+		return errors.New(KEYRING_ENCRYPTION_KEY_ENV + " must contain a base64-encoded 32-byte key")
+	}
+
+	algorithm := viper.GetString("token.keyring.algorithm")
+	if algorithm != KEYRING_ALG_EDDSA {
+		algorithm = KEYRING_ALG_RS256
+	}
+
+	rotation := viper.GetDuration("token.keyring.rotation_interval")
+	if rotation <= 0 {
+		rotation = 30 * 24 * time.Hour
+	}
+
+	// Выведенный ключ должен оставаться в JWKS не меньше времени жизни выданных им токенов
+	retention := viper.GetDuration("token.keyring.retention")
+	if retention < authConstant.TOKEN_TLL_ACCESS {
+		retention = 24 * time.Hour
+	}
+
+	AppKeyringConfig = KeyringConfig{
+		Algorithm:        algorithm,
+		RotationInterval: rotation,
+		Retention:        retention,
+		EncryptionKey:    encryptionKey,
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+/* Настройки провайдера OpenID Connect (секция oidc_providers в конфигурации) */
+type OIDCProviderConfig struct {
+	Name         string   `mapstructure:"name"`   // Значение типа аутентификации (u_auth_types)
+	Issuer       string   `mapstructure:"issuer"` // Адрес издателя (для .well-known/openid-configuration)
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	AuthURL      string   `mapstructure:"auth_url"`
+	TokenURL     string   `mapstructure:"token_url"`
+	UserInfoURL  string   `mapstructure:"userinfo_url"`
+	RevokeURL    string   `mapstructure:"revoke_url"`
+	JWKSURL      string   `mapstructure:"jwks_url"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+/* Конфигурация OAuth2 клиента для провайдера OpenID Connect */
+func (c *OIDCProviderConfig) OAuth2Config() oauth2.Config {
+	scopes := c.Scopes
+	if len(scopes) <= 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  c.AuthURL,
+			TokenURL: c.TokenURL,
+		},
+		RedirectURL: c.RedirectURL,
+		Scopes:      scopes,
+	}
+}
+
+var AppOIDCConfig []OIDCProviderConfig
+
+/* Инициализация списка провайдеров OpenID Connect */
+func InitOIDCConfig() error {
+	AppOIDCConfig = []OIDCProviderConfig{}
+
+	return viper.UnmarshalKey("oidc_providers", &AppOIDCConfig)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/* Алгоритмы хэширования паролей */
+const (
+	PASSWORD_ALG_ARGON2ID = "argon2id"
+	PASSWORD_ALG_BCRYPT   = "bcrypt"
+)
+
+type PasswordConfig struct {
+	// Алгоритм хэширования новых паролей
+	Algorithm string
+
+	// Параметры argon2id: объём памяти (КиБ), число проходов и потоков
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+
+	// Стоимость bcrypt (используется, если выбран алгоритм bcrypt)
+	BcryptCost int
+
+	// Минимальная и максимальная длина пароля (в символах)
+	MinLength int
+	MaxLength int
+
+	// Путь к файлу со списком распространённых паролей (по одному в строке)
+	BlocklistPath string
+}
+
+var AppPasswordConfig PasswordConfig
+
+/* Инициализация настроек хэширования и политики паролей (секция password в конфигурации) */
+func InitPasswordConfig() {
+	algorithm := viper.GetString("password.algorithm")
+	if algorithm != PASSWORD_ALG_BCRYPT {
+		algorithm = PASSWORD_ALG_ARGON2ID
+	}
+
+	memory := viper.GetUint32("password.argon2.memory")
+	if memory < 8*1024 {
+		memory = 19 * 1024
+	}
+
+	iterations := viper.GetUint32("password.argon2.time")
+	if iterations <= 0 {
+		iterations = 2
+	}
+
+	threads := viper.GetUint("password.argon2.threads")
+	if threads <= 0 || threads > 255 {
+		threads = 1
+	}
+
+	// Стоимость bcrypt по-прежнему задаётся параметром crypt.cost
+	cost := viper.GetInt("crypt.cost")
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+
+	minLength := viper.GetInt("password.min_length")
+	if minLength <= 0 {
+		minLength = 8
+	}
+
+	// Ограничение сверху защищает от хэширования произвольно длинных строк
+	maxLength := viper.GetInt("password.max_length")
+	if maxLength < minLength {
+		maxLength = 128
+	}
+
+	blocklist := viper.GetString("password.blocklist")
+	if blocklist == "" {
+		blocklist = "config/common-passwords.txt"
+	}
+
+	AppPasswordConfig = PasswordConfig{
+		Algorithm:     algorithm,
+		Argon2Memory:  memory,
+		Argon2Time:    iterations,
+		Argon2Threads: uint8(threads),
+		BcryptCost:    cost,
+		MinLength:     minLength,
+		MaxLength:     maxLength,
+		BlocklistPath: blocklist,
+	}
+}
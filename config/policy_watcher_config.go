@@ -0,0 +1,54 @@
+package config
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type PolicyWatcherConfig struct {
+	// Канал Postgres (LISTEN/NOTIFY), через который экземпляры сервера обмениваются изменениями правил
+	Channel string
+
+	// Интервал полной перезагрузки правил (страховка от пропущенных уведомлений)
+	ReloadInterval time.Duration
+
+	// Минимальный и максимальный интервалы переподключения слушателя к БД
+	MinReconnect time.Duration
+	MaxReconnect time.Duration
+}
+
+var AppPolicyWatcherConfig PolicyWatcherConfig
+
+var policyChannelRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+/* Инициализация настроек синхронизации правил доступа (секция casbin.watcher в конфигурации) */
+func InitPolicyWatcherConfig() {
+	channel := viper.GetString("casbin.watcher.channel")
+	if !policyChannelRegexp.MatchString(channel) {
+		channel = "casbin_policy"
+	}
+
+	reload := viper.GetDuration("casbin.watcher.reload_interval")
+	if reload <= 0 {
+		reload = 10 * time.Minute
+	}
+
+	minReconnect := viper.GetDuration("casbin.watcher.min_reconnect")
+	if minReconnect <= 0 {
+		minReconnect = 10 * time.Second
+	}
+
+	maxReconnect := viper.GetDuration("casbin.watcher.max_reconnect")
+	if maxReconnect < minReconnect {
+		maxReconnect = minReconnect * 6
+	}
+
+	AppPolicyWatcherConfig = PolicyWatcherConfig{
+		Channel:        channel,
+		ReloadInterval: reload,
+		MinReconnect:   minReconnect,
+		MaxReconnect:   maxReconnect,
+	}
+}
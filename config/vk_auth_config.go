@@ -8,16 +8,45 @@ import (
 
 type VkAuthConfig struct {
 	VkAuth oauth2.Config
+
+	// Базовый адрес VK API (методы users.get и т.д.)
+	ApiURL string
 }
 
 var AppVKAuthConfig VkAuthConfig
 
+/*
+* Инициализация конфигурации VK OAuth2.
+* Адреса авторизации, получения токена и VK API могут быть переопределены
+* в конфигурации (например, для работы с тестовым сервером)
+ */
 func InitVKAuthConfig() {
-	AppOAuth2Config.GoogleLogin = oauth2.Config{
+	endpoint := vk.Endpoint
+
+	if authURL := viper.GetString("vk_oauth2.auth_url"); authURL != "" {
+		endpoint.AuthURL = authURL
+	}
+
+	if tokenURL := viper.GetString("vk_oauth2.token_url"); tokenURL != "" {
+		endpoint.TokenURL = tokenURL
+	}
+
+	redirectURL := viper.GetString("vk_oauth2.redirect_url")
+	if redirectURL == "" {
+		redirectURL = "http://localhost:3000"
+	}
+
+	apiURL := viper.GetString("vk_oauth2.api_url")
+	if apiURL == "" {
+		apiURL = "https://api.vk.com/method"
+	}
+
+	AppVKAuthConfig.VkAuth = oauth2.Config{
 		ClientID:     viper.GetString("vk_oauth2.client_id"),
 		ClientSecret: viper.GetString("vk_oauth2.client_secret"),
-		Endpoint:     vk.Endpoint,
-		RedirectURL:  "http://localhost:3000",
-		Scopes:       []string{"account"},
+		Endpoint:     endpoint,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"email"},
 	}
+	AppVKAuthConfig.ApiURL = apiURL
 }
@@ -10,6 +10,10 @@ const (
 	// Action system
 	ADMINISTRATION = "administration"
 	MANAGEMENT     = "management"
+
+	// Effect of the access rule
+	ALLOW = "allow"
+	DENY  = "deny"
 )
 
 func GetSlice() []string {
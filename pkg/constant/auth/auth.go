@@ -3,12 +3,65 @@ package auth
 import "time"
 
 const (
-	SALT              = "a;ugb*(AW^GFA&WTVFawtfva79wf6g7a6f2r8tc127tVIYTAWCFA&(T"
-	SIGNING_KEY       = "AOgnaiouGHA()wH8WFG8uga8eya7G9g9UBA@e@h(rh@u(!"
 	TOKEN_TLL_ACCESS  = 1 * time.Hour
 	TOKEN_TLL_REFRESH = 12 * time.Hour
 	TOKEN_TLL_RESET   = 5 * time.Minute
+	TOKEN_TLL_2FA     = 5 * time.Minute
+
+	// Смена email-адреса
+	TOKEN_TLL_EMAIL_CHANGE   = 24 * time.Hour
+	EMAIL_CHANGE_TOKEN_BYTES = 32
+
+	// Двухфакторная аутентификация
+	TWO_FACTOR_MAX_ATTEMPTS = 5
+	RECOVERY_CODES_COUNT    = 10
+	TWO_FACTOR_ISSUER       = "main-server"
+
+	// Защита от перебора: действия, для которых ведётся учёт неудачных попыток
+	THROTTLE_SIGN_IN    = "sign_in"
+	THROTTLE_2FA        = "two_factor"
+	THROTTLE_RECOVERY   = "recovery"
+	THROTTLE_RESET      = "reset"
+	THROTTLE_SERVICE    = "service_token"
+	THROTTLE_ACTIVATION = "activation"
+
+	// Область счётчика неудачных попыток (учётная запись или IP-адрес)
+	THROTTLE_SCOPE_ACCOUNT = "account"
+	THROTTLE_SCOPE_IP      = "ip"
+
+	// Экспоненциальная задержка после исчерпания бесплатных попыток и временная блокировка
+	THROTTLE_WINDOW           = 1 * time.Hour
+	THROTTLE_BASE_DELAY       = 1 * time.Second
+	THROTTLE_MAX_DELAY        = 15 * time.Minute
+	THROTTLE_ACCOUNT_FREE     = 3
+	THROTTLE_IP_FREE          = 20
+	THROTTLE_LOCKOUT_ATTEMPTS = 10
+	THROTTLE_LOCKOUT_TTL      = 30 * time.Minute
+
+	// Сервисные клиенты (OAuth2 client credentials)
+	SERVICE_GRANT_CLIENT_CREDENTIALS = "client_credentials"
+	SERVICE_TOKEN_TTL                = 1 * time.Hour
+	SERVICE_SECRET_BYTES             = 32
+
+	// Области доступа сервисных клиентов
+	SCOPE_EMAIL_SEND       = "email:send"
+	SCOPE_TOKEN_VERIFY     = "token:verify"
+	SCOPE_TOKEN_INTROSPECT = "token:introspect"
+	SCOPE_TOKEN_REVOKE     = "token:revoke"
+
+	// Подсказки о типе токена (RFC 7009, RFC 7662)
+	TOKEN_TYPE_HINT_ACCESS  = "access_token"
+	TOKEN_TYPE_HINT_REFRESH = "refresh_token"
 
 	AUTH_TYPE_LOCAL  = "local"
 	AUTH_TYPE_GOOGLE = "google"
+	AUTH_TYPE_VK     = "vk"
 )
+
+/* Области доступа, которые могут быть выданы сервисному клиенту */
+var SERVICE_SCOPES = []string{
+	SCOPE_EMAIL_SEND,
+	SCOPE_TOKEN_VERIFY,
+	SCOPE_TOKEN_INTROSPECT,
+	SCOPE_TOKEN_REVOKE,
+}
@@ -8,6 +8,10 @@ const (
 	ACCESS_TOKEN_CTX     = "access_token"
 	TOKEN_API_CTX        = "token_api"
 	DOMAINS_ID           = "domains_id"
+	DOMAIN_VALUE_CTX     = "domain_value"
+	SESSION_UUID_CTX     = "session_uuid"
+	SERVICE_CLIENT_CTX   = "service_client_id"
+	DEVICE_NAME_HEADER   = "X-Device-Name"
 
 	MN_UI                                            = "ui"
 	MN_UI_LOGOUT                                     = "ui_logout"
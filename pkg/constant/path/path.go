@@ -5,4 +5,5 @@ const (
 	PUBLIC_PROJECT = "public/project/"
 	PUBLIC_OBJECT  = "public/object/"
 	PUBLIC_USER    = "public/profile/"
+	PUBLIC_ARTICLE = "public/article/"
 )
@@ -7,4 +7,5 @@ const (
 	ROLE_MANAGER         = "manager"
 	ROLE_ADMIN           = "admin"
 	ROLE_SUPER_ADMIN     = "super_admin"
+	ROLE_MODERATOR       = "moderator"
 )
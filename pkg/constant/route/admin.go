@@ -5,4 +5,14 @@ const (
 	ADMIN_USER       = "/user"
 	ADMIN_COMPANY    = "/company"
 	SYSTEM           = "/system"
+	ADMIN_BAN        = "/ban"
+	ADMIN_UNBAN      = "/unban"
+	ADMIN_2FA        = "/2fa"
+	ADMIN_2FA_ROLES  = "/roles"
+	ADMIN_SERVICE    = "/service/clients"
+	ADMIN_RBAC       = "/rbac"
+	ADMIN_DOMAINS    = "/domains"
+	ADMIN_ROLES      = "/roles"
+	ADMIN_TYPES      = "/types"
+	ADMIN_ACCESS     = "/access/explain"
 )
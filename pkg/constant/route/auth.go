@@ -2,6 +2,9 @@ package route
 
 const (
 	AUTH_MAIN_ROUTE = "/auth"
+
+	// Открытые ключи для проверки токенов доступа (JWKS)
+	AUTH_JWKS_ROUTE = "/.well-known/jwks.json"
 )
 
 const (
@@ -17,10 +20,27 @@ const (
 	// Google
 	AUTH_SIGN_IN_GOOGLE_ROUTE = "/sign-in/oauth2"
 
+	// Зарегистрированные провайдеры (Google, VK, OpenID Connect)
+	AUTH_SIGN_IN_PROVIDER_ROUTE          = "/sign-in/provider/:provider"
+	AUTH_SIGN_IN_PROVIDER_CALLBACK_ROUTE = "/sign-in/provider/:provider/callback"
+	AUTH_SIGN_IN_PROVIDER_URL_ROUTE      = "/sign-in/provider/:provider/url"
+
+	// Двухфакторная аутентификация (второй шаг входа)
+	AUTH_SIGN_IN_2FA_ROUTE       = "/sign-in/2fa"
+	AUTH_SIGN_IN_2FA_SETUP_ROUTE = "/sign-in/2fa/setup"
+
 	// MAIN
 	AUTH_REFRESH_TOKEN_ROUTE = "/refresh"
 	AUTH_LOGOUT_ROUTE        = "/logout"
 	AUTH_ACTIVATE_ROUTE      = "/activate/:link"
+	AUTH_ACTIVATE_RESEND     = "/activate/resend"
+
+	// Интроспекция и отзыв токенов (для сервисов платформы)
+	AUTH_INTROSPECT_ROUTE = "/introspect"
+	AUTH_REVOKE_ROUTE     = "/revoke"
+
+	// Отмена смены email-адреса (ссылка из письма на прежний адрес)
+	AUTH_EMAIL_CHANGE_CANCEL = "/email/change/cancel"
 
 	// Password Recovery
 	AUTH_RECOVERY_PASSWORD = "/recovery/password"
@@ -0,0 +1,5 @@
+package route
+
+const (
+	ENTITY_MAIN_ROUTE = "/entity"
+)
@@ -3,6 +3,8 @@ package route
 const (
 	MODERATOR_MAIN_ROUTE      = "/moderator"
 	MODERATOR_UNCHECKED_ROUTE = "/unchecked"
+	MODERATOR_APPROVE_ROUTE   = "/approve"
+	MODERATOR_REJECT_ROUTE    = "/reject"
 
 	MODERATOR_ARTICLE_ROUTE = "/article"
 )
@@ -9,7 +9,12 @@ const (
 
 	// Revoke token
 	OAUTH2_REVOKE_TOKEN_ROUTE = "https://oauth2.googleapis.com/revoke?token="
+)
+
+const (
+	// VK API: получение информации о пользователе
+	VK_USERS_GET_ROUTE = "/users.get"
 
-	// Refresh token
-	OAUTH2_REFRESH_TOKEN_ROUTE = "https://oauth2.googleapis.com/token?client_id="
+	// Версия VK API
+	VK_API_VERSION = "5.131"
 )
@@ -5,4 +5,5 @@ const (
 	SERVICE_MAIN       = "/main"
 	SERVICE_VERIFY     = "/verify"
 	SERVICE_EMAIL_SEND = "/email/send"
+	SERVICE_TOKEN      = "/token"
 )
@@ -0,0 +1,5 @@
+package route
+
+const (
+	SUB_ENTITY_MAIN_ROUTE = "/sub-entity"
+)
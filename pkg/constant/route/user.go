@@ -5,5 +5,31 @@ const (
 	USER_ARTICLE_ROUTE      = "/article"
 	USER_PROFILE_ROUTE      = "/profile"
 	USER_CHECK_ACCESS_ROUTE = "/access/check"
+	USER_EXPLAIN_ACCESS     = "/access/explain"
 	USER_ROLES              = "/role"
+	USER_SESSIONS_ROUTE     = "/sessions"
+
+	USER_SESSIONS_REVOKE_ROUTE       = "/revoke"
+	USER_SESSIONS_REVOKE_OTHER_ROUTE = "/revoke/other"
+
+	USER_2FA_ROUTE          = "/2fa"
+	USER_2FA_ENROLL_ROUTE   = "/enroll"
+	USER_2FA_CONFIRM_ROUTE  = "/confirm"
+	USER_2FA_DISABLE_ROUTE  = "/disable"
+	USER_2FA_RECOVERY_ROUTE = "/recovery/regenerate"
+
+	USER_IDENTITIES_ROUTE        = "/identities"
+	USER_IDENTITIES_LINK_URL     = "/link/url"
+	USER_IDENTITIES_LINK_ROUTE   = "/link"
+	USER_IDENTITIES_UNLINK_ROUTE = "/unlink"
+	USER_PASSWORD_SET_ROUTE      = "/password/set"
+
+	USER_EMAIL_ROUTE         = "/email"
+	USER_EMAIL_CHANGE_ROUTE  = "/change"
+	USER_EMAIL_CONFIRM_ROUTE = "/confirm"
+
+	USER_ACCOUNT_ROUTE               = "/account"
+	USER_ACCOUNT_EXPORT_ROUTE        = "/export"
+	USER_ACCOUNT_DELETE_CANCEL_ROUTE = "/delete/cancel"
+	USER_ACCOUNT_DELETE_GET_ROUTE    = "/delete/get"
 )
@@ -0,0 +1,8 @@
+package status
+
+/* Статусы модерации статей */
+const (
+	ARTICLE_UNCHECKED = "unchecked"
+	ARTICLE_APPROVED  = "approved"
+	ARTICLE_REJECTED  = "rejected"
+)
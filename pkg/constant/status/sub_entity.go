@@ -0,0 +1,8 @@
+package status
+
+/* Статусы помещений объекта */
+const (
+	SUB_ENTITY_AVAILABLE = "available"
+	SUB_ENTITY_RESERVED  = "reserved"
+	SUB_ENTITY_RENTED    = "rented"
+)
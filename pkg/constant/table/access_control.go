@@ -6,4 +6,5 @@ const (
 	AC_TYPES_OBJECTS = "ac_types_objects"
 	AC_OBJECTS       = "ac_objects"
 	AC_RULES         = "ac_rules"
+	AC_ROLES_2FA     = "ac_roles_two_factor"
 )
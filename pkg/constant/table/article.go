@@ -0,0 +1,7 @@
+package table
+
+const (
+	A_ARTICLES       = "a_articles"
+	A_FILES          = "a_files"
+	A_ARTICLES_FILES = "a_articles_files"
+)
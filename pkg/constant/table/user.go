@@ -10,4 +10,15 @@ const (
 	U_AUTH_TYPES       = "u_auth_types"
 	U_USERS_AUTH_TYPES = "u_users_auth_types"
 	U_BANS             = "u_bans"
+	U_SESSIONS         = "u_sessions"
+	U_TWO_FACTOR       = "u_two_factor"
+	U_RECOVERY_CODES   = "u_recovery_codes"
+	U_TWO_FACTOR_CHALL = "u_two_factor_challenges"
+	U_AUTH_THROTTLE    = "u_auth_throttle"
+	U_SIGNING_KEYS     = "u_signing_keys"
+	U_SERVICE_CLIENTS  = "u_service_clients"
+	U_REVOKED_TOKENS   = "u_revoked_tokens"
+	U_ACCOUNT_DELETION = "u_account_deletions"
+	U_EMAIL_CHANGES    = "u_email_changes"
+	U_AUTH_STATES      = "u_auth_states"
 )
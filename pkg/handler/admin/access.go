@@ -0,0 +1,44 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary ExplainAccess
+// @Tags admin
+// @Description Объяснение решения о доступе пользователя к объекту: цепочка ролей, правила для объекта и его родительских ресурсов, недостающее правило
+// @ID admin-access-explain
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.AccessExplainUserModel true "credentials"
+// @Success 200 {object} rbacModel.AccessExplainResultModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/access/explain [post]
+func (h *AdminHandler) explainAccess(c *gin.Context) {
+	var input rbacModel.AccessExplainUserModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Access.ExplainForUser(domainId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
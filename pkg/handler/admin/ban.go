@@ -0,0 +1,111 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary BanUser
+// @Tags admin
+// @Description Блокировка пользователя (временная при указании expires_at, иначе бессрочная) с отзывом его токенов
+// @ID admin-user-ban
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.BanCreateModel true "credentials"
+// @Success 200 {object} userModel.BanModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/user/ban [post]
+func (h *AdminHandler) banUser(c *gin.Context) {
+	// Получение пользовательских данных обработанных с помощью цепочки middleware
+	userId, userUuid, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var input userModel.BanCreateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Ban.BanUser(userModel.UserIdentityModel{
+		UserId:   userId,
+		UserUuid: userUuid,
+		DomainId: domainId,
+	}, input)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UnbanUser
+// @Tags admin
+// @Description Снятие всех действующих блокировок пользователя
+// @ID admin-user-unban
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.BanUserUuidModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/user/unban [post]
+func (h *AdminHandler) unbanUser(c *gin.Context) {
+	var input userModel.BanUserUuidModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Ban.UnbanUser(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary GetBans
+// @Tags admin
+// @Description Получение списка блокировок пользователей
+// @ID admin-user-ban-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.BanCountModel true "credentials"
+// @Success 200 {object} userModel.BanAnyCountModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/user/ban/get/all [post]
+func (h *AdminHandler) getBans(c *gin.Context) {
+	var input userModel.BanCountModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Ban.GetBans(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -4,6 +4,7 @@ import (
 	_ "main-server/docs"
 
 	middlewareConstant "main-server/pkg/constant/middleware"
+	roleConstant "main-server/pkg/constant/role"
 	"main-server/pkg/constant/route"
 	service "main-server/pkg/service"
 
@@ -28,6 +29,7 @@ func NewAdminHandler(root *gin.Engine, services *service.Service) *AdminHandler
 func (h *AdminHandler) InitRoutes(
 	middleware *map[string]func(c *gin.Context),
 	hasRole func(role string) func(c *gin.Context),
+	hasRoles func(exp string, roles ...string) func(c *gin.Context),
 ) {
 	// route.ADMIN_MAIN_ROUTE, (*middleware)[middlewareConstant.MN_UI], hasRole(roleConstant.ROLE_ADMIN)
 	// URL: /admin
@@ -38,6 +40,118 @@ func (h *AdminHandler) InitRoutes(
 		{
 			// URL: /admin/user/get/all
 			user.POST(route.GET_ALL_ROUTE, h.getAllUsers)
+
+			// URL: /admin/user/ban
+			user.POST(
+				route.ADMIN_BAN,
+				hasRoles("OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+				h.banUser,
+			)
+
+			// URL: /admin/user/unban
+			user.POST(
+				route.ADMIN_UNBAN,
+				hasRoles("OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+				h.unbanUser,
+			)
+
+			// URL: /admin/user/ban/get/all
+			user.POST(
+				route.ADMIN_BAN+route.GET_ALL_ROUTE,
+				hasRoles("OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+				h.getBans,
+			)
+		}
+
+		// URL: /admin/2fa/roles
+		twoFactor := admin.Group(
+			route.ADMIN_2FA+route.ADMIN_2FA_ROLES,
+			hasRoles("OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+		)
+		{
+			// URL: /admin/2fa/roles/get/all
+			twoFactor.POST(route.GET_ALL_ROUTE, h.getTwoFactorRoles)
+
+			// URL: /admin/2fa/roles/add
+			twoFactor.POST(route.ADD_ROUTE, h.addTwoFactorRole)
+
+			// URL: /admin/2fa/roles/delete
+			twoFactor.POST(route.DELETE_ROUTE, h.deleteTwoFactorRole)
+		}
+
+		// URL: /admin/service/clients
+		serviceClients := admin.Group(
+			route.ADMIN_SERVICE,
+			hasRoles("OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+		)
+		{
+			// URL: /admin/service/clients/create
+			serviceClients.POST(route.CREATE_ROUTE, h.createServiceClient)
+
+			// URL: /admin/service/clients/get/all
+			serviceClients.POST(route.GET_ALL_ROUTE, h.getServiceClients)
+
+			// URL: /admin/service/clients/delete
+			serviceClients.POST(route.DELETE_ROUTE, h.deleteServiceClient)
+		}
+
+		// URL: /admin/access/explain
+		admin.POST(
+			route.ADMIN_ACCESS,
+			hasRoles("OR", roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+			h.explainAccess,
+		)
+
+		// URL: /admin/rbac
+		rbac := admin.Group(route.ADMIN_RBAC, hasRole(roleConstant.ROLE_SUPER_ADMIN))
+		{
+			// URL: /admin/rbac/domains
+			domains := rbac.Group(route.ADMIN_DOMAINS)
+			{
+				// URL: /admin/rbac/domains/get/all
+				domains.POST(route.GET_ALL_ROUTE, h.getDomains)
+
+				// URL: /admin/rbac/domains/create
+				domains.POST(route.CREATE_ROUTE, h.createDomain)
+
+				// URL: /admin/rbac/domains/update
+				domains.POST(route.UPDATE_ROUTE, h.updateDomain)
+
+				// URL: /admin/rbac/domains/delete
+				domains.POST(route.DELETE_ROUTE, h.deleteDomain)
+			}
+
+			// URL: /admin/rbac/roles
+			roles := rbac.Group(route.ADMIN_ROLES)
+			{
+				// URL: /admin/rbac/roles/get/all
+				roles.POST(route.GET_ALL_ROUTE, h.getRoles)
+
+				// URL: /admin/rbac/roles/create
+				roles.POST(route.CREATE_ROUTE, h.createRole)
+
+				// URL: /admin/rbac/roles/update
+				roles.POST(route.UPDATE_ROUTE, h.updateRole)
+
+				// URL: /admin/rbac/roles/delete
+				roles.POST(route.DELETE_ROUTE, h.deleteRole)
+			}
+
+			// URL: /admin/rbac/types
+			types := rbac.Group(route.ADMIN_TYPES)
+			{
+				// URL: /admin/rbac/types/get/all
+				types.POST(route.GET_ALL_ROUTE, h.getTypesObjects)
+
+				// URL: /admin/rbac/types/create
+				types.POST(route.CREATE_ROUTE, h.createTypeObject)
+
+				// URL: /admin/rbac/types/update
+				types.POST(route.UPDATE_ROUTE, h.updateTypeObject)
+
+				// URL: /admin/rbac/types/delete
+				types.POST(route.DELETE_ROUTE, h.deleteTypeObject)
+			}
 		}
 
 		// URL: /admin/company
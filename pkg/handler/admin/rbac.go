@@ -0,0 +1,368 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	rbacModel "main-server/pkg/model/rbac"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetDomains
+// @Tags admin
+// @Description Получение списка доменов
+// @ID admin-rbac-domains-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} rbacModel.DomainsModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/domains/get/all [post]
+func (h *AdminHandler) getDomains(c *gin.Context) {
+	data, err := h.services.Domain.GetDomains()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary CreateDomain
+// @Tags admin
+// @Description Создание нового домена
+// @ID admin-rbac-domains-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.DomainCreateModel true "credentials"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/domains/create [post]
+func (h *AdminHandler) createDomain(c *gin.Context) {
+	var input rbacModel.DomainCreateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Domain.CreateDomain(userId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UpdateDomain
+// @Tags admin
+// @Description Изменение значения или описания домена (значение основного домена приложения не изменяется)
+// @ID admin-rbac-domains-update
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.DomainUpdateModel true "credentials"
+// @Success 200 {object} rbacModel.DomainModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/domains/update [post]
+func (h *AdminHandler) updateDomain(c *gin.Context) {
+	var input rbacModel.DomainUpdateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Domain.UpdateDomain(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteDomain
+// @Tags admin
+// @Description Удаление домена вместе с его ролями. Домен, используемый в правилах управления доступом, удалить нельзя
+// @ID admin-rbac-domains-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.DomainUuidModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/domains/delete [post]
+func (h *AdminHandler) deleteDomain(c *gin.Context) {
+	var input rbacModel.DomainUuidModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Domain.DeleteDomain(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary GetRoles
+// @Tags admin
+// @Description Получение списка ролей домена
+// @ID admin-rbac-roles-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.DomainUuidModel true "credentials"
+// @Success 200 {object} rbacModel.RolesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/roles/get/all [post]
+func (h *AdminHandler) getRoles(c *gin.Context) {
+	var input rbacModel.DomainUuidModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.GetRoles(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary CreateRole
+// @Tags admin
+// @Description Создание новой роли в домене
+// @ID admin-rbac-roles-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.RoleCreateModel true "credentials"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/roles/create [post]
+func (h *AdminHandler) createRole(c *gin.Context) {
+	var input rbacModel.RoleCreateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.CreateRole(userId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UpdateRole
+// @Tags admin
+// @Description Изменение значения или описания роли (у системных ролей изменяется только описание)
+// @ID admin-rbac-roles-update
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.RoleUpdateModel true "credentials"
+// @Success 200 {object} rbacModel.RoleModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/roles/update [post]
+func (h *AdminHandler) updateRole(c *gin.Context) {
+	var input rbacModel.RoleUpdateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.UpdateRole(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteRole
+// @Tags admin
+// @Description Удаление роли. Роль, назначенную пользователям (правила группировки casbin), удалить нельзя
+// @ID admin-rbac-roles-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.RoleUuidModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/roles/delete [post]
+func (h *AdminHandler) deleteRole(c *gin.Context) {
+	var input rbacModel.RoleUuidModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Role.DeleteRole(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary GetTypesObjects
+// @Tags admin
+// @Description Получение списка типов объектов
+// @ID admin-rbac-types-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} rbacModel.TypeObjectsModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/types/get/all [post]
+func (h *AdminHandler) getTypesObjects(c *gin.Context) {
+	data, err := h.services.TypeObject.GetTypes()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary CreateTypeObject
+// @Tags admin
+// @Description Регистрация нового типа объектов с таблицей, в которой они хранятся (table_name)
+// @ID admin-rbac-types-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.TypeObjectCreateModel true "credentials"
+// @Success 200 {object} rbacModel.TypeObjectDbModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/types/create [post]
+func (h *AdminHandler) createTypeObject(c *gin.Context) {
+	var input rbacModel.TypeObjectCreateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TypeObject.CreateType(userId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UpdateTypeObject
+// @Tags admin
+// @Description Изменение описания или таблицы типа объектов (таблица системных типов не изменяется)
+// @ID admin-rbac-types-update
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.TypeObjectUpdateModel true "credentials"
+// @Success 200 {object} rbacModel.TypeObjectDbModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/types/update [post]
+func (h *AdminHandler) updateTypeObject(c *gin.Context) {
+	var input rbacModel.TypeObjectUpdateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.TypeObject.UpdateType(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteTypeObject
+// @Tags admin
+// @Description Удаление типа объектов, для которого не создано ни одного объекта
+// @ID admin-rbac-types-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.TypeObjectValueModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/rbac/types/delete [post]
+func (h *AdminHandler) deleteTypeObject(c *gin.Context) {
+	var input rbacModel.TypeObjectValueModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.TypeObject.DeleteType(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	serviceModel "main-server/pkg/model/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary CreateServiceClient
+// @Tags admin
+// @Description Регистрация сервисного клиента. Секрет клиента возвращается только в этом ответе
+// @ID admin-service-clients-create
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body serviceModel.ServiceClientCreateModel true "credentials"
+// @Success 200 {object} serviceModel.ServiceClientSecretModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/service/clients/create [post]
+func (h *AdminHandler) createServiceClient(c *gin.Context) {
+	var input serviceModel.ServiceClientCreateModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.ServiceClient.CreateClient(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetServiceClients
+// @Tags admin
+// @Description Получение списка сервисных клиентов
+// @ID admin-service-clients-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} serviceModel.ServiceClientsModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/service/clients/get/all [post]
+func (h *AdminHandler) getServiceClients(c *gin.Context) {
+	data, err := h.services.ServiceClient.GetClients()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteServiceClient
+// @Tags admin
+// @Description Отключение сервисного клиента: новые токены не выдаются, ранее выданные перестают приниматься
+// @ID admin-service-clients-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body serviceModel.ServiceClientIdModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/service/clients/delete [post]
+func (h *AdminHandler) deleteServiceClient(c *gin.Context) {
+	var input serviceModel.ServiceClientIdModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.ServiceClient.RevokeClient(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
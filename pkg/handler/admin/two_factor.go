@@ -0,0 +1,122 @@
+package admin
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetTwoFactorRoles
+// @Tags admin
+// @Description Получение ролей текущего домена, для которых двухфакторная аутентификация обязательна
+// @ID admin-2fa-roles-get-all
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.TwoFactorRolesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/2fa/roles/get/all [post]
+func (h *AdminHandler) getTwoFactorRoles(c *gin.Context) {
+	_, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.GetRequiredRoles(domainId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary AddTwoFactorRole
+// @Tags admin
+// @Description Включение обязательной двухфакторной аутентификации для роли
+// @ID admin-2fa-roles-add
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.TwoFactorRoleValueModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/2fa/roles/add [post]
+func (h *AdminHandler) addTwoFactorRole(c *gin.Context) {
+	var input userModel.TwoFactorRoleValueModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, userUuid, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.RequireForRole(userModel.UserIdentityModel{
+		UserId:   userId,
+		UserUuid: userUuid,
+		DomainId: domainId,
+	}, input)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary DeleteTwoFactorRole
+// @Tags admin
+// @Description Отключение обязательной двухфакторной аутентификации для роли
+// @ID admin-2fa-roles-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.TwoFactorRoleValueModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /admin/2fa/roles/delete [post]
+func (h *AdminHandler) deleteTwoFactorRole(c *gin.Context) {
+	var input userModel.TwoFactorRoleValueModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, userUuid, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.UnrequireForRole(userModel.UserIdentityModel{
+		UserId:   userId,
+		UserUuid: userUuid,
+		DomainId: domainId,
+	}, input)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
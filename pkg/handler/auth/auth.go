@@ -2,6 +2,7 @@ package auth
 
 import (
 	config "main-server/config"
+	authConstant "main-server/pkg/constant/auth"
 	middlewareConstant "main-server/pkg/constant/middleware"
 	pathConstant "main-server/pkg/constant/path"
 	utilContext "main-server/pkg/handler/util"
@@ -34,7 +35,7 @@ func (h *AuthHandler) signUp(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Authorization.CreateUser(input)
+	data, err := h.services.Authorization.CreateUser(input, utilContext.GetSessionInfo(c))
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -92,15 +93,17 @@ func (h *AuthHandler) uploadProfileImage(c *gin.Context) {
 
 // @Summary Авторизация пользователя
 // @Tags API для авторизации и регистрации пользователя
-// @Description Авторизация пользователя
+// @Description Авторизация пользователя. При включённой 2FA вместо токена возвращается промежуточный токен для /auth/sign-in/2fa
 // @ID auth-sign-in
 // @Accept  json
 // @Produce  json
 // @Param input body userModel.UserLoginModel true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 202 {object} userModel.TwoFactorChallengeModel "data"
 // @Failure 400,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
+// @Failure 429 {object} ResponseMessage
 // @Router /auth/sign-in [post]
 func (h *AuthHandler) signIn(c *gin.Context) {
 	var input userModel.UserLoginModel
@@ -110,9 +113,15 @@ func (h *AuthHandler) signIn(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Authorization.LoginUser(input)
+	data, err := h.services.Authorization.LoginUser(input, utilContext.GetSessionInfo(c))
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		newAuthErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Требуется второй шаг авторизации (TOTP)
+	if data.Challenge != nil {
+		c.JSON(http.StatusAccepted, data.Challenge)
 		return
 	}
 
@@ -126,32 +135,154 @@ func (h *AuthHandler) signIn(c *gin.Context) {
 	})
 }
 
-// @Summary Авторизация пользователя через VK (no work)
+// @Summary Авторизация пользователя через VK OAuth2
 // @Tags API для авторизации и регистрации пользователя
-// @Description Авторизация пользователя через VK (no work)
+// @Description Авторизация (или регистрация) пользователя по коду авторизации VK
 // @ID auth-sign-in-vk
 // @Accept  json
 // @Produce  json
-// @Param input body userModel.UserLoginModel true "credentials"
+// @Param input body userModel.UserLoginOAuth2Model true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 202 {object} userModel.TwoFactorChallengeModel "data"
 // @Failure 400,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
 // @Router /auth/sign-in/vk [post]
 func (h *AuthHandler) signInVK(c *gin.Context) {
-	var input userModel.UserLoginModel
+	var input userModel.UserLoginOAuth2Model
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	h.loginUserOAuth2(c, authConstant.AUTH_TYPE_VK, input.Code, input.State)
+}
+
+// @Summary Обработка перенаправления от VK OAuth2
+// @Tags API для авторизации и регистрации пользователя
+// @Description Обработка перенаправления от VK OAuth2 (код авторизации передаётся в параметре code)
+// @ID auth-sign-in-vk-callback
+// @Produce  json
+// @Param code query string true "Код авторизации VK"
+// @Param state query string true "Параметр state, выданный при формировании адреса авторизации"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 202 {object} userModel.TwoFactorChallengeModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/sign-in/vk/callback [get]
+func (h *AuthHandler) signInVKCallback(c *gin.Context) {
+	if errorDescription := c.Query("error_description"); errorDescription != "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, errorDescription)
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Ошибка: не передан код авторизации VK!")
+		return
+	}
+
+	h.loginUserOAuth2(c, authConstant.AUTH_TYPE_VK, code, c.Query("state"))
+}
+
+// @Summary Авторизация пользователя через внешний провайдер
+// @Tags API для авторизации и регистрации пользователя
+// @Description Авторизация (или регистрация) пользователя по коду авторизации зарегистрированного провайдера (google, vk, OpenID Connect)
+// @ID auth-sign-in-provider
+// @Accept  json
+// @Produce  json
+// @Param provider path string true "Название провайдера"
+// @Param input body userModel.UserLoginOAuth2Model true "credentials"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 202 {object} userModel.TwoFactorChallengeModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/sign-in/provider/{provider} [post]
+func (h *AuthHandler) signInProvider(c *gin.Context) {
+	var input userModel.UserLoginOAuth2Model
 
 	if err := c.BindJSON(&input); err != nil {
 		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
 		return
 	}
 
-	data, err := h.services.Authorization.LoginUser(input)
+	h.loginUserOAuth2(c, c.Param("provider"), input.Code, input.State)
+}
+
+// @Summary Обработка перенаправления от внешнего провайдера
+// @Tags API для авторизации и регистрации пользователя
+// @Description Обработка перенаправления от внешнего провайдера (код авторизации передаётся в параметре code)
+// @ID auth-sign-in-provider-callback
+// @Produce  json
+// @Param provider path string true "Название провайдера"
+// @Param code query string true "Код авторизации"
+// @Param state query string true "Параметр state, выданный при формировании адреса авторизации"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 202 {object} userModel.TwoFactorChallengeModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/sign-in/provider/{provider}/callback [get]
+func (h *AuthHandler) signInProviderCallback(c *gin.Context) {
+	if errorDescription := c.Query("error_description"); errorDescription != "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, errorDescription)
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Ошибка: не передан код авторизации!")
+		return
+	}
+
+	h.loginUserOAuth2(c, c.Param("provider"), code, c.Query("state"))
+}
+
+// @Summary Адрес авторизации у внешнего провайдера
+// @Tags API для авторизации и регистрации пользователя
+// @Description Формирование адреса страницы авторизации провайдера (с параметрами state, PKCE и nonce)
+// @ID auth-sign-in-provider-url
+// @Produce  json
+// @Param provider path string true "Название провайдера"
+// @Success 200 {object} userModel.OAuth2URLModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/sign-in/provider/{provider}/url [post]
+func (h *AuthHandler) signInProviderURL(c *gin.Context) {
+	url, err := h.services.Authorization.GetOAuth2URL(c.Param("provider"))
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, userModel.OAuth2URLModel{
+		Url: url,
+	})
+}
+
+/* Авторизация пользователя по коду внешнего провайдера и установка токена обновления */
+func (h *AuthHandler) loginUserOAuth2(c *gin.Context, provider, code, state string) {
+	data, err := h.services.Authorization.LoginUserOAuth2(provider, code, state, utilContext.GetSessionInfo(c))
+	h.oauth2LoginResponse(c, data, err)
+}
+
+/* Ответ на авторизацию через внешний провайдер: токен доступа или промежуточный токен второго шага */
+func (h *AuthHandler) oauth2LoginResponse(c *gin.Context, data userModel.UserAuthDataModel, err error) {
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	// Требуется второй шаг авторизации (TOTP)
+	if data.Challenge != nil {
+		c.JSON(http.StatusAccepted, data.Challenge)
+		return
+	}
+
 	// Добавление токена обновления в http only cookie
 	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
 		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
@@ -170,6 +301,7 @@ func (h *AuthHandler) signInVK(c *gin.Context) {
 // @Produce  json
 // @Param input body userModel.GoogleOAuth2Code true "credentials"
 // @Success 200 {object} userModel.TokenAccessModel "data"
+// @Success 202 {object} userModel.TwoFactorChallengeModel "data"
 // @Failure 400,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
@@ -187,20 +319,9 @@ func (h *AuthHandler) signInOAuth2(c *gin.Context) {
 	_, _ = google_oauth2.RevokeToken(token.AccessToken)
 	return*/
 
-	data, err := h.services.Authorization.LoginUserOAuth2(input.Code)
-	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	// Добавление токена обновления в http only cookie
-	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
-		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
-	c.SetSameSite(config.HTTPSameSite)
-
-	c.JSON(http.StatusOK, userModel.TokenAccessModel{
-		AccessToken: data.AccessToken,
-	})
+	// Код получен клиентом через Google SDK - авторизационный запрос (state) сервером не формировался
+	data, err := h.services.Authorization.LoginUserOAuth2SDK(authConstant.AUTH_TYPE_GOOGLE, input.Code, utilContext.GetSessionInfo(c))
+	h.oauth2LoginResponse(c, data, err)
 }
 
 // @Summary Обновление токена доступа
@@ -236,7 +357,7 @@ func (h *AuthHandler) refresh(c *gin.Context) {
 		RefreshToken:  refreshToken,
 		AuthTypeValue: authTypeValue.(string),
 		TokenApi:      tokenApi.(*string),
-	}, refreshToken)
+	}, refreshToken, utilContext.GetSessionInfo(c))
 
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
@@ -306,25 +427,60 @@ func (h *AuthHandler) logout(c *gin.Context) {
 
 // @Summary Активация аккаунта по почте
 // @Tags API для авторизации и регистрации пользователя
-// @Description Активация аккаунта по почте
+// @Description Активация аккаунта по почте (HTML-страница с результатом активации)
 // @ID auth-activate
+// @Produce  html
+// @Param link path string true "Ссылка активации"
+// @Success 200 {string} string "HTML"
+// @Failure 400 {string} string "HTML"
+// @Router /auth/activate/{link} [get]
+func (h *AuthHandler) activate(c *gin.Context) {
+	_, err := h.services.Activate(c.Params.ByName("link"))
+
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "account_activate.html", gin.H{
+			"title":   "Подтверждение аккаунта",
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "account_activate.html", gin.H{
+		"title":   "Подтверждение аккаунта",
+		"success": true,
+	})
+}
+
+// @Summary Повторная отправка письма для активации аккаунта
+// @Tags API для авторизации и регистрации пользователя
+// @Description Повторная отправка письма с новой ссылкой активации (не чаще одного раза в интервал activation.resend_cooldown; ответ не зависит от существования аккаунта)
+// @ID auth-activate-resend
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} LogoutOutputModel "data"
+// @Param input body userModel.UserEmailModel true "credentials"
+// @Success 200 {object} ResponseMessage "data"
 // @Failure 400,404 {object} ResponseMessage
+// @Failure 429 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
-// @Router /auth/activate [get]
-func (h *AuthHandler) activate(c *gin.Context) {
-	_, err := h.services.Activate(c.Params.ByName("link"))
+// @Router /auth/activate/resend [post]
+func (h *AuthHandler) resendActivation(c *gin.Context) {
+	var input userModel.UserEmailModel
+
+	if err := c.BindJSON(&input); err != nil || input.Email == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
 
+	_, err := h.services.Authorization.ResendActivation(input.Email, c.ClientIP())
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		newAuthErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.HTML(http.StatusOK, "account_activate.html", gin.H{
-		"title": "Подтверждение аккаунта",
+	c.JSON(http.StatusOK, httpModel.ResponseMessage{
+		Message: "Если аккаунт с данным email-адресом существует и не активирован, на него было отправлено письмо для активации",
 	})
 }
 
@@ -339,6 +495,7 @@ func (h *AuthHandler) activate(c *gin.Context) {
 // @Failure 400,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
+// @Failure 429 {object} ResponseMessage
 // @Router /auth/recovery/password [post]
 func (h *AuthHandler) recoveryPassword(c *gin.Context) {
 	var input userModel.UserEmailModel
@@ -348,9 +505,9 @@ func (h *AuthHandler) recoveryPassword(c *gin.Context) {
 		return
 	}
 
-	_, err := h.services.Authorization.RecoveryPassword(input.Email)
+	_, err := h.services.Authorization.RecoveryPassword(input.Email, c.ClientIP())
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newAuthErrorResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -370,6 +527,7 @@ func (h *AuthHandler) recoveryPassword(c *gin.Context) {
 // @Failure 400,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
+// @Failure 429 {object} ResponseMessage
 // @Router /auth/reset/password [post]
 func (h *AuthHandler) resetPassword(c *gin.Context) {
 	var input userModel.ResetPasswordModel
@@ -379,9 +537,9 @@ func (h *AuthHandler) resetPassword(c *gin.Context) {
 		return
 	}
 
-	_, err := h.services.Authorization.ResetPassword(input)
+	_, err := h.services.Authorization.ResetPassword(input, c.ClientIP())
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newAuthErrorResponse(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -389,3 +547,34 @@ func (h *AuthHandler) resetPassword(c *gin.Context) {
 		Message: "Пароль был успешно изменён!",
 	})
 }
+
+// @Summary Отмена смены email-адреса
+// @Tags API для авторизации и регистрации пользователя
+// @Description Отмена смены email-адреса по ссылке из письма, отправленного на прежний адрес (вход в аккаунт не требуется)
+// @ID auth-email-change-cancel
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.EmailChangeTokenModel true "credentials"
+// @Success 200 {object} ResponseMessage "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/email/change/cancel [post]
+func (h *AuthHandler) cancelEmailChange(c *gin.Context) {
+	var input userModel.EmailChangeTokenModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	_, err := h.services.EmailChange.CancelEmailChange(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseMessage{
+		Message: "Смена email-адреса отменена",
+	})
+}
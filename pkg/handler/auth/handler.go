@@ -1,10 +1,15 @@
 package auth
 
 import (
+	"errors"
 	_ "main-server/docs"
+	"net/http"
+	"strconv"
 
+	authConstant "main-server/pkg/constant/auth"
 	middlewareConstant "main-server/pkg/constant/middleware"
 	"main-server/pkg/constant/route"
+	utilContext "main-server/pkg/handler/util"
 	service "main-server/pkg/service"
 
 	"github.com/gin-gonic/gin"
@@ -27,7 +32,11 @@ func NewAuthHandler(root *gin.Engine, services *service.Service) *AuthHandler {
 /* Инициализация маршрутов для авторизации пользователя */
 func (h *AuthHandler) InitRoutes(
 	middleware *map[string]func(c *gin.Context),
+	hasScope func(scope string) func(c *gin.Context),
 ) {
+	// URL: /.well-known/jwks.json
+	h.rootHandler.GET(route.AUTH_JWKS_ROUTE, h.getJWKS)
+
 	// URL: /auth
 	auth := h.rootHandler.Group(route.AUTH_MAIN_ROUTE)
 	{
@@ -37,12 +46,36 @@ func (h *AuthHandler) InitRoutes(
 		// URL: /auth/sign-in
 		auth.POST(route.AUTH_SIGN_IN_ROUTE, h.signIn)
 
+		// URL: /auth/sign-in/2fa
+		auth.POST(route.AUTH_SIGN_IN_2FA_ROUTE, h.signInTwoFactor)
+
+		// URL: /auth/sign-in/2fa/setup
+		auth.POST(route.AUTH_SIGN_IN_2FA_SETUP_ROUTE, h.signInTwoFactorSetup)
+
 		// URL: /auth/sign-in/oauth2
 		auth.POST(route.AUTH_SIGN_IN_GOOGLE_ROUTE, h.signInOAuth2)
 
+		// URL: /auth/sign-in/vk
+		auth.POST(route.AUTH_SIGN_IN_VK_ROUTE, h.signInVK)
+
+		// URL: /auth/sign-in/vk/callback
+		auth.GET(route.AUTH_SIGN_IN_VK_CALLBACK_ROUTE, h.signInVKCallback)
+
+		// URL: /auth/sign-in/provider/:provider
+		auth.POST(route.AUTH_SIGN_IN_PROVIDER_ROUTE, h.signInProvider)
+
+		// URL: /auth/sign-in/provider/:provider/callback
+		auth.GET(route.AUTH_SIGN_IN_PROVIDER_CALLBACK_ROUTE, h.signInProviderCallback)
+
+		// URL: /auth/sign-in/provider/:provider/url
+		auth.POST(route.AUTH_SIGN_IN_PROVIDER_URL_ROUTE, h.signInProviderURL)
+
 		// URL: /auth/activate/:link
 		auth.GET(route.AUTH_ACTIVATE_ROUTE, h.activate)
 
+		// URL: /auth/activate/resend
+		auth.POST(route.AUTH_ACTIVATE_RESEND, h.resendActivation)
+
 		// URL: /auth/refresh
 		auth.POST(route.AUTH_REFRESH_TOKEN_ROUTE, (*middleware)[middlewareConstant.MN_UI_LOGOUT], h.refresh)
 
@@ -52,10 +85,30 @@ func (h *AuthHandler) InitRoutes(
 		// URL: /auth/sign-up/upload/image
 		auth.POST(route.AUTH_UPLOAD_PROFILE_IMAGE, (*middleware)[middlewareConstant.MN_UI], h.uploadProfileImage)
 
+		// URL: /auth/introspect
+		auth.POST(route.AUTH_INTROSPECT_ROUTE, hasScope(authConstant.SCOPE_TOKEN_INTROSPECT), h.introspect)
+
+		// URL: /auth/revoke
+		auth.POST(route.AUTH_REVOKE_ROUTE, hasScope(authConstant.SCOPE_TOKEN_REVOKE), h.revoke)
+
 		// URL: /auth/recovery/password
 		auth.POST(route.AUTH_RECOVERY_PASSWORD, h.recoveryPassword)
 
 		// URL: /auth/reset/password
 		auth.POST(route.AUTH_RESET_PASSWORD, h.resetPassword)
+
+		// URL: /auth/email/change/cancel
+		auth.POST(route.AUTH_EMAIL_CHANGE_CANCEL, h.cancelEmailChange)
 	}
 }
+
+/* Ответ с ошибкой авторизации: при превышении числа попыток - 429 с заголовком Retry-After */
+func newAuthErrorResponse(c *gin.Context, statusCode int, err error) {
+	var throttleErr *service.TooManyAttemptsError
+	if errors.As(err, &throttleErr) {
+		c.Header("Retry-After", strconv.Itoa(throttleErr.Seconds()))
+		statusCode = http.StatusTooManyRequests
+	}
+
+	utilContext.NewErrorResponse(c, statusCode, err.Error())
+}
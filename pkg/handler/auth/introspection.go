@@ -0,0 +1,78 @@
+package auth
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Интроспекция токена доступа
+// @Tags API для авторизации и регистрации пользователя
+// @Description Проверка активности токена доступа пользователя (RFC 7662). Требуется токен сервисного клиента с областью доступа token:introspect
+// @ID auth-introspect
+// @Accept  x-www-form-urlencoded,json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа сервисного клиента" example(Bearer access_token)
+// @Param input body userModel.TokenIntrospectInputModel true "Токен для проверки"
+// @Success 200 {object} userModel.TokenIntrospectionModel "data"
+// @Failure 400,401,403 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/introspect [post]
+func (h *AuthHandler) introspect(c *gin.Context) {
+	var input userModel.TokenIntrospectInputModel
+
+	if err := c.ShouldBind(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	domainsId, err := utilContext.GetContextDomainId(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Introspection.Introspect(input, domainsId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary Отзыв токена
+// @Tags API для авторизации и регистрации пользователя
+// @Description Отзыв токена доступа или обновления (RFC 7009). Неизвестные токены игнорируются. Требуется токен сервисного клиента с областью доступа token:revoke
+// @ID auth-revoke
+// @Accept  x-www-form-urlencoded,json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа сервисного клиента" example(Bearer access_token)
+// @Param input body userModel.TokenIntrospectInputModel true "Токен для отзыва"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,401,403 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/revoke [post]
+func (h *AuthHandler) revoke(c *gin.Context) {
+	var input userModel.TokenIntrospectInputModel
+
+	if err := c.ShouldBind(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.services.Introspection.Revoke(input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: true,
+	})
+}
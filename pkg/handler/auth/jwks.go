@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetJWKS
+// @Tags auth
+// @Description Набор открытых ключей (JWKS) для локальной проверки токенов доступа сервисами. Ключ подписи указывается в заголовке kid токена
+// @ID auth-jwks
+// @Produce  json
+// @Success 200 {object} userModel.JWKSModel "data"
+// @Failure default {object} ResponseMessage
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.services.Token.GetJWKS())
+}
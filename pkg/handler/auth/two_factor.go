@@ -0,0 +1,77 @@
+package auth
+
+import (
+	config "main-server/config"
+	utilContext "main-server/pkg/handler/util"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// @Summary Второй шаг авторизации пользователя (2FA)
+// @Tags API для авторизации и регистрации пользователя
+// @Description Обмен промежуточного токена и кода TOTP (или кода восстановления) на токен доступа
+// @ID auth-sign-in-2fa
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.TwoFactorSignInModel true "credentials"
+// @Success 200 {object} userModel.TokenAccessModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Failure 429 {object} ResponseMessage
+// @Router /auth/sign-in/2fa [post]
+func (h *AuthHandler) signInTwoFactor(c *gin.Context) {
+	var input userModel.TwoFactorSignInModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.TwoFactor.SignIn(input, utilContext.GetSessionInfo(c))
+	if err != nil {
+		newAuthErrorResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Добавление токена обновления в http only cookie
+	c.SetCookie(viper.GetString("environment.refresh_token_key"), data.RefreshToken,
+		30*24*60*60*1000, "/", viper.GetString("environment.domain"), false, true)
+	c.SetSameSite(config.HTTPSameSite)
+
+	c.JSON(http.StatusOK, userModel.TokenAccessModel{
+		AccessToken: data.AccessToken,
+	})
+}
+
+// @Summary Подключение 2FA при входе
+// @Tags API для авторизации и регистрации пользователя
+// @Description Выдача секрета TOTP пользователю, для роли которого 2FA обязательна, но ещё не подключена (enrollment_required). Подключение подтверждается кодом на /auth/sign-in/2fa
+// @ID auth-sign-in-2fa-setup
+// @Accept  json
+// @Produce  json
+// @Param input body userModel.TwoFactorChallengeTokenModel true "credentials"
+// @Success 200 {object} userModel.TwoFactorEnrollModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /auth/sign-in/2fa/setup [post]
+func (h *AuthHandler) signInTwoFactorSetup(c *gin.Context) {
+	var input userModel.TwoFactorChallengeTokenModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "invalid input body")
+		return
+	}
+
+	data, err := h.services.TwoFactor.SetupChallenge(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	"main-server/pkg/repository"
+	service "main-server/pkg/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* Первый шаг авторизации: пароль верный, каждый вход выдаёт новый промежуточный токен */
+type stubAuthorization struct {
+	repository.Authorization
+	issued int
+}
+
+func (r *stubAuthorization) LoginUser(user userModel.UserLoginModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	r.issued++
+
+	return userModel.UserAuthDataModel{
+		Challenge: &userModel.TwoFactorChallengeModel{
+			ChallengeToken: fmt.Sprintf("challenge-%d", r.issued),
+			ExpiresAt:      time.Now().Add(5 * time.Minute),
+		},
+	}, nil
+}
+
+/* Второй шаг авторизации: каждый код неверный */
+type stubTwoFactor struct {
+	repository.TwoFactor
+	email string
+}
+
+func (r *stubTwoFactor) GetChallengeEmail(token string) (string, error) {
+	return r.email, nil
+}
+
+func (r *stubTwoFactor) SignIn(data userModel.TwoFactorSignInModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return userModel.UserAuthDataModel{}, repository.ErrInvalidTwoFactorCode
+}
+
+/* Учёт неудачных попыток в памяти (задержка после исчерпания бесплатных попыток учётной записи) */
+type memoryThrottle struct {
+	failures map[string]int
+	resets   []string
+}
+
+func (t *memoryThrottle) Check(action, email, ip string) (time.Duration, error) {
+	if t.failures[action+":"+email] > authConstant.THROTTLE_ACCOUNT_FREE {
+		return authConstant.THROTTLE_BASE_DELAY, nil
+	}
+
+	return 0, nil
+}
+
+func (t *memoryThrottle) Fail(action, email, ip string) error {
+	t.failures[action+":"+email]++
+	return nil
+}
+
+func (t *memoryThrottle) Reset(action, email string) error {
+	t.resets = append(t.resets, action)
+	delete(t.failures, action+":"+email)
+	return nil
+}
+
+func postJSON(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestTwoFactorAttemptsAreThrottledAcrossChallenges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	throttle := &memoryThrottle{failures: make(map[string]int)}
+	services := &service.Service{
+		Authorization: service.NewAuthService(&stubAuthorization{}, throttle, service.TokenService{}),
+		TwoFactor:     service.NewTwoFactorService(&stubTwoFactor{email: "user@example.com"}, throttle),
+	}
+
+	router := gin.New()
+	handler := NewAuthHandler(router, services)
+	router.POST("/sign-in", handler.signIn)
+	router.POST("/sign-in/2fa", handler.signInTwoFactor)
+
+	for i := 0; i < authConstant.THROTTLE_LOCKOUT_ATTEMPTS; i++ {
+		response := postJSON(router, "/sign-in", userModel.UserLoginModel{Email: "user@example.com", Password: "password"})
+		if response.Code != http.StatusAccepted {
+			t.Fatalf("sign-in: expected 202, got %d: %s", response.Code, response.Body.String())
+		}
+
+		var challenge userModel.TwoFactorChallengeModel
+		if err := json.Unmarshal(response.Body.Bytes(), &challenge); err != nil {
+			t.Fatal(err)
+		}
+
+		// Каждый новый промежуточный токен допускает одну попытку, но счётчик учётной записи общий
+		response = postJSON(router, "/sign-in/2fa", userModel.TwoFactorSignInModel{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+		if response.Code == http.StatusTooManyRequests {
+			if response.Header().Get("Retry-After") == "" {
+				t.Fatal("a throttled response must carry Retry-After")
+			}
+
+			if i != authConstant.THROTTLE_ACCOUNT_FREE+1 {
+				t.Fatalf("expected the backoff after %d failures, got it after %d", authConstant.THROTTLE_ACCOUNT_FREE+1, i)
+			}
+
+			for _, action := range throttle.resets {
+				if action == authConstant.THROTTLE_SIGN_IN {
+					t.Fatal("issuing a challenge must not reset the sign-in counter")
+				}
+			}
+
+			return
+		}
+
+		if response.Code != http.StatusBadRequest {
+			t.Fatalf("sign-in/2fa: expected 400, got %d: %s", response.Code, response.Body.String())
+		}
+	}
+
+	t.Fatal("repeated challenges must be throttled with 429")
+}
@@ -0,0 +1,223 @@
+package company
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	entityModel "main-server/pkg/model/entity"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary CreateEntity
+// @Tags entity
+// @Description Создание нового объекта (здания) в проекте
+// @ID company-project-entity-create
+// @Accept  json
+// @Produce  json
+// @Param input body entityModel.EntityCreateModel true "credentials"
+// @Success 200 {object} entityModel.EntityDbDataEx "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/create [post]
+func (h *CompanyHandler) createEntity(c *gin.Context) {
+	var input entityModel.EntityCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Entity.CreateEntity(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary EntityUpdate
+// @Tags entity
+// @Description Обновление информации об объекте проекта
+// @ID company-project-entity-update
+// @Accept  json
+// @Produce  json
+// @Param input body entityModel.EntityUpdateModel true "credentials"
+// @Success 200 {object} entityModel.EntityUpdateModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/update [post]
+func (h *CompanyHandler) entityUpdate(c *gin.Context) {
+	var input entityModel.EntityUpdateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Entity.EntityUpdate(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetEntity
+// @Tags entity
+// @Description Получение информации о конкретном объекте проекта
+// @ID company-project-entity-get
+// @Accept  json
+// @Produce  json
+// @Param input body entityModel.EntityUuidModel true "credentials"
+// @Success 200 {object} entityModel.EntityDbDataEx "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/get [post]
+func (h *CompanyHandler) getEntity(c *gin.Context) {
+	var input entityModel.EntityUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Entity.GetEntity(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetEntities
+// @Tags entity
+// @Description Получение среза объектов проекта
+// @ID company-project-entity-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body entityModel.EntityCountModel true "credentials"
+// @Success 200 {object} entityModel.EntityAnyCountModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/get/all [post]
+func (h *CompanyHandler) getEntities(c *gin.Context) {
+	var input entityModel.EntityCountModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Entity.GetEntities(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteEntity
+// @Tags entity
+// @Description Удаление объекта проекта
+// @ID company-project-entity-delete
+// @Accept  json
+// @Produce  json
+// @Param input body entityModel.EntityUuidModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/delete [post]
+func (h *CompanyHandler) deleteEntity(c *gin.Context) {
+	var input entityModel.EntityUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Entity.DeleteEntity(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
@@ -71,6 +71,92 @@ func (h *CompanyHandler) InitRoutes(
 
 			// URL: /company/project/get/all
 			project.POST(route.GET_ALL_ROUTE, h.getProjects)
+
+			// URL: /company/project/entity
+			entity := project.Group(route.ENTITY_MAIN_ROUTE)
+			{
+				// URL: /company/project/entity/create
+				entity.POST(
+					route.CREATE_ROUTE,
+					hasRoles(
+						"OR",
+						roleConstant.ROLE_BUILDER_MANAGER,
+						roleConstant.ROLE_BUILDER_ADMIN,
+					),
+					h.createEntity,
+				)
+
+				// URL: /company/project/entity/update
+				entity.POST(
+					route.UPDATE_ROUTE,
+					hasRoles(
+						"OR",
+						roleConstant.ROLE_BUILDER_MANAGER,
+						roleConstant.ROLE_BUILDER_ADMIN,
+					),
+					h.entityUpdate,
+				)
+
+				// URL: /company/project/entity/delete
+				entity.POST(
+					route.DELETE_ROUTE,
+					hasRoles(
+						"OR",
+						roleConstant.ROLE_BUILDER_MANAGER,
+						roleConstant.ROLE_BUILDER_ADMIN,
+					),
+					h.deleteEntity,
+				)
+
+				// URL: /company/project/entity/get
+				entity.POST(route.GET_ROUTE, h.getEntity)
+
+				// URL: /company/project/entity/get/all
+				entity.POST(route.GET_ALL_ROUTE, h.getEntities)
+
+				// URL: /company/project/entity/sub-entity
+				subEntity := entity.Group(route.SUB_ENTITY_MAIN_ROUTE)
+				{
+					// URL: /company/project/entity/sub-entity/create
+					subEntity.POST(
+						route.CREATE_ROUTE,
+						hasRoles(
+							"OR",
+							roleConstant.ROLE_BUILDER_MANAGER,
+							roleConstant.ROLE_BUILDER_ADMIN,
+						),
+						h.createSubEntity,
+					)
+
+					// URL: /company/project/entity/sub-entity/update
+					subEntity.POST(
+						route.UPDATE_ROUTE,
+						hasRoles(
+							"OR",
+							roleConstant.ROLE_BUILDER_MANAGER,
+							roleConstant.ROLE_BUILDER_ADMIN,
+						),
+						h.subEntityUpdate,
+					)
+
+					// URL: /company/project/entity/sub-entity/delete
+					subEntity.POST(
+						route.DELETE_ROUTE,
+						hasRoles(
+							"OR",
+							roleConstant.ROLE_BUILDER_MANAGER,
+							roleConstant.ROLE_BUILDER_ADMIN,
+						),
+						h.deleteSubEntity,
+					)
+
+					// URL: /company/project/entity/sub-entity/get
+					subEntity.POST(route.GET_ROUTE, h.getSubEntity)
+
+					// URL: /company/project/entity/sub-entity/get/all
+					subEntity.POST(route.GET_ALL_ROUTE, h.getSubEntities)
+				}
+			}
 		}
 
 		// URL: /manager
@@ -0,0 +1,223 @@
+package company
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	subEntityModel "main-server/pkg/model/sub_entity"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary CreateSubEntity
+// @Tags sub-entity
+// @Description Добавление нового помещения в объект
+// @ID company-project-entity-sub-entity-create
+// @Accept  json
+// @Produce  json
+// @Param input body subEntityModel.SubEntityCreateModel true "credentials"
+// @Success 200 {object} subEntityModel.SubEntityInfoModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/sub-entity/create [post]
+func (h *CompanyHandler) createSubEntity(c *gin.Context) {
+	var input subEntityModel.SubEntityCreateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.SubEntity.CreateSubEntity(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary SubEntityUpdate
+// @Tags sub-entity
+// @Description Обновление информации о помещении объекта
+// @ID company-project-entity-sub-entity-update
+// @Accept  json
+// @Produce  json
+// @Param input body subEntityModel.SubEntityUpdateModel true "credentials"
+// @Success 200 {object} subEntityModel.SubEntityUpdateModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/sub-entity/update [post]
+func (h *CompanyHandler) subEntityUpdate(c *gin.Context) {
+	var input subEntityModel.SubEntityUpdateModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.SubEntity.SubEntityUpdate(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetSubEntity
+// @Tags sub-entity
+// @Description Получение информации о конкретном помещении объекта
+// @ID company-project-entity-sub-entity-get
+// @Accept  json
+// @Produce  json
+// @Param input body subEntityModel.SubEntityUuidModel true "credentials"
+// @Success 200 {object} subEntityModel.SubEntityInfoModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/sub-entity/get [post]
+func (h *CompanyHandler) getSubEntity(c *gin.Context) {
+	var input subEntityModel.SubEntityUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.SubEntity.GetSubEntity(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetSubEntities
+// @Tags sub-entity
+// @Description Получение среза помещений объекта
+// @ID company-project-entity-sub-entity-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body subEntityModel.SubEntityCountModel true "credentials"
+// @Success 200 {object} subEntityModel.SubEntityAnyCountModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/sub-entity/get/all [post]
+func (h *CompanyHandler) getSubEntities(c *gin.Context) {
+	var input subEntityModel.SubEntityCountModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.SubEntity.GetSubEntities(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteSubEntity
+// @Tags sub-entity
+// @Description Удаление помещения объекта
+// @ID company-project-entity-sub-entity-delete
+// @Accept  json
+// @Produce  json
+// @Param input body subEntityModel.SubEntityUuidModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /company/project/entity/sub-entity/delete [post]
+func (h *CompanyHandler) deleteSubEntity(c *gin.Context) {
+	var input subEntityModel.SubEntityUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.SubEntity.DeleteSubEntity(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
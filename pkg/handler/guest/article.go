@@ -0,0 +1,67 @@
+package guest
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	articleModel "main-server/pkg/model/article"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetArticle
+// @Tags guest
+// @Description Получение одобренной модератором статьи
+// @ID guest-article-get
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /guest/article/get [post]
+func (h *GuestHandler) getArticle(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.GetApprovedArticle(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticles
+// @Tags guest
+// @Description Получение среза одобренных модератором статей
+// @ID guest-article-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleCountModel true "credentials"
+// @Success 200 {object} articleModel.ArticlesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /guest/article/get/all [post]
+func (h *GuestHandler) getArticles(c *gin.Context) {
+	var input articleModel.ArticleCountModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.GetApprovedArticles(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,125 @@
+package guest
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	guestModel "main-server/pkg/model/guest"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetCompanies
+// @Tags guest
+// @Description Получение публичного списка компаний
+// @ID guest-company-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body guestModel.GuestCompanyQueryModel true "credentials"
+// @Success 200 {object} guestModel.GuestCompanyAnyCountModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /guest/company/get/all [post]
+func (h *GuestHandler) getCompanies(c *gin.Context) {
+	var input guestModel.GuestCompanyQueryModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetCompanies(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetProjects
+// @Tags guest
+// @Description Получение публичного списка проектов (с фильтрацией по компании)
+// @ID guest-project-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body guestModel.GuestProjectQueryModel true "credentials"
+// @Success 200 {object} guestModel.GuestProjectAnyCountModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /guest/project/get/all [post]
+func (h *GuestHandler) getProjects(c *gin.Context) {
+	var input guestModel.GuestProjectQueryModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetProjects(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetSubEntities
+// @Tags guest
+// @Description Получение списка доступных для аренды помещений с фильтрацией, сортировкой и курсорной навигацией
+// @ID guest-sub-entity-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body guestModel.GuestSubEntityQueryModel true "credentials"
+// @Success 200 {object} guestModel.GuestSubEntityAnyCountModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /guest/sub-entity/get/all [post]
+func (h *GuestHandler) getSubEntities(c *gin.Context) {
+	var input guestModel.GuestSubEntityQueryModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetSubEntities(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetSubEntity
+// @Tags guest
+// @Description Получение публичной информации о доступном для аренды помещении
+// @ID guest-sub-entity-get
+// @Accept  json
+// @Produce  json
+// @Param input body guestModel.GuestUuidModel true "credentials"
+// @Success 200 {object} guestModel.GuestSubEntityModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /guest/sub-entity/get [post]
+func (h *GuestHandler) getSubEntity(c *gin.Context) {
+	var input guestModel.GuestUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Guest.GetSubEntity(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,56 @@
+package guest
+
+import (
+	_ "main-server/docs"
+	"main-server/pkg/constant/route"
+	service "main-server/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/swaggo/files"
+	_ "github.com/swaggo/gin-swagger"
+)
+
+type GuestHandler struct {
+	rootHandler *gin.Engine
+	services    *service.Service
+}
+
+func NewGuestHandler(root *gin.Engine, services *service.Service) *GuestHandler {
+	return &GuestHandler{
+		rootHandler: root,
+		services:    services,
+	}
+}
+
+/* Инициализация маршрутов публичного каталога (доступны без авторизации) */
+func (h *GuestHandler) InitRoutes() {
+	// URL: /guest
+	guest := h.rootHandler.Group(route.GUEST_MAIN_ROUTE)
+	{
+		// URL: /guest/company/get/all
+		guest.POST(route.COMPANY_MAIN_ROUTE+route.GET_ALL_ROUTE, h.getCompanies)
+
+		// URL: /guest/project/get/all
+		guest.POST(route.PROJECT_MAIN_ROUTE+route.GET_ALL_ROUTE, h.getProjects)
+
+		// URL: /guest/article
+		article := guest.Group(route.GUEST_ARTICLE_ROUTE)
+		{
+			// URL: /guest/article/get
+			article.POST(route.GET_ROUTE, h.getArticle)
+
+			// URL: /guest/article/get/all
+			article.POST(route.GET_ALL_ROUTE, h.getArticles)
+		}
+
+		// URL: /guest/sub-entity
+		subEntity := guest.Group(route.SUB_ENTITY_MAIN_ROUTE)
+		{
+			// URL: /guest/sub-entity/get
+			subEntity.POST(route.GET_ROUTE, h.getSubEntity)
+
+			// URL: /guest/sub-entity/get/all
+			subEntity.POST(route.GET_ALL_ROUTE, h.getSubEntities)
+		}
+	}
+}
@@ -1,16 +1,20 @@
 package handler
 
 import (
+	"main-server/config"
 	middlewareConstant "main-server/pkg/constant/middleware"
 	adminHandler "main-server/pkg/handler/admin"
 	authHandler "main-server/pkg/handler/auth"
 	companyHandler "main-server/pkg/handler/company"
 	excelHandler "main-server/pkg/handler/excel"
+	guestHandler "main-server/pkg/handler/guest"
+	moderatorHandler "main-server/pkg/handler/moderator"
 	serviceHandler "main-server/pkg/handler/service"
 	userHandler "main-server/pkg/handler/user"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	_ "main-server/docs"
@@ -35,6 +39,12 @@ func NewHandler(services *service.Service) *Handler {
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
 
+	// Адрес клиента из X-Forwarded-For принимается только от доверенных прокси (по умолчанию - ни от каких),
+	// иначе клиент может подменить адрес, по которому ведётся учёт неудачных попыток и сессий
+	if err := router.SetTrustedProxies(config.AppHTTPConfig.TrustedProxies); err != nil {
+		logrus.Fatalf("error setting trusted proxies: %s", err.Error())
+	}
+
 	// Установка максимального размера тела Multipart
 	router.MaxMultipartMemory = 50 << 20 // 50 MiB
 
@@ -49,13 +59,16 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		//AllowAllOrigins: true,
 		AllowOrigins:     []string{viper.GetString("client_url")},
 		AllowMethods:     []string{"POST", "GET"},
-		AllowHeaders:     []string{"Origin", "Content-type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-type", "Authorization", config.AppDomainConfig.Header},
 		AllowCredentials: true,
 	}))
 
 	// URL: /swagger/index.html
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Определение домена запроса (для всех маршрутов API)
+	router.Use(h.domainIdentity)
+
 	// Инициализация списка обработчиков в цепочке middleware
 	middleware := make(map[string]func(c *gin.Context))
 	middleware[middlewareConstant.MN_UI] = h.userIdentity
@@ -63,11 +76,11 @@ func (h *Handler) InitRoutes() *gin.Engine {
 
 	// Инициализация маршрутов для сервиса service
 	service := serviceHandler.NewServiceHandler(router, h.services)
-	service.InitRoutes(&middleware)
+	service.InitRoutes(&middleware, h.serviceIdentityHasScope)
 
 	// Инициализация маршрутов для сервиса auth
 	auth := authHandler.NewAuthHandler(router, h.services)
-	auth.InitRoutes(&middleware)
+	auth.InitRoutes(&middleware, h.serviceIdentityHasScope)
 
 	// Инициализация маршрутов для сервиса user
 	user := userHandler.NewUserHandler(router, h.services)
@@ -79,11 +92,19 @@ func (h *Handler) InitRoutes() *gin.Engine {
 
 	// Инициализация маршрутов для сервиса admin
 	admin := adminHandler.NewAdminHandler(router, h.services)
-	admin.InitRoutes(&middleware, h.userIdentityHasRole)
+	admin.InitRoutes(&middleware, h.userIdentityHasRole, h.userIdentityHasRoles)
 
 	// Инициализация маршрутов для сервиса excel
 	excel := excelHandler.NewExcelHandler(router, h.services)
 	excel.InitRoutes(&middleware)
 
+	// Инициализация маршрутов для сервиса moderator
+	moderator := moderatorHandler.NewModeratorHandler(router, h.services)
+	moderator.InitRoutes(&middleware, h.userIdentityHasRoles)
+
+	// Инициализация маршрутов для публичного каталога guest
+	guest := guestHandler.NewGuestHandler(router, h.services)
+	guest.InitRoutes()
+
 	return router
 }
@@ -1,16 +1,64 @@
 package handler
 
 import (
+	"fmt"
+	config "main-server/config"
+	authConstants "main-server/pkg/constant/auth"
 	middlewareConstants "main-server/pkg/constant/middleware"
+	"main-server/pkg/constant/route"
 	utilContext "main-server/pkg/handler/util"
 	authService "main-server/pkg/service/auth"
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper"
+	"github.com/samber/lo"
 )
 
+/* Маршруты получения данных (GET-запросы и маршруты вида .../get, .../get/all, проверка доступа) */
+func isReadOnlyRoute(c *gin.Context) bool {
+	if c.Request.Method == http.MethodGet {
+		return true
+	}
+
+	path := c.FullPath()
+
+	return strings.HasSuffix(path, route.GET_ROUTE) ||
+		strings.Contains(path, route.GET_ROUTE+"/") ||
+		strings.HasSuffix(path, route.USER_CHECK_ACCESS_ROUTE)
+}
+
+/*
+* Определение домена запроса: значение из заголовка X-Domain, домен, привязанный к имени хоста,
+* или домен по умолчанию. Домен проверяется по таблице ac_domains
+ */
+func (h *Handler) domainIdentity(c *gin.Context) {
+	value := strings.TrimSpace(c.GetHeader(config.AppDomainConfig.Header))
+
+	if value == "" {
+		host := c.Request.Host
+		if name, _, err := net.SplitHostPort(host); err == nil {
+			host = name
+		}
+
+		value = config.AppDomainConfig.Hosts[strings.ToLower(host)]
+	}
+
+	if value == "" {
+		value = config.AppDomainConfig.Default
+	}
+
+	domain, err := h.services.Domain.Resolve(value)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Set(middlewareConstants.DOMAINS_ID, domain.Id)
+	c.Set(middlewareConstants.DOMAIN_VALUE_CTX, domain.Value)
+}
+
 /* Идентификация пользователя */
 func (h *Handler) userIdentity(c *gin.Context) {
 	header := c.GetHeader(middlewareConstants.AUTHORIZATION_HEADER)
@@ -26,28 +74,57 @@ func (h *Handler) userIdentity(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Token.ParseToken(headerParts[1], viper.GetString("token.signing_key_access"))
+	data, err := h.services.Token.ParseToken(headerParts[1])
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	domain, err := h.services.Domain.Get("value", viper.GetString("domain"), true)
-	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+	// Проверка блокировки пользователя
+	if err := h.services.Ban.CheckBan(data.UsersId); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
 		return
 	}
 
-	switch data.AuthType.Value {
-	case "GOOGLE":
-		if result, err := authService.VerifyAccessToken(*data.TokenApi); err != nil || result != true {
+	// Проверка активации аккаунта (в режиме read_only до активации доступно только получение данных)
+	if config.AppActivationConfig.Mode != config.ACTIVATION_MODE_OFF {
+		activated, err := h.services.Authorization.CheckActivation(data.UsersId)
+		if err != nil {
+			utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if !activated && (config.AppActivationConfig.Mode == config.ACTIVATION_MODE_BLOCK || !isReadOnlyRoute(c)) {
+			utilContext.NewErrorResponse(c, http.StatusForbidden, "Аккаунт не активирован! Перейдите по ссылке из письма или запросите его повторно")
+			return
+		}
+	}
+
+	// Проверка, что сессия, к которой привязан токен, не завершена
+	if data.SessionUuid != nil {
+		if err := h.services.Session.CheckSession(data.UsersId, *data.SessionUuid); err != nil {
+			utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+	}
+
+	// Проверка внешнего токена доступа, если пользователь авторизован через провайдера
+	if data.AuthType.Value != authConstants.AUTH_TYPE_LOCAL {
+		provider, err := authService.GetProvider(data.AuthType.Value)
+		if err != nil {
+			utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if data.TokenApi == nil {
 			utilContext.NewErrorResponse(c, http.StatusUnauthorized, "Не действительный токен доступа")
 			return
 		}
-		break
 
-	case "LOCAL":
-		break
+		if result, err := provider.VerifyAccessToken(*data.TokenApi); err != nil || result != true {
+			utilContext.NewErrorResponse(c, http.StatusUnauthorized, "Не действительный токен доступа")
+			return
+		}
 	}
 
 	// Добавление к контексту дополнительных данных о пользователе
@@ -56,7 +133,7 @@ func (h *Handler) userIdentity(c *gin.Context) {
 	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, data.AuthType.Value)
 	c.Set(middlewareConstants.TOKEN_API_CTX, data.TokenApi)
 	c.Set(middlewareConstants.ACCESS_TOKEN_CTX, headerParts[1])
-	c.Set(middlewareConstants.DOMAINS_ID, domain.Id)
+	c.Set(middlewareConstants.SESSION_UUID_CTX, data.SessionUuid)
 }
 
 func (h *Handler) userIdentityLogout(c *gin.Context) {
@@ -72,7 +149,7 @@ func (h *Handler) userIdentityLogout(c *gin.Context) {
 		return
 	}
 
-	data, err := h.services.Token.ParseTokenWithoutValid(headerParts[1], viper.GetString("token.signing_key_access"))
+	data, err := h.services.Token.ParseTokenWithoutValid(headerParts[1])
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
@@ -84,6 +161,36 @@ func (h *Handler) userIdentityLogout(c *gin.Context) {
 	c.Set(middlewareConstants.AUTH_TYPE_VALUE_CTX, data.AuthType.Value)
 	c.Set(middlewareConstants.TOKEN_API_CTX, data.TokenApi)
 	c.Set(middlewareConstants.ACCESS_TOKEN_CTX, headerParts[1])
+	c.Set(middlewareConstants.SESSION_UUID_CTX, data.SessionUuid)
+}
+
+/* Идентификация сервисного клиента и проверка области доступа, необходимой маршруту */
+func (h *Handler) serviceIdentityHasScope(scope string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		header := c.GetHeader(middlewareConstants.AUTHORIZATION_HEADER)
+
+		headerParts := strings.Split(header, " ")
+		if len(headerParts) != 2 || !strings.EqualFold(headerParts[0], "Bearer") {
+			c.Header("WWW-Authenticate", `Bearer realm="service"`)
+			utilContext.NewErrorResponse(c, http.StatusUnauthorized, "Не корректный авторизационный заголовок!")
+			return
+		}
+
+		data, err := h.services.Token.ParseServiceToken(headerParts[1])
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="service", error="invalid_token"`)
+			utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if !lo.Contains(data.Scopes, scope) {
+			c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer realm="service", error="insufficient_scope", scope="%s"`, scope))
+			utilContext.NewErrorResponse(c, http.StatusForbidden, fmt.Sprintf("Недостаточно прав: требуется область доступа %s", scope))
+			return
+		}
+
+		c.Set(middlewareConstants.SERVICE_CLIENT_CTX, data.ClientId)
+	}
 }
 
 func (h *Handler) userIdentityHasRoles(exp string, roles ...string) func(c *gin.Context) {
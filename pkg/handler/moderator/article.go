@@ -0,0 +1,89 @@
+package moderator
+
+import (
+	statusConstant "main-server/pkg/constant/status"
+	utilContext "main-server/pkg/handler/util"
+	articleModel "main-server/pkg/model/article"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetUncheckedArticles
+// @Tags moderator
+// @Description Получение среза статей, ожидающих модерации
+// @ID moderator-article-unchecked
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleCountModel true "credentials"
+// @Success 200 {object} articleModel.ArticlesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /moderator/article/unchecked [post]
+func (h *ModeratorHandler) getUncheckedArticles(c *gin.Context) {
+	var input articleModel.ArticleCountModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.GetUncheckedArticles(input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary ApproveArticle
+// @Tags moderator
+// @Description Одобрение статьи (статья становится доступна в публичном разделе)
+// @ID moderator-article-approve
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleModerationModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /moderator/article/approve [post]
+func (h *ModeratorHandler) approveArticle(c *gin.Context) {
+	h.moderateArticle(c, statusConstant.ARTICLE_APPROVED)
+}
+
+// @Summary RejectArticle
+// @Tags moderator
+// @Description Отклонение статьи с указанием причины (comment)
+// @ID moderator-article-reject
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleModerationModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /moderator/article/reject [post]
+func (h *ModeratorHandler) rejectArticle(c *gin.Context) {
+	h.moderateArticle(c, statusConstant.ARTICLE_REJECTED)
+}
+
+/* Изменение статуса модерации статьи */
+func (h *ModeratorHandler) moderateArticle(c *gin.Context, status string) {
+	var input articleModel.ArticleModerationModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.ModerateArticle(input, status)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,52 @@
+package moderator
+
+import (
+	_ "main-server/docs"
+	middlewareConstant "main-server/pkg/constant/middleware"
+	roleConstant "main-server/pkg/constant/role"
+	"main-server/pkg/constant/route"
+	service "main-server/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/swaggo/files"
+	_ "github.com/swaggo/gin-swagger"
+)
+
+type ModeratorHandler struct {
+	rootHandler *gin.Engine
+	services    *service.Service
+}
+
+func NewModeratorHandler(root *gin.Engine, services *service.Service) *ModeratorHandler {
+	return &ModeratorHandler{
+		rootHandler: root,
+		services:    services,
+	}
+}
+
+/* Инициализация маршрутов для модерации контента */
+func (h *ModeratorHandler) InitRoutes(
+	middleware *map[string]func(c *gin.Context),
+	hasRoles func(exp string, roles ...string) func(c *gin.Context),
+) {
+	// URL: /moderator
+	moderator := h.rootHandler.Group(
+		route.MODERATOR_MAIN_ROUTE,
+		(*middleware)[middlewareConstant.MN_UI],
+		hasRoles("OR", roleConstant.ROLE_MODERATOR, roleConstant.ROLE_ADMIN, roleConstant.ROLE_SUPER_ADMIN),
+	)
+	{
+		// URL: /moderator/article
+		article := moderator.Group(route.MODERATOR_ARTICLE_ROUTE)
+		{
+			// URL: /moderator/article/unchecked
+			article.POST(route.MODERATOR_UNCHECKED_ROUTE, h.getUncheckedArticles)
+
+			// URL: /moderator/article/approve
+			article.POST(route.MODERATOR_APPROVE_ROUTE, h.approveArticle)
+
+			// URL: /moderator/article/reject
+			article.POST(route.MODERATOR_REJECT_ROUTE, h.rejectArticle)
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"main-server/pkg/constant/route"
 	service "main-server/pkg/service"
 
-	middlewareConstant "main-server/pkg/constant/middleware"
+	authConstant "main-server/pkg/constant/auth"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/swaggo/files"
@@ -28,18 +28,22 @@ func NewServiceHandler(root *gin.Engine, services *service.Service) *ServiceHand
 /* Инициализация маршрутов для сервисов */
 func (h *ServiceHandler) InitRoutes(
 	middleware *map[string]func(c *gin.Context),
+	hasScope func(scope string) func(c *gin.Context),
 ) {
 	// URL: /service
-	service := h.rootHandler.Group(route.SERVICE, (*middleware)[middlewareConstant.MN_UI])
+	service := h.rootHandler.Group(route.SERVICE)
 	{
+		// URL: /service/token
+		service.POST(route.SERVICE_TOKEN, h.serviceToken)
+
 		// URL: /main
 		main := service.Group(route.SERVICE_MAIN)
 		{
 			// URL: /verify
-			main.POST(route.SERVICE_VERIFY, h.serviceMainVerify)
+			main.POST(route.SERVICE_VERIFY, hasScope(authConstant.SCOPE_TOKEN_VERIFY), h.serviceMainVerify)
 
 			// URL: /mail/send
-			main.POST(route.SERVICE_EMAIL_SEND, h.serviceMainEmailSend)
+			main.POST(route.SERVICE_EMAIL_SEND, hasScope(authConstant.SCOPE_EMAIL_SEND), h.serviceMainEmailSend)
 		}
 	}
 }
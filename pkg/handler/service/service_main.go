@@ -5,7 +5,6 @@ import (
 	emailModel "main-server/pkg/model/email"
 	httpModel "main-server/pkg/model/http"
 	serviceModel "main-server/pkg/model/service"
-	"main-server/pkg/model/user"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,38 +12,58 @@ import (
 
 // @Summary Проверка токена доступа
 // @Tags Общие API для сервисов
-// @Description Проверка токена доступа
+// @Description Проверка токена доступа пользователя (подпись, срок действия, блокировка и активность сессии). Требуется область доступа token:verify
 // @ID service-main-token-verify
 // @Accept  json
 // @Produce  json
-// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param Authorization header string true "Токен доступа сервисного клиента" example(Bearer access_token)
+// @Param input body serviceModel.TokenVerifyInputModel true "Токен доступа пользователя"
 // @Success 200 {object} serviceModel.TokenVerifyModel "data"
-// @Failure 400,404 {object} ResponseMessage
+// @Failure 400,401,403,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
 // @Router /service/main/verify [post]
 func (h *ServiceHandler) serviceMainVerify(c *gin.Context) {
-	_, userUuid, _, err := utilContext.GetContextUserInfo(c)
+	var input serviceModel.TokenVerifyInputModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.services.Token.ParseToken(input.AccessToken)
 	if err != nil {
-		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.services.Ban.CheckBan(data.UsersId); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
+	if data.SessionUuid != nil {
+		if err := h.services.Session.CheckSession(data.UsersId, *data.SessionUuid); err != nil {
+			utilContext.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, serviceModel.TokenVerifyModel{
-		Uuid: userUuid,
+		Uuid: data.UsersUuid,
 	})
 }
 
 // @Summary Отправка сообщения пользователю
 // @Tags Общие API для сервисов
-// @Description Проверка токена доступа
+// @Description Отправка сообщения пользователям по их UUID. Требуется область доступа email:send
 // @ID service-main-email-send
 // @Accept  json
 // @Produce  json
-// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param Authorization header string true "Токен доступа сервисного клиента" example(Bearer access_token)
 // @Param input body emailModel.MessageInputModel true "Информация для отправки сообщения"
 // @Success 200 {object} httpModel.ResponseValue "data"
-// @Failure 400,404 {object} ResponseMessage
+// @Failure 400,401,403,404 {object} ResponseMessage
 // @Failure 500 {object} ResponseMessage
 // @Failure default {object} ResponseMessage
 // @Router /service/main/email/send [post]
@@ -56,9 +75,13 @@ func (h *ServiceHandler) serviceMainEmailSend(c *gin.Context) {
 		return
 	}
 
-	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	clientId, err := utilContext.GetContextServiceClient(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
 
-	data, err := h.services.SendEmail(&user.UserIdentityModel{UserId: userId, DomainId: domainId}, &input)
+	data, err := h.services.SendEmail(clientId, &input)
 
 	if err != nil {
 		utilContext.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	serviceModel "main-server/pkg/model/service"
+	service "main-server/pkg/service"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+/* Ответ с ошибкой в формате OAuth2 (RFC 6749, раздел 5.2) */
+func newTokenErrorResponse(c *gin.Context, statusCode int, code string, err error) {
+	c.AbortWithStatusJSON(statusCode, serviceModel.ServiceTokenErrorModel{
+		Error:            code,
+		ErrorDescription: err.Error(),
+	})
+}
+
+// @Summary Получение токена сервисного клиента
+// @Tags Общие API для сервисов
+// @Description OAuth2 client credentials: client_id и client_secret передаются в теле (form или JSON) либо в заголовке Authorization (Basic)
+// @ID service-token
+// @Accept  x-www-form-urlencoded,json
+// @Produce  json
+// @Param input body serviceModel.ServiceTokenRequestModel true "Данные клиента"
+// @Success 200 {object} serviceModel.ServiceTokenModel "data"
+// @Failure 400,401,429 {object} serviceModel.ServiceTokenErrorModel
+// @Failure 500 {object} serviceModel.ServiceTokenErrorModel
+// @Failure default {object} serviceModel.ServiceTokenErrorModel
+// @Router /service/token [post]
+func (h *ServiceHandler) serviceToken(c *gin.Context) {
+	var input serviceModel.ServiceTokenRequestModel
+
+	if err := c.ShouldBind(&input); err != nil {
+		newTokenErrorResponse(c, http.StatusBadRequest, "invalid_request", err)
+		return
+	}
+
+	// Учётные данные клиента из заголовка Basic (RFC 6749, раздел 2.3.1) имеют приоритет
+	clientId, clientSecret, basic := c.Request.BasicAuth()
+	if basic {
+		var errId, errSecret error
+		input.ClientId, errId = url.QueryUnescape(clientId)
+		input.ClientSecret, errSecret = url.QueryUnescape(clientSecret)
+
+		if errId != nil || errSecret != nil {
+			newTokenErrorResponse(c, http.StatusBadRequest, "invalid_request", errors.New("Некорректный заголовок Basic"))
+			return
+		}
+	}
+
+	data, err := h.services.ServiceClient.IssueToken(input, c.ClientIP())
+
+	var throttleErr *service.TooManyAttemptsError
+
+	switch {
+	case err == nil:
+		c.Header("Cache-Control", "no-store")
+		c.Header("Pragma", "no-cache")
+		c.JSON(http.StatusOK, data)
+	case errors.Is(err, service.ErrUnsupportedGrantType):
+		newTokenErrorResponse(c, http.StatusBadRequest, "unsupported_grant_type", err)
+	case errors.Is(err, service.ErrInvalidScope):
+		newTokenErrorResponse(c, http.StatusBadRequest, "invalid_scope", err)
+	case errors.Is(err, service.ErrInvalidClient):
+		if basic {
+			c.Header("WWW-Authenticate", `Basic realm="service"`)
+		}
+		newTokenErrorResponse(c, http.StatusUnauthorized, "invalid_client", err)
+	case errors.As(err, &throttleErr):
+		c.Header("Retry-After", strconv.Itoa(throttleErr.Seconds()))
+		newTokenErrorResponse(c, http.StatusTooManyRequests, "slow_down", err)
+	default:
+		newTokenErrorResponse(c, http.StatusInternalServerError, "server_error", err)
+	}
+}
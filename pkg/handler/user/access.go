@@ -0,0 +1,51 @@
+package user
+
+import (
+	"errors"
+	utilContext "main-server/pkg/handler/util"
+	rbacModel "main-server/pkg/model/rbac"
+	service "main-server/pkg/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary ExplainAccess
+// @Tags profile
+// @Description Объяснение решения о доступе текущего пользователя к объекту: цепочка ролей, правила для объекта и его родительских ресурсов, недостающее правило. Для объектов без правил пользователя - 404, цепочка родительских ресурсов (objects) - только при разрешённом доступе
+// @ID user-access-explain
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body rbacModel.AccessExplainModel true "credentials"
+// @Success 200 {object} rbacModel.AccessExplainResultModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/access/explain [post]
+func (h *UserHandler) explainAccess(c *gin.Context) {
+	var input rbacModel.AccessExplainModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Access.Explain(userId, domainId, input)
+	if errors.Is(err, service.ErrAccessObjectNotFound) {
+		utilContext.NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
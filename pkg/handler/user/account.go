@@ -0,0 +1,156 @@
+package user
+
+import (
+	"fmt"
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary ExportAccount
+// @Tags account
+// @Description Выгрузка персональных данных: профиль, способы входа, роли, участие в компаниях и загруженные файлы (format: zip - по умолчанию, или json)
+// @ID user-account-export
+// @Accept  json
+// @Produce  json,application/zip
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.AccountExportRequestModel false "credentials"
+// @Success 200 {object} userModel.AccountExportModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/account/export [post]
+func (h *UserHandler) exportAccount(c *gin.Context) {
+	var input userModel.AccountExportRequestModel
+
+	// Тело запроса необязательно
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&input); err != nil {
+			utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if input.Format == "json" {
+		data, err := h.services.Account.GetExport(userId)
+		if err != nil {
+			utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	archive, err := h.services.Account.GetExportArchive(userId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%s.zip"`, time.Now().Format("20060102")))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// @Summary DeleteAccount
+// @Tags account
+// @Description Запрос на удаление аккаунта по истечении периода ожидания (пароль обязателен при наличии локального входа). Недоступно единственному администратору компании
+// @ID user-account-delete
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.AccountDeleteModel true "credentials"
+// @Success 200 {object} userModel.AccountDeletionModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/account/delete [post]
+func (h *UserHandler) deleteAccount(c *gin.Context) {
+	var input userModel.AccountDeleteModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Account.RequestDeletion(userId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetAccountDeletion
+// @Tags account
+// @Description Получение состояния запроса на удаление аккаунта
+// @ID user-account-delete-get
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.AccountDeletionModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/account/delete/get [post]
+func (h *UserHandler) getAccountDeletion(c *gin.Context) {
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Account.GetDeletion(userId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary CancelAccountDeletion
+// @Tags account
+// @Description Отмена запроса на удаление аккаунта (до истечения периода ожидания)
+// @ID user-account-delete-cancel
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/account/delete/cancel [post]
+func (h *UserHandler) cancelAccountDeletion(c *gin.Context) {
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Account.CancelDeletion(userId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
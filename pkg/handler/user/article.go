@@ -0,0 +1,308 @@
+package user
+
+import (
+	pathConstant "main-server/pkg/constant/path"
+	utilContext "main-server/pkg/handler/util"
+	articleModel "main-server/pkg/model/article"
+	userModel "main-server/pkg/model/user"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+)
+
+/* Файл, который необходимо сохранить после успешного изменения статьи */
+type articleUploadFile struct {
+	header   *multipart.FileHeader
+	filepath string
+}
+
+/*
+ * Разбор файлов статьи из multipart-формы:
+ * file - обложка статьи, files - прикреплённые к статье файлы
+ */
+func parseArticleFiles(form *multipart.Form) (*string, *string, *[]articleModel.ArticlesFilesDBModel, []articleUploadFile) {
+	var uploads []articleUploadFile
+	var filename, filepath *string
+
+	if cover := form.File["file"]; len(cover) > 0 {
+		header := cover[len(cover)-1]
+		path := pathConstant.PUBLIC_ARTICLE + uuid.NewV4().String()
+
+		filename, filepath = &header.Filename, &path
+		uploads = append(uploads, articleUploadFile{header: header, filepath: path})
+	}
+
+	files := []articleModel.ArticlesFilesDBModel{}
+	for index, header := range form.File["files"] {
+		path := pathConstant.PUBLIC_ARTICLE + uuid.NewV4().String()
+
+		files = append(files, articleModel.ArticlesFilesDBModel{
+			Index:    index,
+			Filename: header.Filename,
+			Filepath: path,
+		})
+		uploads = append(uploads, articleUploadFile{header: header, filepath: path})
+	}
+
+	return filename, filepath, &files, uploads
+}
+
+// @Summary CreateArticle
+// @Tags article
+// @Description Создание новой статьи (multipart: title, text, tags, file - обложка, files - вложения)
+// @ID user-article-create
+// @Accept  mpfd
+// @Produce  json
+// @Success 200 {object} articleModel.ArticleModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/article/create [post]
+func (h *UserHandler) createArticle(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := articleModel.ArticleCreateRequestModel{
+		Title: c.PostForm("title"),
+		Text:  c.PostForm("text"),
+		Tags:  c.PostForm("tags"),
+	}
+
+	if input.Title == "" || input.Text == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Ошибка: не указан заголовок или текст статьи!")
+		return
+	}
+
+	var uploads []articleUploadFile
+	input.Filename, input.Filepath, input.Files, uploads = parseArticleFiles(form)
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.CreateArticle(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		form.RemoveAll()
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, file := range uploads {
+		c.SaveUploadedFile(file.header, file.filepath)
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary UpdateArticle
+// @Tags article
+// @Description Обновление статьи (multipart: uuid, title, text, tags, file, files, files_delete). Статья повторно отправляется на модерацию
+// @ID user-article-update
+// @Accept  mpfd
+// @Produce  json
+// @Success 200 {object} articleModel.ArticleModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/article/update [post]
+func (h *UserHandler) updateArticle(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := articleModel.ArticleUpdateRequestModel{
+		Uuid:  c.PostForm("uuid"),
+		Title: c.PostForm("title"),
+		Text:  c.PostForm("text"),
+		Tags:  c.PostForm("tags"),
+	}
+
+	if input.Uuid == "" || input.Title == "" || input.Text == "" {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, "Ошибка: не указан uuid, заголовок или текст статьи!")
+		return
+	}
+
+	filesDelete := []int{}
+	for _, value := range c.PostFormArray("files_delete") {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			utilContext.NewErrorResponse(c, http.StatusBadRequest, "Ошибка: некорректный идентификатор файла!")
+			return
+		}
+
+		filesDelete = append(filesDelete, id)
+	}
+	input.FilesDelete = &filesDelete
+
+	var uploads []articleUploadFile
+	input.Filename, input.Filepath, input.Files, uploads = parseArticleFiles(form)
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.UpdateArticle(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		form.RemoveAll()
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, file := range uploads {
+		c.SaveUploadedFile(file.header, file.filepath)
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DeleteArticle
+// @Tags article
+// @Description Удаление статьи пользователя
+// @ID user-article-delete
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleSuccessModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/article/delete [post]
+func (h *UserHandler) deleteArticle(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.DeleteArticle(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticle
+// @Tags article
+// @Description Получение статьи пользователя (с любым статусом модерации)
+// @ID user-article-get
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleUuidModel true "credentials"
+// @Success 200 {object} articleModel.ArticleModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/article/get [post]
+func (h *UserHandler) getArticle(c *gin.Context) {
+	var input articleModel.ArticleUuidModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.GetUserArticle(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary GetArticles
+// @Tags article
+// @Description Получение среза статей пользователя
+// @ID user-article-get-all
+// @Accept  json
+// @Produce  json
+// @Param input body articleModel.ArticleCountModel true "credentials"
+// @Success 200 {object} articleModel.ArticlesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/article/get/all [post]
+func (h *UserHandler) getArticles(c *gin.Context) {
+	var input articleModel.ArticleCountModel
+
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.Article.GetUserArticles(
+		userModel.UserIdentityModel{
+			UserId:   userId,
+			DomainId: domainId,
+		},
+		input,
+	)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
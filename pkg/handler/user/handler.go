@@ -34,6 +34,9 @@ func (h *UserHandler) InitRoutes(
 		// URL: /user/access/check
 		user.POST(route.USER_CHECK_ACCESS_ROUTE, h.accessCheck)
 
+		// URL: /user/access/explain
+		user.POST(route.USER_EXPLAIN_ACCESS, h.explainAccess)
+
 		// URL: /user/role/get/all
 		user.POST(route.USER_ROLES+"/"+route.GET_ALL_ROUTE, h.getUserRoles)
 
@@ -45,6 +48,105 @@ func (h *UserHandler) InitRoutes(
 
 			// URL: /user/profile/update
 			profile.POST(route.UPDATE_ROUTE, h.updateProfile)
+
+			// URL: /user/profile/password/set
+			profile.POST(route.USER_PASSWORD_SET_ROUTE, h.setPassword)
+
+			// URL: /user/profile/email
+			email := profile.Group(route.USER_EMAIL_ROUTE)
+			{
+				// URL: /user/profile/email/get
+				email.POST(route.GET_ROUTE, h.getEmailChange)
+
+				// URL: /user/profile/email/change
+				email.POST(route.USER_EMAIL_CHANGE_ROUTE, h.requestEmailChange)
+
+				// URL: /user/profile/email/confirm
+				email.POST(route.USER_EMAIL_CONFIRM_ROUTE, h.confirmEmailChange)
+			}
+
+			// URL: /user/profile/identities
+			identities := profile.Group(route.USER_IDENTITIES_ROUTE)
+			{
+				// URL: /user/profile/identities/get/all
+				identities.POST(route.GET_ALL_ROUTE, h.getIdentities)
+
+				// URL: /user/profile/identities/link/url
+				identities.POST(route.USER_IDENTITIES_LINK_URL, h.getIdentityLinkURL)
+
+				// URL: /user/profile/identities/link
+				identities.POST(route.USER_IDENTITIES_LINK_ROUTE, h.linkIdentity)
+
+				// URL: /user/profile/identities/unlink
+				identities.POST(route.USER_IDENTITIES_UNLINK_ROUTE, h.unlinkIdentity)
+			}
+		}
+
+		// URL: /user/account
+		account := user.Group(route.USER_ACCOUNT_ROUTE)
+		{
+			// URL: /user/account/export
+			account.POST(route.USER_ACCOUNT_EXPORT_ROUTE, h.exportAccount)
+
+			// URL: /user/account/delete
+			account.POST(route.DELETE_ROUTE, h.deleteAccount)
+
+			// URL: /user/account/delete/get
+			account.POST(route.USER_ACCOUNT_DELETE_GET_ROUTE, h.getAccountDeletion)
+
+			// URL: /user/account/delete/cancel
+			account.POST(route.USER_ACCOUNT_DELETE_CANCEL_ROUTE, h.cancelAccountDeletion)
+		}
+
+		// URL: /user/sessions
+		sessions := user.Group(route.USER_SESSIONS_ROUTE)
+		{
+			// URL: /user/sessions/get/all
+			sessions.POST(route.GET_ALL_ROUTE, h.getSessions)
+
+			// URL: /user/sessions/revoke
+			sessions.POST(route.USER_SESSIONS_REVOKE_ROUTE, h.revokeSession)
+
+			// URL: /user/sessions/revoke/other
+			sessions.POST(route.USER_SESSIONS_REVOKE_OTHER_ROUTE, h.revokeOtherSessions)
+		}
+
+		// URL: /user/2fa
+		twoFactor := user.Group(route.USER_2FA_ROUTE)
+		{
+			// URL: /user/2fa/get
+			twoFactor.POST(route.GET_ROUTE, h.getTwoFactorStatus)
+
+			// URL: /user/2fa/enroll
+			twoFactor.POST(route.USER_2FA_ENROLL_ROUTE, h.enrollTwoFactor)
+
+			// URL: /user/2fa/confirm
+			twoFactor.POST(route.USER_2FA_CONFIRM_ROUTE, h.confirmTwoFactor)
+
+			// URL: /user/2fa/disable
+			twoFactor.POST(route.USER_2FA_DISABLE_ROUTE, h.disableTwoFactor)
+
+			// URL: /user/2fa/recovery/regenerate
+			twoFactor.POST(route.USER_2FA_RECOVERY_ROUTE, h.regenerateRecoveryCodes)
+		}
+
+		// URL: /user/article
+		article := user.Group(route.USER_ARTICLE_ROUTE)
+		{
+			// URL: /user/article/create
+			article.POST(route.CREATE_ROUTE, h.createArticle)
+
+			// URL: /user/article/update
+			article.POST(route.UPDATE_ROUTE, h.updateArticle)
+
+			// URL: /user/article/delete
+			article.POST(route.DELETE_ROUTE, h.deleteArticle)
+
+			// URL: /user/article/get
+			article.POST(route.GET_ROUTE, h.getArticle)
+
+			// URL: /user/article/get/all
+			article.POST(route.GET_ALL_ROUTE, h.getArticles)
 		}
 
 		// URL: /user/company
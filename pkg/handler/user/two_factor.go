@@ -0,0 +1,183 @@
+package user
+
+import (
+	utilContext "main-server/pkg/handler/util"
+	httpModel "main-server/pkg/model/http"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary GetTwoFactorStatus
+// @Tags two-factor
+// @Description Получение состояния двухфакторной аутентификации пользователя
+// @ID user-2fa-get
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.TwoFactorStatusModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/2fa/get [post]
+func (h *UserHandler) getTwoFactorStatus(c *gin.Context) {
+	userId, userUuid, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.GetStatus(userModel.UserIdentityModel{
+		UserId:   userId,
+		UserUuid: userUuid,
+		DomainId: domainId,
+	})
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary EnrollTwoFactor
+// @Tags two-factor
+// @Description Начало подключения 2FA: секрет и URI (otpauth://) для QR-кода приложения-аутентификатора
+// @ID user-2fa-enroll
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Success 200 {object} userModel.TwoFactorEnrollModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/2fa/enroll [post]
+func (h *UserHandler) enrollTwoFactor(c *gin.Context) {
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.Enroll(userId)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary ConfirmTwoFactor
+// @Tags two-factor
+// @Description Подтверждение подключения 2FA кодом из приложения. Возвращает одноразовые коды восстановления (показываются один раз)
+// @ID user-2fa-confirm
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.TwoFactorCodeModel true "credentials"
+// @Success 200 {object} userModel.TwoFactorRecoveryCodesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/2fa/confirm [post]
+func (h *UserHandler) confirmTwoFactor(c *gin.Context) {
+	var input userModel.TwoFactorCodeModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.Confirm(userId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// @Summary DisableTwoFactor
+// @Tags two-factor
+// @Description Отключение 2FA (код TOTP или код восстановления). Недоступно, если 2FA обязательна для роли пользователя
+// @ID user-2fa-disable
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.TwoFactorCodeModel true "credentials"
+// @Success 200 {object} httpModel.ResponseValue "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/2fa/disable [post]
+func (h *UserHandler) disableTwoFactor(c *gin.Context) {
+	var input userModel.TwoFactorCodeModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, userUuid, domainId, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.Disable(userModel.UserIdentityModel{
+		UserId:   userId,
+		UserUuid: userUuid,
+		DomainId: domainId,
+	}, input)
+
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, httpModel.ResponseValue{
+		Value: data,
+	})
+}
+
+// @Summary RegenerateRecoveryCodes
+// @Tags two-factor
+// @Description Перевыпуск кодов восстановления (подтверждается кодом TOTP), ранее выданные коды становятся недействительными
+// @ID user-2fa-recovery-regenerate
+// @Accept  json
+// @Produce  json
+// @Param Authorization header string true "Токен доступа для текущего пользователя" example(Bearer access_token)
+// @Param input body userModel.TwoFactorCodeModel true "credentials"
+// @Success 200 {object} userModel.TwoFactorRecoveryCodesModel "data"
+// @Failure 400,404 {object} ResponseMessage
+// @Failure 500 {object} ResponseMessage
+// @Failure default {object} ResponseMessage
+// @Router /user/2fa/recovery/regenerate [post]
+func (h *UserHandler) regenerateRecoveryCodes(c *gin.Context) {
+	var input userModel.TwoFactorCodeModel
+	if err := c.BindJSON(&input); err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId, _, _, err := utilContext.GetContextUserInfo(c)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	data, err := h.services.TwoFactor.RegenerateRecoveryCodes(userId, input)
+	if err != nil {
+		utilContext.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"errors"
 	middlewareConstants "main-server/pkg/constant/middleware"
+	userModel "main-server/pkg/model/user"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -46,6 +47,63 @@ func GetContextUserInfo(c *gin.Context) (int, string, int, error) {
 	return usersId.(int), usersUuid.(string), domainsId.(int), nil
 }
 
+/* Получение идентификатора домена запроса из контекста */
+func GetContextDomainId(c *gin.Context) (int, error) {
+	domainsId, exist := c.Get(middlewareConstants.DOMAINS_ID)
+	if !exist {
+		return -1, errors.New("Домен не определён!")
+	}
+
+	return domainsId.(int), nil
+}
+
+/* Функция получения идентификатора сервисного клиента из контекста */
+func GetContextServiceClient(c *gin.Context) (string, error) {
+	clientId, exist := c.Get(middlewareConstants.SERVICE_CLIENT_CTX)
+
+	if !exist {
+		return "", errors.New("Нет доступа!")
+	}
+
+	return clientId.(string), nil
+}
+
+/* Получение данных об устройстве пользователя из запроса (для сессий) */
+func GetSessionInfo(c *gin.Context) userModel.SessionInfoModel {
+	deviceName := c.GetHeader(middlewareConstants.DEVICE_NAME_HEADER)
+	if deviceName == "" {
+		deviceName = c.Request.UserAgent()
+	}
+
+	if len(deviceName) > 256 {
+		deviceName = deviceName[:256]
+	}
+
+	domainsId, _ := GetContextDomainId(c)
+
+	return userModel.SessionInfoModel{
+		DeviceName: deviceName,
+		Ip:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		DomainsId:  domainsId,
+	}
+}
+
+/* Получение uuid текущей сессии пользователя (пустая строка для токенов без сессии) */
+func GetContextSessionUuid(c *gin.Context) string {
+	value, exist := c.Get(middlewareConstants.SESSION_UUID_CTX)
+	if !exist {
+		return ""
+	}
+
+	sessionUuid, ok := value.(*string)
+	if !ok || sessionUuid == nil {
+		return ""
+	}
+
+	return *sessionUuid
+}
+
 /* Структура сообщения об ошибке */
 type ResponseMessage struct {
 	Message string `json:"message" binding:"required"`
@@ -0,0 +1,149 @@
+package migration
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+/* Набор SQL-миграций, встроенных в исполняемый файл сервера */
+//go:embed sql/*.sql
+var migrations embed.FS
+
+const migrationsDir = "sql"
+
+/* Модель состояния отдельной миграции */
+type StatusModel struct {
+	Version    uint   `json:"version"`
+	Identifier string `json:"identifier"`
+	Applied    bool   `json:"applied"`
+}
+
+/* Модель общего состояния схемы базы данных */
+type SchemaStatusModel struct {
+	Version    uint          `json:"version"`
+	Dirty      bool          `json:"dirty"`
+	Migrations []StatusModel `json:"migrations"`
+}
+
+/* Структура для управления миграциями схемы базы данных */
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+/* Функция создания нового экземпляра Migrator поверх существующего подключения */
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	src, err := iofs.New(migrations, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{migrate: m}, nil
+}
+
+/* Применение миграций (steps <= 0 - применение всех доступных миграций) */
+func (m *Migrator) Up(steps int) error {
+	var err error
+
+	if steps <= 0 {
+		err = m.migrate.Up()
+	} else {
+		err = m.migrate.Steps(steps)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+/* Откат миграций (steps <= 0 - откат всех применённых миграций) */
+func (m *Migrator) Down(steps int) error {
+	var err error
+
+	if steps <= 0 {
+		err = m.migrate.Down()
+	} else {
+		err = m.migrate.Steps(-steps)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+/* Получение информации о состоянии схемы базы данных */
+func (m *Migrator) Status() (SchemaStatusModel, error) {
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return SchemaStatusModel{}, err
+	}
+
+	src, err := iofs.New(migrations, migrationsDir)
+	if err != nil {
+		return SchemaStatusModel{}, err
+	}
+	defer src.Close()
+
+	var list []StatusModel
+
+	current, err := src.First()
+	for err == nil {
+		body, identifier, readErr := src.ReadUp(current)
+		if readErr != nil {
+			return SchemaStatusModel{}, readErr
+		}
+		body.Close()
+
+		list = append(list, StatusModel{
+			Version:    current,
+			Identifier: identifier,
+			Applied:    current < version || (current == version && !dirty),
+		})
+
+		current, err = src.Next(current)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return SchemaStatusModel{}, err
+	}
+
+	return SchemaStatusModel{
+		Version:    version,
+		Dirty:      dirty,
+		Migrations: list,
+	}, nil
+}
+
+/* Принудительная установка версии схемы (используется для снятия флага dirty) */
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+/* Закрытие источника миграций и подключения к базе данных */
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.migrate.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+
+	return dbErr
+}
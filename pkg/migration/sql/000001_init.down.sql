@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS cb_projects;
+DROP TABLE IF EXISTS cb_workers;
+DROP TABLE IF EXISTS cb_companies;
+DROP TABLE IF EXISTS ac_rules;
+DROP TABLE IF EXISTS ac_objects;
+DROP TABLE IF EXISTS ac_types_objects;
+DROP TABLE IF EXISTS ac_roles;
+DROP TABLE IF EXISTS ac_domains;
+DROP TABLE IF EXISTS u_users_auth_types;
+DROP TABLE IF EXISTS u_auth_types;
+DROP TABLE IF EXISTS u_reset_tokens;
+DROP TABLE IF EXISTS u_tokens;
+DROP TABLE IF EXISTS u_activations;
+DROP TABLE IF EXISTS u_users_data;
+DROP TABLE IF EXISTS u_users;
@@ -0,0 +1,181 @@
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+/* Пользователи */
+CREATE TABLE IF NOT EXISTS u_users (
+    id       SERIAL PRIMARY KEY,
+    uuid     UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    email    VARCHAR(255) NOT NULL UNIQUE,
+    password TEXT NOT NULL
+);
+
+/* Пользовательские данные (профиль) */
+CREATE TABLE IF NOT EXISTS u_users_data (
+    id         SERIAL PRIMARY KEY,
+    data       JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    users_id   INTEGER NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE
+);
+
+/* Активация аккаунтов */
+CREATE TABLE IF NOT EXISTS u_activations (
+    id              SERIAL PRIMARY KEY,
+    users_id        INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    is_activated    BOOLEAN NOT NULL DEFAULT FALSE,
+    activation_link VARCHAR(255) NOT NULL UNIQUE
+);
+
+/* Токены доступа и обновления */
+CREATE TABLE IF NOT EXISTS u_tokens (
+    id            SERIAL PRIMARY KEY,
+    users_id      INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    access_token  TEXT NOT NULL,
+    refresh_token TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS u_tokens_users_id_idx ON u_tokens (users_id);
+
+/* Токены сброса пароля */
+CREATE TABLE IF NOT EXISTS u_reset_tokens (
+    id       SERIAL PRIMARY KEY,
+    users_id INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    token    TEXT NOT NULL
+);
+
+/* Типы аутентификации */
+CREATE TABLE IF NOT EXISTS u_auth_types (
+    id    SERIAL PRIMARY KEY,
+    uuid  UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    value VARCHAR(64) NOT NULL UNIQUE
+);
+
+/* Связь пользователей с типами аутентификации */
+CREATE TABLE IF NOT EXISTS u_users_auth_types (
+    id            SERIAL PRIMARY KEY,
+    users_id      INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    auth_types_id INTEGER NOT NULL REFERENCES u_auth_types (id) ON DELETE RESTRICT,
+    UNIQUE (users_id, auth_types_id)
+);
+
+/* Домены */
+CREATE TABLE IF NOT EXISTS ac_domains (
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    value       VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    users_id    INTEGER REFERENCES u_users (id) ON DELETE SET NULL
+);
+
+/* Роли */
+CREATE TABLE IF NOT EXISTS ac_roles (
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    value       VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    users_id    INTEGER REFERENCES u_users (id) ON DELETE SET NULL,
+    domains_id  INTEGER REFERENCES ac_domains (id) ON DELETE CASCADE,
+    UNIQUE (value, domains_id)
+);
+
+/* Типы информационных объектов */
+CREATE TABLE IF NOT EXISTS ac_types_objects (
+    id          SERIAL PRIMARY KEY,
+    value       VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    table_name  VARCHAR(255) NOT NULL,
+    users_id    INTEGER REFERENCES u_users (id) ON DELETE SET NULL
+);
+
+/* Информационные объекты */
+CREATE TABLE IF NOT EXISTS ac_objects (
+    id               SERIAL PRIMARY KEY,
+    value            VARCHAR(255) NOT NULL UNIQUE,
+    description      TEXT NOT NULL DEFAULT '',
+    parent_id        INTEGER REFERENCES ac_objects (id) ON DELETE CASCADE,
+    types_objects_id INTEGER NOT NULL REFERENCES ac_types_objects (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS ac_objects_parent_id_idx ON ac_objects (parent_id);
+
+/* Правила управления доступом (casbin) */
+CREATE TABLE IF NOT EXISTS ac_rules (
+    id    BIGSERIAL PRIMARY KEY,
+    ptype VARCHAR(100),
+    v0    VARCHAR(255),
+    v1    VARCHAR(255),
+    v2    VARCHAR(255),
+    v3    VARCHAR(255),
+    v4    VARCHAR(255),
+    v5    VARCHAR(255),
+    v6    VARCHAR(255),
+    v7    VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS ac_rules_ptype_v0_idx ON ac_rules (ptype, v0);
+CREATE INDEX IF NOT EXISTS ac_rules_v2_idx ON ac_rules (v2);
+
+/* Компании */
+CREATE TABLE IF NOT EXISTS cb_companies (
+    id         SERIAL PRIMARY KEY,
+    uuid       UUID NOT NULL UNIQUE,
+    data       JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    users_id   INTEGER REFERENCES u_users (id) ON DELETE SET NULL
+);
+
+/* Работники компаний */
+CREATE TABLE IF NOT EXISTS cb_workers (
+    id           SERIAL PRIMARY KEY,
+    uuid         UUID NOT NULL UNIQUE,
+    data         TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    users_id     INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    companies_id INTEGER NOT NULL REFERENCES cb_companies (id) ON DELETE CASCADE,
+    UNIQUE (users_id, companies_id)
+);
+
+/* Проекты компаний */
+CREATE TABLE IF NOT EXISTS cb_projects (
+    id           SERIAL PRIMARY KEY,
+    uuid         UUID NOT NULL UNIQUE,
+    data         JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    workers_id   INTEGER REFERENCES cb_workers (id) ON DELETE SET NULL,
+    companies_id INTEGER NOT NULL REFERENCES cb_companies (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cb_projects_companies_id_idx ON cb_projects (companies_id);
+
+/* Начальные данные */
+INSERT INTO ac_domains (value, description) VALUES
+    ('rental_housing', 'Основной домен приложения "Rental housing"')
+ON CONFLICT (value) DO NOTHING;
+
+INSERT INTO ac_roles (value, description, domains_id)
+SELECT r.value, r.description, d.id
+FROM (VALUES
+    ('client', 'Клиент'),
+    ('builder_admin', 'Администратор компании-застройщика'),
+    ('builder_manager', 'Менеджер компании-застройщика'),
+    ('manager', 'Менеджер системы'),
+    ('admin', 'Администратор системы'),
+    ('super_admin', 'Супер-администратор системы')
+) AS r (value, description)
+CROSS JOIN ac_domains d
+WHERE d.value = 'rental_housing'
+ON CONFLICT (value, domains_id) DO NOTHING;
+
+INSERT INTO ac_types_objects (value, description, table_name) VALUES
+    ('company', 'Компания', 'cb_companies'),
+    ('project', 'Проект компании', 'cb_projects'),
+    ('entity', 'Объект проекта (здание)', 'cb_entities'),
+    ('sub_entity', 'Подобъект (квартира, помещение)', 'cb_sub_entities')
+ON CONFLICT (value) DO NOTHING;
+
+INSERT INTO u_auth_types (value) VALUES
+    ('local'),
+    ('google')
+ON CONFLICT (value) DO NOTHING;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cb_entities;
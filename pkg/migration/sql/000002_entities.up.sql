@@ -0,0 +1,11 @@
+/* Объекты проектов (здания, блоки) */
+CREATE TABLE IF NOT EXISTS cb_entities (
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL UNIQUE,
+    data        JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    projects_id INTEGER NOT NULL REFERENCES cb_projects (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cb_entities_projects_id_idx ON cb_entities (projects_id);
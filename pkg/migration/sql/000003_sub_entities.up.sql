@@ -0,0 +1,18 @@
+/* Помещения объектов (квартиры, юниты), сдаваемые в аренду */
+CREATE TABLE IF NOT EXISTS cb_sub_entities (
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL UNIQUE,
+    data        JSONB NOT NULL DEFAULT '{}'::jsonb,
+    rooms       INTEGER NOT NULL CHECK (rooms >= 0),
+    area        NUMERIC(10, 2) NOT NULL CHECK (area > 0),
+    floor       INTEGER NOT NULL DEFAULT 0,
+    price       NUMERIC(14, 2) NOT NULL CHECK (price >= 0),
+    status      VARCHAR(32) NOT NULL DEFAULT 'available'
+                CHECK (status IN ('available', 'reserved', 'rented')),
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    entities_id INTEGER NOT NULL REFERENCES cb_entities (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cb_sub_entities_entities_id_idx ON cb_sub_entities (entities_id);
+CREATE INDEX IF NOT EXISTS cb_sub_entities_status_idx ON cb_sub_entities (status);
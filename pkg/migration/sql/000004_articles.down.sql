@@ -0,0 +1,5 @@
+DELETE FROM ac_roles WHERE value = 'moderator';
+
+DROP TABLE IF EXISTS a_articles_files;
+DROP TABLE IF EXISTS a_files;
+DROP TABLE IF EXISTS a_articles;
@@ -0,0 +1,44 @@
+/* Статьи пользователей */
+CREATE TABLE IF NOT EXISTS a_articles (
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    users_id    INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    filepath    TEXT NOT NULL DEFAULT '',
+    filename    TEXT NOT NULL DEFAULT '',
+    title       TEXT NOT NULL,
+    text        TEXT NOT NULL,
+    tags        TEXT NOT NULL DEFAULT '',
+    status      VARCHAR(32) NOT NULL DEFAULT 'unchecked'
+                CHECK (status IN ('unchecked', 'approved', 'rejected')),
+    comment     TEXT,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS a_articles_users_id_idx ON a_articles (users_id);
+CREATE INDEX IF NOT EXISTS a_articles_status_idx ON a_articles (status);
+
+/* Файлы, загруженные пользователями */
+CREATE TABLE IF NOT EXISTS a_files (
+    id          SERIAL PRIMARY KEY,
+    filename    TEXT NOT NULL,
+    filepath    TEXT NOT NULL,
+    users_id    INTEGER REFERENCES u_users (id) ON DELETE SET NULL
+);
+
+/* Файлы, прикреплённые к статьям */
+CREATE TABLE IF NOT EXISTS a_articles_files (
+    id          SERIAL PRIMARY KEY,
+    articles_id INTEGER NOT NULL REFERENCES a_articles (id) ON DELETE CASCADE,
+    files_id    INTEGER NOT NULL REFERENCES a_files (id) ON DELETE CASCADE,
+    index       INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS a_articles_files_articles_id_idx ON a_articles_files (articles_id);
+
+/* Роль модератора статей */
+INSERT INTO ac_roles (value, description, domains_id)
+SELECT 'moderator', 'Модератор', d.id
+FROM ac_domains d
+WHERE d.value = 'rental_housing'
+ON CONFLICT (value, domains_id) DO NOTHING;
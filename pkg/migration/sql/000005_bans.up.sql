@@ -0,0 +1,13 @@
+/* Блокировки пользователей (expires_at = NULL - бессрочная блокировка) */
+CREATE TABLE IF NOT EXISTS u_bans (
+    id          SERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    users_id    INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    admins_id   INTEGER REFERENCES u_users (id) ON DELETE SET NULL,
+    reason      TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMP,
+    revoked_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS u_bans_users_id_idx ON u_bans (users_id);
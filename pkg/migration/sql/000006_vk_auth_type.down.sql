@@ -0,0 +1,4 @@
+DELETE FROM u_users_auth_types
+WHERE auth_types_id IN (SELECT id FROM u_auth_types WHERE value = 'vk');
+
+DELETE FROM u_auth_types WHERE value = 'vk';
@@ -0,0 +1,4 @@
+/* Тип аутентификации через VK OAuth2 */
+INSERT INTO u_auth_types (value) VALUES
+    ('vk')
+ON CONFLICT (value) DO NOTHING;
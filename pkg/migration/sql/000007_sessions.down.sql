@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS u_tokens_refresh_token_idx;
+DROP INDEX IF EXISTS u_tokens_sessions_id_idx;
+
+ALTER TABLE u_tokens DROP COLUMN IF EXISTS used_at;
+ALTER TABLE u_tokens DROP COLUMN IF EXISTS sessions_id;
+
+DROP TABLE IF EXISTS u_sessions;
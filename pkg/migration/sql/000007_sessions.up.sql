@@ -0,0 +1,21 @@
+/* Сессии пользователей (отдельная сессия для каждого устройства) */
+CREATE TABLE IF NOT EXISTS u_sessions (
+    id           SERIAL PRIMARY KEY,
+    uuid         UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    users_id     INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    device_name  VARCHAR(256) NOT NULL DEFAULT '',
+    ip           VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent   TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NOT NULL,
+    revoked_at   TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS u_sessions_users_id_idx ON u_sessions (users_id);
+
+/* Токены привязываются к сессии; used_at - токен обновления уже был использован (ротация) */
+ALTER TABLE u_tokens ADD COLUMN IF NOT EXISTS sessions_id INTEGER NULL REFERENCES u_sessions (id) ON DELETE CASCADE;
+ALTER TABLE u_tokens ADD COLUMN IF NOT EXISTS used_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS u_tokens_sessions_id_idx ON u_tokens (sessions_id);
+CREATE INDEX IF NOT EXISTS u_tokens_refresh_token_idx ON u_tokens (refresh_token);
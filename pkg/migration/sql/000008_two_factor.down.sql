@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS ac_roles_two_factor;
+DROP TABLE IF EXISTS u_two_factor_challenges;
+DROP TABLE IF EXISTS u_recovery_codes;
+DROP TABLE IF EXISTS u_two_factor;
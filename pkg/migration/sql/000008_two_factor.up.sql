@@ -0,0 +1,37 @@
+/* Секреты TOTP пользователей (confirmed_at = NULL - подключение не подтверждено) */
+CREATE TABLE IF NOT EXISTS u_two_factor (
+    id           SERIAL PRIMARY KEY,
+    users_id     INTEGER NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE,
+    secret       TEXT NOT NULL,
+    last_counter BIGINT NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    confirmed_at TIMESTAMP
+);
+
+/* Одноразовые коды восстановления (хранится только хэш кода) */
+CREATE TABLE IF NOT EXISTS u_recovery_codes (
+    id        SERIAL PRIMARY KEY,
+    users_id  INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS u_recovery_codes_users_id_idx ON u_recovery_codes (users_id);
+
+/* Промежуточные токены второго шага авторизации (хранится только хэш токена) */
+CREATE TABLE IF NOT EXISTS u_two_factor_challenges (
+    id          SERIAL PRIMARY KEY,
+    users_id    INTEGER NOT NULL REFERENCES u_users (id) ON DELETE CASCADE,
+    token_hash  TEXT NOT NULL UNIQUE,
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMP NOT NULL
+);
+
+/* Роли, для которых двухфакторная аутентификация обязательна */
+CREATE TABLE IF NOT EXISTS ac_roles_two_factor (
+    id         SERIAL PRIMARY KEY,
+    roles_id   INTEGER NOT NULL UNIQUE REFERENCES ac_roles (id) ON DELETE CASCADE,
+    users_id   INTEGER REFERENCES u_users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,14 @@
+/*
+ * Счётчики неудачных попыток авторизации (общие для всех экземпляров сервера).
+ * scope - account (email) или ip, action - sign_in, recovery, reset
+ */
+CREATE TABLE IF NOT EXISTS u_auth_throttle (
+    id              SERIAL PRIMARY KEY,
+    scope           VARCHAR(16) NOT NULL,
+    key             VARCHAR(255) NOT NULL,
+    action          VARCHAR(32) NOT NULL,
+    failures        INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMP,
+    UNIQUE (scope, key, action)
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS u_activations_users_id_idx;
+
+ALTER TABLE u_activations DROP COLUMN IF EXISTS activated_at;
+ALTER TABLE u_activations DROP COLUMN IF EXISTS last_sent_at;
+ALTER TABLE u_activations DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE u_activations DROP COLUMN IF EXISTS created_at;
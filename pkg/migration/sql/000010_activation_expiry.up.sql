@@ -0,0 +1,10 @@
+/*
+ * Срок действия ссылок активации (expires_at = NULL - ссылка бессрочная, для ранее созданных записей),
+ * время последней отправки письма (для ограничения повторной отправки) и время активации
+ */
+ALTER TABLE u_activations ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE u_activations ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE u_activations ADD COLUMN IF NOT EXISTS last_sent_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE u_activations ADD COLUMN IF NOT EXISTS activated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS u_activations_users_id_idx ON u_activations (users_id);
@@ -0,0 +1,13 @@
+/*
+ * Асимметричные ключи подписи токенов доступа (закрытый и открытый ключ в PEM).
+ * retired_at - ключ больше не используется для подписи, но публикуется в JWKS для проверки
+ */
+CREATE TABLE IF NOT EXISTS u_signing_keys (
+    id          SERIAL PRIMARY KEY,
+    kid         VARCHAR(64) NOT NULL UNIQUE,
+    algorithm   VARCHAR(16) NOT NULL,
+    private_key TEXT NOT NULL,
+    public_key  TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    retired_at  TIMESTAMP
+);
@@ -0,0 +1,13 @@
+/*
+ * Сервисные клиенты (OAuth2 client credentials) для API /service.
+ * secret_hash - SHA-256 секрета клиента, scopes - разрешённые области доступа через пробел
+ */
+CREATE TABLE IF NOT EXISTS u_service_clients (
+    id          SERIAL PRIMARY KEY,
+    client_id   VARCHAR(64) NOT NULL UNIQUE,
+    name        VARCHAR(256) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    scopes      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at  TIMESTAMP
+);
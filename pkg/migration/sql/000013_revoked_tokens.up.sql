@@ -0,0 +1,11 @@
+/*
+ * Отозванные токены доступа (по идентификатору jti) до истечения их срока действия
+ */
+CREATE TABLE IF NOT EXISTS u_revoked_tokens (
+    id         SERIAL PRIMARY KEY,
+    jti        VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS u_revoked_tokens_expires_at_idx ON u_revoked_tokens (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS u_users_auth_types_subject_idx;
+
+ALTER TABLE u_users_auth_types DROP COLUMN IF EXISTS created_at;
+ALTER TABLE u_users_auth_types DROP COLUMN IF EXISTS email;
+ALTER TABLE u_users_auth_types DROP COLUMN IF EXISTS subject;
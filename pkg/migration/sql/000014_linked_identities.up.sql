@@ -0,0 +1,18 @@
+/*
+ * Привязанные способы входа: идентификатор пользователя у провайдера (subject = NULL - локальный вход
+ * или привязка, созданная до появления идентификаторов), email-адрес на стороне провайдера и время привязки
+ */
+ALTER TABLE u_users_auth_types ADD COLUMN IF NOT EXISTS subject VARCHAR(255);
+ALTER TABLE u_users_auth_types ADD COLUMN IF NOT EXISTS email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE u_users_auth_types ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+/* Учётная запись провайдера может быть привязана только к одному пользователю */
+CREATE UNIQUE INDEX IF NOT EXISTS u_users_auth_types_subject_idx ON u_users_auth_types (auth_types_id, subject)
+    WHERE subject IS NOT NULL;
+
+/* Ранее при входе через провайдера в поле пароля записывался токен доступа провайдера */
+UPDATE u_users SET password = '' WHERE id NOT IN (
+    SELECT tl.users_id FROM u_users_auth_types tl
+    INNER JOIN u_auth_types tat ON tat.id = tl.auth_types_id
+    WHERE tat.value = 'local'
+);
@@ -0,0 +1,12 @@
+/*
+ * Запросы пользователей на удаление аккаунта: до scheduled_at запрос можно отменить,
+ * после - аккаунт удаляется фоновой задачей
+ */
+CREATE TABLE IF NOT EXISTS u_account_deletions (
+    id           SERIAL PRIMARY KEY,
+    users_id     INTEGER NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE,
+    requested_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    scheduled_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS u_account_deletions_scheduled_at_idx ON u_account_deletions (scheduled_at);
@@ -0,0 +1,13 @@
+/*
+ * Запросы на смену email-адреса: confirm_token отправляется на новый адрес, cancel_token - на текущий
+ * (хранятся SHA-256 хэши токенов)
+ */
+CREATE TABLE IF NOT EXISTS u_email_changes (
+    id           SERIAL PRIMARY KEY,
+    users_id     INTEGER NOT NULL UNIQUE REFERENCES u_users (id) ON DELETE CASCADE,
+    new_email    VARCHAR(255) NOT NULL,
+    confirm_hash VARCHAR(64) NOT NULL UNIQUE,
+    cancel_hash  VARCHAR(64) NOT NULL UNIQUE,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMP NOT NULL
+);
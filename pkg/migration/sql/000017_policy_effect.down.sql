@@ -0,0 +1,2 @@
+DELETE FROM ac_rules WHERE ptype = 'p' AND v4 = 'deny';
+UPDATE ac_rules SET v4 = NULL WHERE ptype = 'p' AND v4 = 'allow';
@@ -0,0 +1,2 @@
+/* Эффект правила управления доступом (v4): allow - разрешение, deny - явный запрет */
+UPDATE ac_rules SET v4 = 'allow' WHERE ptype = 'p' AND COALESCE(v4, '') = '';
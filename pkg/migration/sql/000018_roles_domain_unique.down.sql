@@ -0,0 +1 @@
+DROP INDEX IF EXISTS ac_roles_value_without_domain_key;
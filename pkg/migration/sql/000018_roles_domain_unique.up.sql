@@ -0,0 +1,5 @@
+/*
+ * Значения ролей уникальны в пределах домена: системные роли (client, admin, ...) создаются в каждом домене.
+ * Ограничение UNIQUE (value, domains_id) не действует для ролей без домена (NULL), для них - отдельный индекс
+ */
+CREATE UNIQUE INDEX IF NOT EXISTS ac_roles_value_without_domain_key ON ac_roles (value) WHERE domains_id IS NULL;
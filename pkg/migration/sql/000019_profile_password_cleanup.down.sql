@@ -0,0 +1,2 @@
+/* Удалённые пароли не восстанавливаются */
+SELECT 1;
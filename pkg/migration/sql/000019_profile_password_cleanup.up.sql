@@ -0,0 +1,2 @@
+/* Удаление пароля в открытом виде, ранее сохранявшегося в данных профиля при его изменении */
+UPDATE u_users_data SET data = data - 'password' WHERE data ? 'password';
@@ -0,0 +1,4 @@
+ALTER TABLE u_two_factor_challenges
+    DROP COLUMN IF EXISTS auth_types_id,
+    DROP COLUMN IF EXISTS token_api,
+    DROP COLUMN IF EXISTS token_api_refresh;
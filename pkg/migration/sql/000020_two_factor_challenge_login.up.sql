@@ -0,0 +1,8 @@
+/*
+ * Способ входа первого шага авторизации: 2FA проверяется при входе через любой тип аутентификации,
+ * после второго шага сессия создаётся с тем же типом аутентификации и токенами внешнего провайдера
+ */
+ALTER TABLE u_two_factor_challenges
+    ADD COLUMN IF NOT EXISTS auth_types_id     INTEGER REFERENCES u_auth_types (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS token_api         TEXT,
+    ADD COLUMN IF NOT EXISTS token_api_refresh TEXT;
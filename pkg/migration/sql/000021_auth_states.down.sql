@@ -0,0 +1 @@
+DROP TABLE IF EXISTS u_auth_states;
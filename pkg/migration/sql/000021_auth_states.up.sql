@@ -0,0 +1,16 @@
+/*
+ * Параметры авторизационных запросов к внешним провайдерам (state, PKCE, nonce).
+ * Хранятся в БД, чтобы обратный вызов провайдера мог обработать любой экземпляр сервера (хранится только хэш state)
+ */
+CREATE TABLE IF NOT EXISTS u_auth_states (
+    id            SERIAL PRIMARY KEY,
+    state_hash    TEXT NOT NULL UNIQUE,
+    provider      TEXT NOT NULL,
+    users_id      INTEGER REFERENCES u_users (id) ON DELETE CASCADE,
+    code_verifier TEXT NOT NULL,
+    nonce         TEXT NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS u_auth_states_expires_at_idx ON u_auth_states (expires_at);
@@ -22,13 +22,23 @@ type SystemPermissionModel struct {
 type PermissionModel struct {
 	ObjectUuid string   `json:"object_uuid" binding:"required"`
 	ActionList []string `json:"action_list" binding:"required"`
+	Effect     string   `json:"effect"` // allow (по умолчанию) или deny - явный запрет, в том числе для дочерних объектов
+}
+
+/* Эффект правила (allow, если не указан) */
+func (pm *PermissionModel) GetEffect() string {
+	if pm.Effect == "" {
+		return actionConstant.ALLOW
+	}
+
+	return pm.Effect
 }
 
 /* Метод проверки модели зависимостей */
 func (smm *SystemPermissionModel) Check(db *sqlx.DB) error {
 	slice := actionConstant.GetSlice()
 
-	query := fmt.Sprintf(`SELECT value FROM %s`, tableConstant.AC_OBJECTS)
+	query := fmt.Sprintf(`SELECT value FROM %s WHERE value = $1`, tableConstant.AC_OBJECTS)
 	if len(smm.PermissionList) > 0 {
 		for _, item := range smm.PermissionList {
 			var objects []rbacModel.ObjectDbModel
@@ -41,6 +51,10 @@ func (smm *SystemPermissionModel) Check(db *sqlx.DB) error {
 				return errors.New(fmt.Sprintf("Ошибка: объекта с ID = %s нет в базе данных", item.ObjectUuid))
 			}
 
+			if effect := item.GetEffect(); effect != actionConstant.ALLOW && effect != actionConstant.DENY {
+				return errors.New(fmt.Sprintf("Ошибка: эффект правила должен быть %s или %s", actionConstant.ALLOW, actionConstant.DENY))
+			}
+
 			if len(item.ActionList) > 0 {
 				for _, subItem := range item.ActionList {
 					if exists, _ := util.InArray(subItem, slice); !exists {
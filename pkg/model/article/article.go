@@ -41,6 +41,8 @@ type ArticleModel struct {
 	Title     string                 `json:"title" binding:"required"`
 	Text      string                 `json:"text" binding:"required"`
 	Tags      string                 `json:"tags" binding:"required"`
+	Status    string                 `json:"status" binding:"required"`
+	Comment   *string                `json:"comment"`
 	Files     []ArticlesFilesDBModel `json:"files" binding:"required"`
 	CreatedAt time.Time              `json:"created_at" binding:"required"`
 	UpdatedAt time.Time              `json:"updated_at" binding:"required"`
@@ -48,6 +50,19 @@ type ArticleModel struct {
 
 type ArticlesModel struct {
 	Articles []ArticleModel `json:"articles" binding:"required"`
+	Count    int            `json:"count" binding:"required"`
+}
+
+/* Model data for get any count articles */
+type ArticleCountModel struct {
+	Limit int `json:"limit" binding:"required"`
+	Count int `json:"count"`
+}
+
+/* Model data for moderation of article (comment is a reason of rejection) */
+type ArticleModerationModel struct {
+	Uuid    string  `json:"uuid" binding:"required"`
+	Comment *string `json:"comment"`
 }
 
 type ArticleUuidModel struct {
@@ -63,6 +78,8 @@ type ArticleDBModel struct {
 	Title     string    `json:"title" binding:"required" db:"title"`
 	Text      string    `json:"text" binding:"required" db:"text"`
 	Tags      string    `json:"tags" binding:"required" db:"tags"`
+	Status    string    `json:"status" binding:"required" db:"status"`
+	Comment   *string   `json:"comment" db:"comment"`
 	CreatedAt time.Time `json:"created_at" binding:"required" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" binding:"required" db:"updated_at"`
 }
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+)
+
+/* Модель данных для создания нового объекта проекта */
+type EntityCreateModel struct {
+	ProjectUuid string  `json:"project_uuid" binding:"required"`
+	Logo        *string `json:"logo"`
+	Title       string  `json:"title" binding:"required"`
+	Description string  `json:"description" binding:"required"`
+	Address     string  `json:"address" binding:"required"`
+	Floors      int     `json:"floors"`
+}
+
+/* Модель данных для обновления объекта проекта */
+type EntityUpdateModel struct {
+	Uuid        string `json:"uuid" binding:"required"`
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	Address     string `json:"address" binding:"required"`
+	Floors      int    `json:"floors"`
+}
+
+/* Модель данных объекта (элемент data, jsonb) */
+type EntityDataModel struct {
+	Logo        *string `json:"logo" db:"logo"`
+	Title       string  `json:"title" binding:"required" db:"title"`
+	Description string  `json:"description" binding:"required" db:"description"`
+	Address     string  `json:"address" binding:"required" db:"address"`
+	Floors      int     `json:"floors" db:"floors"`
+}
+
+type EntityUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Модель для получения среза объектов проекта (uuid - идентификатор проекта) */
+type EntityCountModel struct {
+	Uuid  string `json:"uuid" binding:"required"`
+	Limit int    `json:"limit" binding:"required"`
+	Count int    `json:"count"`
+}
+
+type EntityLowInfoModel struct {
+	Uuid      string    `json:"uuid" db:"uuid"`
+	Data      string    `json:"data" db:"data"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type EntityDbDataEx struct {
+	Uuid      string          `json:"uuid" db:"uuid"`
+	Data      EntityDataModel `json:"data" db:"data"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type EntityAnyCountModel struct {
+	Entities []EntityDbDataEx `json:"entities" binding:"required"`
+	Count    int              `json:"count" binding:"required"`
+}
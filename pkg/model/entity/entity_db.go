@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+/*
+ * Модели, использующиеся для взаимодействия с таблицей cb_entities
+ */
+
+/* Основная модель */
+type EntityDbModel struct {
+	Id         int       `json:"id" db:"id"`
+	Uuid       string    `json:"uuid" db:"uuid"`
+	Data       string    `json:"data" db:"data"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ProjectsId int       `json:"projects_id" db:"projects_id"`
+}
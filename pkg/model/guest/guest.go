@@ -0,0 +1,117 @@
+package guest
+
+import (
+	"time"
+)
+
+/*
+ * Модели публичного каталога (доступны без авторизации).
+ * Содержат только публичные поля компаний, проектов и помещений.
+ */
+
+/* Курсор постраничной навигации (сортировка, значение поля сортировки и идентификатор записи) */
+type CursorModel struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Id    int    `json:"id"`
+}
+
+type GuestUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Публичные данные компании */
+type GuestCompanyDataModel struct {
+	Logo         string `json:"logo"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Phone        string `json:"phone"`
+	Link         string `json:"link"`
+	EmailCompany string `json:"email_company"`
+}
+
+type GuestCompanyModel struct {
+	Uuid      string                `json:"uuid"`
+	Data      GuestCompanyDataModel `json:"data"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+/* Публичные данные проекта */
+type GuestProjectDataModel struct {
+	Logo        *string `json:"logo"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+}
+
+type GuestProjectModel struct {
+	Uuid        string                `json:"uuid"`
+	CompanyUuid string                `json:"company_uuid"`
+	Data        GuestProjectDataModel `json:"data"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+/* Публичные данные помещения */
+type GuestSubEntityModel struct {
+	Uuid         string    `json:"uuid"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Rooms        int       `json:"rooms"`
+	Area         float64   `json:"area"`
+	Floor        int       `json:"floor"`
+	Price        float64   `json:"price"`
+	Status       string    `json:"status"`
+	Address      string    `json:"address"`
+	EntityUuid   string    `json:"entity_uuid"`
+	EntityTitle  string    `json:"entity_title"`
+	ProjectUuid  string    `json:"project_uuid"`
+	CompanyUuid  string    `json:"company_uuid"`
+	CompanyTitle string    `json:"company_title"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+/* Модель запроса списка компаний */
+type GuestCompanyQueryModel struct {
+	Title  *string `json:"title"`
+	Limit  int     `json:"limit" binding:"required,min=1,max=100"`
+	Cursor *string `json:"cursor"`
+}
+
+/* Модель запроса списка проектов */
+type GuestProjectQueryModel struct {
+	CompanyUuid *string `json:"company_uuid"`
+	Limit       int     `json:"limit" binding:"required,min=1,max=100"`
+	Cursor      *string `json:"cursor"`
+}
+
+/* Модель запроса списка доступных помещений */
+type GuestSubEntityQueryModel struct {
+	CompanyUuid *string  `json:"company_uuid"`
+	ProjectUuid *string  `json:"project_uuid"`
+	Address     *string  `json:"address"`
+	PriceMin    *float64 `json:"price_min" binding:"omitempty,min=0"`
+	PriceMax    *float64 `json:"price_max" binding:"omitempty,min=0"`
+	RoomsMin    *int     `json:"rooms_min" binding:"omitempty,min=0"`
+	RoomsMax    *int     `json:"rooms_max" binding:"omitempty,min=0"`
+	SortBy      string   `json:"sort_by" binding:"omitempty,oneof=price area rooms created_at"`
+	SortOrder   string   `json:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Limit       int      `json:"limit" binding:"required,min=1,max=100"`
+	Cursor      *string  `json:"cursor"`
+}
+
+type GuestCompanyAnyCountModel struct {
+	Companies  []GuestCompanyModel `json:"companies"`
+	Count      int                 `json:"count"`
+	NextCursor *string             `json:"next_cursor"`
+}
+
+type GuestProjectAnyCountModel struct {
+	Projects   []GuestProjectModel `json:"projects"`
+	Count      int                 `json:"count"`
+	NextCursor *string             `json:"next_cursor"`
+}
+
+type GuestSubEntityAnyCountModel struct {
+	SubEntities []GuestSubEntityModel `json:"sub_entities"`
+	Count       int                   `json:"count"`
+	NextCursor  *string               `json:"next_cursor"`
+}
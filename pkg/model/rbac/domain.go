@@ -7,3 +7,22 @@ type DomainModel struct {
 	Description string  `json:"description" db:"description"`
 	UsersId     *string `json:"users_id" db:"users_id"`
 }
+
+type DomainsModel struct {
+	Domains []DomainModel `json:"domains"`
+}
+
+type DomainCreateModel struct {
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+type DomainUpdateModel struct {
+	Uuid        string  `json:"uuid" binding:"required"`
+	Value       *string `json:"value"`
+	Description *string `json:"description"`
+}
+
+type DomainUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
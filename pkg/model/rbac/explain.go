@@ -0,0 +1,51 @@
+package rbac
+
+/* Запрос объяснения решения о доступе текущего пользователя к объекту */
+type AccessExplainModel struct {
+	ObjectUuid string  `json:"object_uuid" binding:"required"`
+	Action     string  `json:"action" binding:"required"`
+	Role       *string `json:"role"` // Роль, наличие которой проверяется (необязательно)
+}
+
+/* Запрос объяснения решения о доступе произвольного пользователя (для администратора) */
+type AccessExplainUserModel struct {
+	UserUuid string `json:"user_uuid" binding:"required"`
+	AccessExplainModel
+}
+
+/* Правило группировки (g): субъект входит в роль в домене */
+type AccessGroupingModel struct {
+	Rule       []string `json:"rule"`                  // Субъект, роль, домен
+	RoleId     int      `json:"role_id"`               // Идентификатор роли
+	RoleValue  string   `json:"role_value"`            // Значение роли
+	ObjectUuid string   `json:"object_uuid,omitempty"` // Объект, в контексте которого действует роль (GPSubjectModel)
+}
+
+/* Правило доступа (p) субъекта или одной из его ролей к объекту или его родительскому ресурсу */
+type AccessRuleModel struct {
+	Rule    []string `json:"rule"`    // Субъект, домен, объект, действие, эффект
+	Matched bool     `json:"matched"` // Правило применимо к запрашиваемому действию
+}
+
+/* Результат проверки наличия роли */
+type AccessRoleCheckModel struct {
+	Value  string `json:"value"`
+	RoleId int    `json:"role_id"`
+	Has    bool   `json:"has"`
+}
+
+/* Объяснение решения о доступе */
+type AccessExplainResultModel struct {
+	Allowed  bool                  `json:"allowed"`           // Решение casbin
+	Subject  string                `json:"subject"`           // Субъект (идентификатор пользователя)
+	Domain   string                `json:"domain"`            // Домен
+	Object   string                `json:"object"`            // Запрашиваемый объект
+	Action   string                `json:"action"`            // Запрашиваемое действие
+	Objects  []string              `json:"objects,omitempty"` // Объект и его родительские ресурсы, права на которые наследуются
+	Roles    []AccessGroupingModel `json:"roles"`             // Цепочка ролей субъекта
+	Rules    []AccessRuleModel     `json:"rules"`             // Правила субъекта и его ролей для объектов цепочки
+	Decisive []string              `json:"decisive"`          // Правило, определившее решение
+	Missing  []string              `json:"missing"`           // Правило, которого не хватает для доступа
+	Reason   string                `json:"reason"`            // Пояснение решения
+	Role     *AccessRoleCheckModel `json:"role,omitempty"`    // Проверка роли (если роль указана в запросе)
+}
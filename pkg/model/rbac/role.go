@@ -26,3 +26,23 @@ type RoleValueModel struct {
 type RolesUserModel struct {
 	Roles []string `json:"roles" binding:"required"`
 }
+
+type RolesModel struct {
+	Roles []RoleModel `json:"roles"`
+}
+
+type RoleCreateModel struct {
+	DomainUuid  string `json:"domain_uuid" binding:"required"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+type RoleUpdateModel struct {
+	Uuid        string  `json:"uuid" binding:"required"`
+	Value       *string `json:"value"`
+	Description *string `json:"description"`
+}
+
+type RoleUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
@@ -0,0 +1,21 @@
+package rbac
+
+type TypeObjectsModel struct {
+	Types []TypeObjectDbModel `json:"types"`
+}
+
+type TypeObjectCreateModel struct {
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+	TableName   string `json:"table_name" binding:"required"`
+}
+
+type TypeObjectUpdateModel struct {
+	Value       string  `json:"value" binding:"required"`
+	Description *string `json:"description"`
+	TableName   *string `json:"table_name"`
+}
+
+type TypeObjectValueModel struct {
+	Value string `json:"value" binding:"required"`
+}
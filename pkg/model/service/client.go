@@ -0,0 +1,89 @@
+package service
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+/* Основная модель таблицы u_service_clients */
+type ServiceClientDbModel struct {
+	Id         int        `json:"id" db:"id"`
+	ClientId   string     `json:"client_id" db:"client_id"`
+	Name       string     `json:"name" db:"name"`
+	SecretHash string     `json:"secret_hash" db:"secret_hash"`
+	Scopes     string     `json:"scopes" db:"scopes"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+/* Регистрация нового сервисного клиента */
+type ServiceClientCreateModel struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required"`
+}
+
+/* Идентификатор сервисного клиента */
+type ServiceClientIdModel struct {
+	ClientId string `json:"client_id" binding:"required"`
+}
+
+/* Данные нового клиента (секрет возвращается только один раз - при создании) */
+type ServiceClientSecretModel struct {
+	ClientId     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Name         string   `json:"name"`
+	Scopes       []string `json:"scopes"`
+}
+
+type ServiceClientModel struct {
+	ClientId  string     `json:"client_id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+type ServiceClientsModel struct {
+	Clients []ServiceClientModel `json:"clients"`
+}
+
+/* Запрос токена по OAuth2 client credentials (RFC 6749, раздел 4.4) */
+type ServiceTokenRequestModel struct {
+	GrantType    string `json:"grant_type" form:"grant_type" binding:"required"`
+	ClientId     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"`
+}
+
+/* Токен доступа сервисного клиента */
+type ServiceTokenModel struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+/* Ошибка конечной точки выдачи токена (RFC 6749, раздел 5.2) */
+type ServiceTokenErrorModel struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+/* Полезные данные токена сервисного клиента (общие для выдачи и проверки токена) */
+type ServiceTokenClaims struct {
+	jwt.StandardClaims
+	ClientId string `json:"client_id"` // Идентификатор сервисного клиента
+	Scope    string `json:"scope"`     // Выданные области доступа (через пробел)
+}
+
+/* Данные, полученные из токена сервисного клиента */
+type ServiceTokenOutputParse struct {
+	ClientId string
+	Scopes   []string
+}
+
+/* Токен доступа пользователя для проверки */
+type TokenVerifyInputModel struct {
+	AccessToken string `json:"access_token" binding:"required"`
+}
@@ -0,0 +1,64 @@
+package sub_entity
+
+import (
+	"time"
+)
+
+/* Модель данных для создания нового помещения объекта */
+type SubEntityCreateModel struct {
+	EntityUuid  string  `json:"entity_uuid" binding:"required"`
+	Title       string  `json:"title" binding:"required"`
+	Description string  `json:"description"`
+	Rooms       int     `json:"rooms" binding:"min=0"`
+	Area        float64 `json:"area" binding:"required,gt=0"`
+	Floor       int     `json:"floor"`
+	Price       float64 `json:"price" binding:"min=0"`
+	Status      string  `json:"status" binding:"omitempty,oneof=available reserved rented"`
+}
+
+/* Модель данных для обновления помещения объекта */
+type SubEntityUpdateModel struct {
+	Uuid        string  `json:"uuid" binding:"required"`
+	Title       string  `json:"title" binding:"required"`
+	Description string  `json:"description"`
+	Rooms       int     `json:"rooms" binding:"min=0"`
+	Area        float64 `json:"area" binding:"required,gt=0"`
+	Floor       int     `json:"floor"`
+	Price       float64 `json:"price" binding:"min=0"`
+	Status      string  `json:"status" binding:"required,oneof=available reserved rented"`
+}
+
+/* Модель данных помещения (элемент data, jsonb) */
+type SubEntityDataModel struct {
+	Title       string `json:"title" db:"title"`
+	Description string `json:"description" db:"description"`
+}
+
+type SubEntityUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
+
+/* Модель для получения среза помещений объекта (uuid - идентификатор объекта) */
+type SubEntityCountModel struct {
+	Uuid   string  `json:"uuid" binding:"required"`
+	Limit  int     `json:"limit" binding:"required"`
+	Count  int     `json:"count"`
+	Status *string `json:"status" binding:"omitempty,oneof=available reserved rented"`
+}
+
+/* Информация о помещении объекта */
+type SubEntityInfoModel struct {
+	Uuid      string             `json:"uuid" db:"uuid"`
+	Data      SubEntityDataModel `json:"data" db:"data"`
+	Rooms     int                `json:"rooms" db:"rooms"`
+	Area      float64            `json:"area" db:"area"`
+	Floor     int                `json:"floor" db:"floor"`
+	Price     float64            `json:"price" db:"price"`
+	Status    string             `json:"status" db:"status"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+}
+
+type SubEntityAnyCountModel struct {
+	SubEntities []SubEntityInfoModel `json:"sub_entities" binding:"required"`
+	Count       int                  `json:"count" binding:"required"`
+}
@@ -0,0 +1,22 @@
+package sub_entity
+
+import "time"
+
+/*
+ * Модели, использующиеся для взаимодействия с таблицей cb_sub_entities
+ */
+
+/* Основная модель */
+type SubEntityDbModel struct {
+	Id         int       `json:"id" db:"id"`
+	Uuid       string    `json:"uuid" db:"uuid"`
+	Data       string    `json:"data" db:"data"`
+	Rooms      int       `json:"rooms" db:"rooms"`
+	Area       float64   `json:"area" db:"area"`
+	Floor      int       `json:"floor" db:"floor"`
+	Price      float64   `json:"price" db:"price"`
+	Status     string    `json:"status" db:"status"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	EntitiesId int       `json:"entities_id" db:"entities_id"`
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/* Модель таблицы u_account_deletions */
+type AccountDeletionDbModel struct {
+	Id          int       `json:"id" db:"id"`
+	UsersId     int       `json:"users_id" db:"users_id"`
+	RequestedAt time.Time `json:"requested_at" db:"requested_at"`
+	ScheduledAt time.Time `json:"scheduled_at" db:"scheduled_at"`
+}
+
+/* Запрос на удаление аккаунта (пароль обязателен, если у аккаунта есть локальный вход) */
+type AccountDeleteModel struct {
+	Password string `json:"password"`
+}
+
+/* Состояние запроса на удаление аккаунта (Scheduled = false - запроса нет) */
+type AccountDeletionModel struct {
+	Scheduled   bool       `json:"scheduled"`
+	RequestedAt *time.Time `json:"requested_at,omitempty"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+}
+
+/* Запрос на выгрузку персональных данных (format: zip - по умолчанию, или json) */
+type AccountExportRequestModel struct {
+	Format string `json:"format"`
+}
+
+/* Роль пользователя (Object - uuid компании для ролей в контексте компании) */
+type AccountRoleModel struct {
+	Domain string `json:"domain" db:"domain"`
+	Role   string `json:"role" db:"role"`
+	Object string `json:"object,omitempty" db:"object"`
+}
+
+/* Участие пользователя в компании (данные из cb_companies и cb_workers) */
+type AccountCompanyDbModel struct {
+	Uuid       string    `db:"uuid"`
+	Data       string    `db:"data"`
+	WorkerUuid string    `db:"worker_uuid"`
+	WorkerData string    `db:"worker_data"`
+	JoinedAt   time.Time `db:"created_at"`
+}
+
+type AccountCompanyModel struct {
+	Uuid       string          `json:"uuid"`
+	Data       json.RawMessage `json:"data"`
+	WorkerUuid string          `json:"worker_uuid"`
+	WorkerData string          `json:"worker_data"`
+	JoinedAt   time.Time       `json:"joined_at"`
+}
+
+/* Файл, загруженный пользователем (Filepath - путь на сервере, Archive - путь в архиве выгрузки) */
+type AccountFileModel struct {
+	Filename string `json:"filename" db:"filename"`
+	Filepath string `json:"-" db:"filepath"`
+	Archive  string `json:"archive,omitempty" db:"-"`
+}
+
+/* Выгрузка персональных данных пользователя */
+type AccountExportModel struct {
+	Uuid       string                `json:"uuid"`
+	Email      string                `json:"email"`
+	Profile    UserJSONBModel        `json:"profile"`
+	Identities []IdentityModel       `json:"identities"`
+	Roles      []AccountRoleModel    `json:"roles"`
+	Companies  []AccountCompanyModel `json:"companies"`
+	Files      []AccountFileModel    `json:"files"`
+	Deletion   AccountDeletionModel  `json:"deletion"`
+	ExportedAt time.Time             `json:"exported_at"`
+}
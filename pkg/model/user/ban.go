@@ -0,0 +1,48 @@
+package user
+
+import "time"
+
+/* Модель данных для блокировки пользователя (expires_at не указан - бессрочная блокировка) */
+type BanCreateModel struct {
+	UserUuid  string     `json:"user_uuid" binding:"required"`
+	Reason    string     `json:"reason" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type BanUserUuidModel struct {
+	UserUuid string `json:"user_uuid" binding:"required"`
+}
+
+/* Модель для получения среза блокировок (active - только действующие блокировки) */
+type BanCountModel struct {
+	Limit  int  `json:"limit" binding:"required"`
+	Count  int  `json:"count"`
+	Active bool `json:"active"`
+}
+
+/* Основная модель таблицы u_bans */
+type BanDbModel struct {
+	Id        int        `json:"id" db:"id"`
+	Uuid      string     `json:"uuid" db:"uuid"`
+	UsersId   int        `json:"users_id" db:"users_id"`
+	AdminsId  *int       `json:"admins_id" db:"admins_id"`
+	Reason    string     `json:"reason" db:"reason"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+type BanModel struct {
+	Uuid      string     `json:"uuid" db:"uuid"`
+	UserUuid  string     `json:"user_uuid" db:"user_uuid"`
+	Email     string     `json:"email" db:"email"`
+	Reason    string     `json:"reason" db:"reason"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+type BanAnyCountModel struct {
+	Bans  []BanModel `json:"bans" binding:"required"`
+	Count int        `json:"count" binding:"required"`
+}
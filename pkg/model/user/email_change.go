@@ -0,0 +1,32 @@
+package user
+
+import "time"
+
+/* Модель таблицы u_email_changes */
+type EmailChangeDbModel struct {
+	Id          int       `json:"id" db:"id"`
+	UsersId     int       `json:"users_id" db:"users_id"`
+	NewEmail    string    `json:"new_email" db:"new_email"`
+	ConfirmHash string    `json:"-" db:"confirm_hash"`
+	CancelHash  string    `json:"-" db:"cancel_hash"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
+}
+
+/* Запрос на смену email-адреса (пароль обязателен, если у аккаунта есть локальный вход) */
+type EmailChangeModel struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password"`
+}
+
+/* Токен подтверждения или отмены смены email-адреса */
+type EmailChangeTokenModel struct {
+	Token string `json:"token" binding:"required"`
+}
+
+/* Состояние запроса на смену email-адреса (Pending = false - запроса нет) */
+type EmailChangeStatusModel struct {
+	Pending   bool       `json:"pending"`
+	NewEmail  string     `json:"new_email,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
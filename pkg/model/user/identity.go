@@ -0,0 +1,44 @@
+package user
+
+import "time"
+
+/* Привязанный к пользователю способ входа */
+type IdentityModel struct {
+	AuthType  string    `json:"auth_type" db:"value"`
+	Email     string    `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type IdentitiesModel struct {
+	Identities []IdentityModel `json:"identities"`
+	Available  []string        `json:"available"`
+}
+
+/* Модель запроса с названием провайдера (google, vk, ...) */
+type IdentityProviderModel struct {
+	Provider string `json:"provider" binding:"required"`
+}
+
+/* Модель запроса на привязку учётной записи провайдера (code и state - из перенаправления провайдера) */
+type IdentityLinkModel struct {
+	Provider string `json:"provider" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	State    string `json:"state" binding:"required"`
+}
+
+/* Модель запроса на установку локального пароля */
+type PasswordSetModel struct {
+	Password string `json:"password" binding:"required"`
+}
+
+/* Основная модель таблицы u_auth_states (параметры авторизационного запроса к провайдеру) */
+type AuthStateDbModel struct {
+	Id           int       `json:"id" db:"id"`
+	StateHash    string    `json:"state_hash" db:"state_hash"`
+	Provider     string    `json:"provider" db:"provider"`
+	UsersId      *int      `json:"users_id" db:"users_id"` // Пользователь, привязывающий учётную запись провайдера (NULL - вход)
+	CodeVerifier string    `json:"code_verifier" db:"code_verifier"`
+	Nonce        string    `json:"nonce" db:"nonce"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -0,0 +1,42 @@
+package user
+
+import "time"
+
+/* Данные об устройстве, с которого выполняется вход (заполняются из HTTP-запроса) */
+type SessionInfoModel struct {
+	DeviceName string `json:"device_name"`
+	Ip         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	DomainsId  int    `json:"-"` // Домен запроса
+}
+
+/* Основная модель таблицы u_sessions */
+type SessionDbModel struct {
+	Id         int        `json:"id" db:"id"`
+	Uuid       string     `json:"uuid" db:"uuid"`
+	UsersId    int        `json:"users_id" db:"users_id"`
+	DeviceName string     `json:"device_name" db:"device_name"`
+	Ip         string     `json:"ip" db:"ip"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+type SessionModel struct {
+	Uuid       string    `json:"uuid"`
+	DeviceName string    `json:"device_name"`
+	Ip         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+type SessionsModel struct {
+	Sessions []SessionModel `json:"sessions"`
+}
+
+type SessionUuidModel struct {
+	Uuid string `json:"uuid" binding:"required"`
+}
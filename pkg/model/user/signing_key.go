@@ -0,0 +1,30 @@
+package user
+
+import "time"
+
+/* Основная модель таблицы u_signing_keys */
+type SigningKeyDbModel struct {
+	Id         int        `json:"id" db:"id"`
+	Kid        string     `json:"kid" db:"kid"`
+	Algorithm  string     `json:"algorithm" db:"algorithm"`
+	PrivateKey string     `json:"private_key" db:"private_key"`
+	PublicKey  string     `json:"public_key" db:"public_key"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at" db:"retired_at"`
+}
+
+/* Открытый ключ в формате JWK (RFC 7517): RSA - n, e; Ed25519 - crv, x */
+type JWKModel struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSModel struct {
+	Keys []JWKModel `json:"keys"`
+}
@@ -1,10 +1,14 @@
 package user
 
+import "time"
+
 type TokenModel struct {
-	Id           int    `json:"id" db:"id"`
-	UsersId      int    `json:"users_id" db:"users_id"`
-	AccessToken  string `json:"access_token" db:"access_token"`
-	RefreshToken string `json:"refresh_token" db:"refresh_token"`
+	Id           int        `json:"id" db:"id"`
+	UsersId      int        `json:"users_id" db:"users_id"`
+	AccessToken  string     `json:"access_token" db:"access_token"`
+	RefreshToken string     `json:"refresh_token" db:"refresh_token"`
+	SessionsId   *int       `json:"sessions_id" db:"sessions_id"`
+	UsedAt       *time.Time `json:"used_at" db:"used_at"`
 }
 
 type TokenDataModel struct {
@@ -20,10 +24,14 @@ type TokenLogoutDataModel struct {
 }
 
 type TokenOutputParse struct {
-	UsersId   int           `json:"users_id"`
-	UsersUuid string        `json:"uuid"`
-	AuthType  AuthTypeModel `json:"auth_types"`
-	TokenApi  *string       `json:"token_api"`
+	UsersId     int           `json:"users_id"`
+	UsersUuid   string        `json:"uuid"`
+	AuthType    AuthTypeModel `json:"auth_types"`
+	TokenApi    *string       `json:"token_api"`
+	SessionUuid *string       `json:"session_uuid"`
+	TokenId     string        `json:"jti"`
+	ExpiresAt   int64         `json:"exp"`
+	IssuedAt    int64         `json:"iat"`
 }
 
 type TokenOutputParseUU struct {
@@ -55,3 +63,22 @@ type ResetTokenModel struct {
 	UsersId int    `json:"users_id" db:"users_id"`
 	Token   string `json:"token" db:"token"`
 }
+
+/* Запрос интроспекции или отзыва токена (RFC 7662, RFC 7009) */
+type TokenIntrospectInputModel struct {
+	Token         string `json:"token" form:"token" binding:"required"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint"`
+}
+
+/* Результат интроспекции токена доступа (для неактивного токена возвращается только active=false) */
+type TokenIntrospectionModel struct {
+	Active      bool     `json:"active"`
+	TokenType   string   `json:"token_type,omitempty"`
+	Sub         string   `json:"sub,omitempty"`
+	AuthType    string   `json:"auth_type,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	SessionUuid *string  `json:"session_uuid,omitempty"`
+	Jti         string   `json:"jti,omitempty"`
+	Exp         int64    `json:"exp,omitempty"`
+	Iat         int64    `json:"iat,omitempty"`
+}
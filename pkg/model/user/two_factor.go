@@ -0,0 +1,84 @@
+package user
+
+import "time"
+
+/* Основная модель таблицы u_two_factor */
+type TwoFactorDbModel struct {
+	Id          int        `json:"id" db:"id"`
+	UsersId     int        `json:"users_id" db:"users_id"`
+	Secret      string     `json:"secret" db:"secret"`
+	LastCounter int64      `json:"last_counter" db:"last_counter"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at" db:"confirmed_at"`
+}
+
+/* Основная модель таблицы u_two_factor_challenges */
+type TwoFactorChallengeDbModel struct {
+	Id        int       `json:"id" db:"id"`
+	UsersId   int       `json:"users_id" db:"users_id"`
+	TokenHash string    `json:"token_hash" db:"token_hash"`
+	Attempts  int       `json:"attempts" db:"attempts"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+
+	// Способ входа первого шага (для входа через внешнего провайдера - его токены)
+	AuthTypesId     *int    `json:"-" db:"auth_types_id"`
+	TokenApi        *string `json:"-" db:"token_api"`
+	TokenApiRefresh *string `json:"-" db:"token_api_refresh"`
+}
+
+/*
+* Ответ первого шага авторизации при включённой (или обязательной) 2FA.
+* enrollment_required - 2FA обязательна для роли пользователя, но ещё не подключена
+ */
+type TwoFactorChallengeModel struct {
+	ChallengeToken     string    `json:"challenge_token"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+}
+
+/* Второй шаг авторизации: code - код TOTP или код восстановления */
+type TwoFactorSignInModel struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeTokenModel struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+/* Данные для подключения приложения-аутентификатора (uri - содержимое QR-кода) */
+type TwoFactorEnrollModel struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+type TwoFactorCodeModel struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorRecoveryCodesModel struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+/* Состояние 2FA пользователя (required - обязательна для одной из ролей пользователя) */
+type TwoFactorStatusModel struct {
+	Enabled            bool `json:"enabled"`
+	Required           bool `json:"required"`
+	RecoveryCodesCount int  `json:"recovery_codes_count"`
+}
+
+/* Роль, для которой 2FA обязательна */
+type TwoFactorRoleModel struct {
+	RoleUuid  string    `json:"role_uuid" db:"role_uuid"`
+	Value     string    `json:"value" db:"value"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type TwoFactorRolesModel struct {
+	Roles []TwoFactorRoleModel `json:"roles"`
+}
+
+type TwoFactorRoleValueModel struct {
+	Value string `json:"value" binding:"required"`
+}
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 type UserIdentityModel struct {
 	UserId   int
 	UserUuid string
@@ -39,6 +41,7 @@ type UserJSONBModel struct {
 
 /* Model for registration via Google OAuth 2 */
 type UserRegisterOAuth2Model struct {
+	Subject    string `json:"id"` // Identifier of the user at the provider (field "id" of the Google userinfo)
 	Email      string `json:"email" binding:"required"`
 	FamilyName string `json:"family_name" binding:"required"`
 	GivenName  string `json:"given_name" binding:"required"`
@@ -51,15 +54,25 @@ type UserLoginModel struct {
 	Password string `json:"password" binding:"required"`
 }
 
-/* A model for working with data during user authorization via Google OAuth 2 */
+/* A model for working with data during user authorization via an external provider redirect */
 type UserLoginOAuth2Model struct {
-	Code string `json:"code" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"` // Параметр state, выданный при формировании адреса авторизации
+}
+
+/* A model of the external provider authorization URL */
+type OAuth2URLModel struct {
+	Url string `json:"url"`
 }
 
-/* A model representing user authorization data */
+/*
+ * A model representing user authorization data.
+ * Challenge is set instead of tokens when the second factor (TOTP) is required
+ */
 type UserAuthDataModel struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string                   `json:"access_token"`
+	RefreshToken string                   `json:"refresh_token"`
+	Challenge    *TwoFactorChallengeModel `json:"challenge,omitempty"`
 }
 
 /* A model representing the user's activation data */
@@ -68,6 +81,18 @@ type UserActivateModel struct {
 	IsActivated    bool   `json:"is_activated" db:"is_activated"`
 }
 
+/* A model of the u_activations table (expires_at = nil - the link does not expire) */
+type ActivationDbModel struct {
+	Id             int        `json:"id" db:"id"`
+	UsersId        int        `json:"users_id" db:"users_id"`
+	IsActivated    bool       `json:"is_activated" db:"is_activated"`
+	ActivationLink string     `json:"activation_link" db:"activation_link"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt      *time.Time `json:"expires_at" db:"expires_at"`
+	LastSentAt     time.Time  `json:"last_sent_at" db:"last_sent_at"`
+	ActivatedAt    *time.Time `json:"activated_at" db:"activated_at"`
+}
+
 /* A model for representing authorization types */
 type AuthTypeModel struct {
 	Id    int    `json:"id" db:"id"`
@@ -77,9 +102,12 @@ type AuthTypeModel struct {
 
 /* A model for linking users with specific types of authorizations */
 type UserAuthTypeModel struct {
-	Id          int `json:"id" db:"id"`
-	UsersId     int `json:"users_id" db:"users_id"`
-	AuthTypesId int `json:"auth_types_id" db:"auth_types_id"`
+	Id          int       `json:"id" db:"id"`
+	UsersId     int       `json:"users_id" db:"users_id"`
+	AuthTypesId int       `json:"auth_types_id" db:"auth_types_id"`
+	Subject     *string   `json:"subject" db:"subject"`
+	Email       string    `json:"email" db:"email"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
 /* A model for email address every users */
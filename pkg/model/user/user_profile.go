@@ -30,3 +30,23 @@ type UserProfileUpdateDataModel struct {
 	Position   string  `json:"position"`
 	Password   *string `json:"password"`
 }
+
+/* Данные профиля, сохраняемые в u_users_data (без пароля) */
+func (m UserProfileUpdateDataModel) ProfileData() UserProfileStoreModel {
+	return UserProfileStoreModel{
+		Name:       m.Name,
+		Surname:    m.Surname,
+		Nickname:   m.Nickname,
+		Patronymic: m.Patronymic,
+		Position:   m.Position,
+	}
+}
+
+/* Model for profile data stored in u_users_data */
+type UserProfileStoreModel struct {
+	Name       string `json:"name"`
+	Surname    string `json:"surname"`
+	Nickname   string `json:"nickname"`
+	Patronymic string `json:"patronymic"`
+	Position   string `json:"position"`
+}
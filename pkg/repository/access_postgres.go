@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"fmt"
+	actionConstant "main-server/pkg/constant/action"
+	rbacModel "main-server/pkg/model/rbac"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+type AccessPostgres struct {
+	enforcer *Enforcer
+	tree     *ObjectTree
+	role     *RolePostgres
+}
+
+/* Функция создания нового экземпляра структуры AccessPostgres */
+func NewAccessPostgres(enforcer *Enforcer, tree *ObjectTree, role *RolePostgres) *AccessPostgres {
+	return &AccessPostgres{
+		enforcer: enforcer,
+		tree:     tree,
+		role:     role,
+	}
+}
+
+/*
+* Цепочка ролей субъекта в домене: правила группировки (g) субъекта и его ролей.
+* Возвращает правила и всех субъектов, от имени которых могут действовать правила доступа (p)
+ */
+func (r *AccessPostgres) getRoleChain(subject, domain string) ([]rbacModel.AccessGroupingModel, []string) {
+	roles := []rbacModel.AccessGroupingModel{}
+	subjects := []string{subject}
+	visited := map[string]bool{subject: true}
+
+	for index := 0; index < len(subjects); index++ {
+		for _, rule := range r.enforcer.GetFilteredGroupingPolicy(0, subjects[index], "", domain) {
+			grouping := rbacModel.AccessGroupingModel{Rule: rule}
+
+			// Роль задаётся идентификатором или моделью "<id роли>;<uuid объекта>"
+			if gpsm, err := rbacModel.NewGPSubjectModel(rule[1]); err == nil {
+				grouping.RoleId = gpsm.RoleId
+				grouping.ObjectUuid = gpsm.ObjectUuid
+			} else if id, err := strconv.Atoi(rule[1]); err == nil {
+				grouping.RoleId = id
+			}
+
+			if role, _ := r.role.Get("id", grouping.RoleId, false); role != nil {
+				grouping.RoleValue = role.Value
+			}
+
+			roles = append(roles, grouping)
+
+			if !visited[rule[1]] {
+				visited[rule[1]] = true
+				subjects = append(subjects, rule[1])
+			}
+		}
+	}
+
+	return roles, subjects
+}
+
+/* Правила доступа (p) субъектов для объекта и его родительских ресурсов */
+func (r *AccessPostgres) getRules(subjects []string, domain string, objects []string, action string) []rbacModel.AccessRuleModel {
+	rules := []rbacModel.AccessRuleModel{}
+
+	for _, subject := range subjects {
+		for _, rule := range r.enforcer.GetFilteredPolicy(0, subject, domain) {
+			if !lo.Contains(objects, rule[2]) {
+				continue
+			}
+
+			rules = append(rules, rbacModel.AccessRuleModel{
+				Rule:    rule,
+				Matched: rule[3] == action,
+			})
+		}
+	}
+
+	return rules
+}
+
+/*
+* Объяснение решения о доступе пользователя к объекту: решение casbin, цепочка ролей,
+* правила для объекта и его родительских ресурсов, а также недостающее правило
+ */
+func (r *AccessPostgres) Explain(usersId, domainsId int, data rbacModel.AccessExplainModel) (rbacModel.AccessExplainResultModel, error) {
+	subject := strconv.Itoa(usersId)
+	domain := strconv.Itoa(domainsId)
+
+	allowed, decisive, err := r.enforcer.EnforceEx(subject, domain, data.ObjectUuid, data.Action)
+	if err != nil {
+		return rbacModel.AccessExplainResultModel{}, err
+	}
+
+	ancestors, err := r.tree.Ancestors(data.ObjectUuid)
+	if err != nil {
+		return rbacModel.AccessExplainResultModel{}, err
+	}
+
+	objects := append([]string{data.ObjectUuid}, ancestors...)
+	roles, subjects := r.getRoleChain(subject, domain)
+	rules := r.getRules(subjects, domain, objects, data.Action)
+
+	result := rbacModel.AccessExplainResultModel{
+		Allowed:  allowed,
+		Subject:  subject,
+		Domain:   domain,
+		Object:   data.ObjectUuid,
+		Action:   data.Action,
+		Objects:  objects,
+		Roles:    roles,
+		Rules:    rules,
+		Decisive: decisive,
+	}
+
+	switch {
+	case allowed:
+		result.Reason = fmt.Sprintf("Доступ разрешён правилом: %s", strings.Join(decisive, ", "))
+	case len(decisive) > 0 && decisive[len(decisive)-1] == actionConstant.DENY:
+		result.Reason = fmt.Sprintf("Доступ запрещён явным правилом: %s", strings.Join(decisive, ", "))
+	default:
+		result.Missing = []string{subject, domain, data.ObjectUuid, data.Action, actionConstant.ALLOW}
+
+		if len(rules) <= 0 {
+			result.Reason = "Нет доступа: у пользователя и его ролей нет правил для объекта и его родительских ресурсов"
+		} else {
+			result.Reason = fmt.Sprintf("Нет доступа: правила для объекта есть, но ни одно из них не разрешает действие %s", data.Action)
+		}
+	}
+
+	if data.Role != nil {
+		role, err := r.role.GetInDomain(*data.Role, domainsId, true)
+		if err != nil {
+			return rbacModel.AccessExplainResultModel{}, err
+		}
+
+		has, err := r.enforcer.HasRoleForUser(subject, strconv.Itoa(role.Id), domain)
+		if err != nil {
+			return rbacModel.AccessExplainResultModel{}, err
+		}
+
+		result.Role = &rbacModel.AccessRoleCheckModel{
+			Value:  role.Value,
+			RoleId: role.Id,
+			Has:    has,
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"main-server/config"
+	roleConstant "main-server/pkg/constant/role"
+	tableConstant "main-server/pkg/constant/table"
+	"main-server/pkg/model/email"
+	userModel "main-server/pkg/model/user"
+	smtpService "main-server/pkg/service/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type AccountPostgres struct {
+	db       *sqlx.DB
+	enforcer *Enforcer
+}
+
+/* Функция создания нового экземпляра структуры AccountPostgres */
+func NewAccountPostgres(db *sqlx.DB, enforcer *Enforcer) *AccountPostgres {
+	return &AccountPostgres{db: db, enforcer: enforcer}
+}
+
+/*
+* Компании, в которых пользователь - единственный администратор-застройщик.
+* Роль в контексте компании задаётся правилом группировки вида "<id роли>;<uuid компании>"
+ */
+func soleCompanyAdmin(db sqlx.Queryer, usersId int) ([]string, error) {
+	var companies []string
+
+	query := fmt.Sprintf(`SELECT DISTINCT split_part(tl.v1, $3, 2) FROM %s tl
+		INNER JOIN %s tr ON tr.id::text = split_part(tl.v1, $3, 1)
+		WHERE tl.ptype = 'g' AND tl.v0 = $1 AND tr.value = $2 AND position($3 in tl.v1) > 0
+			AND NOT EXISTS (
+				SELECT 1 FROM %s tg WHERE tg.ptype = 'g' AND tg.v1 = tl.v1 AND tg.v2 = tl.v2 AND tg.v0 <> tl.v0
+			)`,
+		tableConstant.AC_RULES, tableConstant.AC_ROLES, tableConstant.AC_RULES,
+	)
+
+	err := sqlx.Select(db, &companies, query, strconv.Itoa(usersId), roleConstant.ROLE_BUILDER_ADMIN, ";")
+
+	return companies, err
+}
+
+/* Файлы, загруженные пользователем: изображение профиля, вложения и обложки статей */
+func (r *AccountPostgres) getUserFiles(usersId int, profile userModel.UserJSONBModel) ([]userModel.AccountFileModel, error) {
+	files := []userModel.AccountFileModel{}
+
+	if profile.Avatar != "" {
+		files = append(files, userModel.AccountFileModel{Filename: "avatar", Filepath: profile.Avatar})
+	}
+
+	var uploaded []userModel.AccountFileModel
+
+	query := fmt.Sprintf(`SELECT filename, filepath FROM %s WHERE users_id = $1
+		UNION SELECT filename, filepath FROM %s WHERE users_id = $1 AND filepath <> ''`,
+		tableConstant.A_FILES, tableConstant.A_ARTICLES,
+	)
+
+	if err := r.db.Select(&uploaded, query, usersId); err != nil {
+		return nil, err
+	}
+
+	return append(files, uploaded...), nil
+}
+
+/*
+* Получение профиля пользователя из u_users_data. Возвращаются только поля профиля:
+* прочие ключи JSONB (служебные данные) в выгрузку не попадают
+ */
+func (r *AccountPostgres) getProfile(usersId int) (userModel.UserJSONBModel, error) {
+	var data []string
+
+	query := fmt.Sprintf("SELECT data FROM %s WHERE users_id=$1 LIMIT 1", tableConstant.U_USERS_DATA)
+	if err := r.db.Select(&data, query, usersId); err != nil {
+		return userModel.UserJSONBModel{}, err
+	}
+
+	if len(data) <= 0 {
+		return userModel.UserJSONBModel{}, nil
+	}
+
+	var profile userModel.UserJSONBModel
+	if err := json.Unmarshal([]byte(data[0]), &profile); err != nil {
+		return userModel.UserJSONBModel{}, err
+	}
+
+	return profile, nil
+}
+
+/* Сбор персональных данных пользователя для выгрузки */
+func (r *AccountPostgres) GetExportData(usersId int) (userModel.AccountExportModel, error) {
+	var user userModel.UserModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1 LIMIT 1", tableConstant.U_USERS)
+	if err := r.db.Get(&user, query, usersId); err != nil {
+		return userModel.AccountExportModel{}, errors.New("Пользователь не найден!")
+	}
+
+	profile, err := r.getProfile(usersId)
+	if err != nil {
+		return userModel.AccountExportModel{}, err
+	}
+
+	identities := []userModel.IdentityModel{}
+	query = fmt.Sprintf(`SELECT tat.value, tl.email, tl.created_at FROM %s tl
+		INNER JOIN %s tat ON tat.id = tl.auth_types_id
+		WHERE tl.users_id = $1 ORDER BY tl.created_at, tl.id`,
+		tableConstant.U_USERS_AUTH_TYPES, tableConstant.U_AUTH_TYPES,
+	)
+	if err := r.db.Select(&identities, query, usersId); err != nil {
+		return userModel.AccountExportModel{}, err
+	}
+
+	roles := []userModel.AccountRoleModel{}
+	query = fmt.Sprintf(`SELECT td.value AS domain, tr.value AS role,
+			CASE WHEN position(';' in tl.v1) > 0 THEN split_part(tl.v1, ';', 2) ELSE '' END AS object
+		FROM %s tl
+		INNER JOIN %s tr ON tr.id::text = split_part(tl.v1, ';', 1)
+		INNER JOIN %s td ON td.id::text = tl.v2
+		WHERE tl.ptype = 'g' AND tl.v0 = $1
+		ORDER BY td.value, tr.value`,
+		tableConstant.AC_RULES, tableConstant.AC_ROLES, tableConstant.AC_DOMAINS,
+	)
+	if err := r.db.Select(&roles, query, strconv.Itoa(usersId)); err != nil {
+		return userModel.AccountExportModel{}, err
+	}
+
+	var companiesDb []userModel.AccountCompanyDbModel
+	query = fmt.Sprintf(`SELECT tc.uuid, tc.data, tw.uuid AS worker_uuid, tw.data AS worker_data, tw.created_at
+		FROM %s tw INNER JOIN %s tc ON tc.id = tw.companies_id
+		WHERE tw.users_id = $1 ORDER BY tw.created_at`,
+		tableConstant.CB_WORKERS, tableConstant.CB_COMPANIES,
+	)
+	if err := r.db.Select(&companiesDb, query, usersId); err != nil {
+		return userModel.AccountExportModel{}, err
+	}
+
+	companies := []userModel.AccountCompanyModel{}
+	for _, item := range companiesDb {
+		companies = append(companies, userModel.AccountCompanyModel{
+			Uuid:       item.Uuid,
+			Data:       json.RawMessage(item.Data),
+			WorkerUuid: item.WorkerUuid,
+			WorkerData: item.WorkerData,
+			JoinedAt:   item.JoinedAt,
+		})
+	}
+
+	files, err := r.getUserFiles(usersId, profile)
+	if err != nil {
+		return userModel.AccountExportModel{}, err
+	}
+
+	deletion, err := r.GetDeletion(usersId)
+	if err != nil {
+		return userModel.AccountExportModel{}, err
+	}
+
+	return userModel.AccountExportModel{
+		Uuid:       user.Uuid,
+		Email:      user.Email,
+		Profile:    profile,
+		Identities: identities,
+		Roles:      roles,
+		Companies:  companies,
+		Files:      files,
+		Deletion:   deletion,
+		ExportedAt: time.Now(),
+	}, nil
+}
+
+/* Получение состояния запроса на удаление аккаунта */
+func (r *AccountPostgres) GetDeletion(usersId int) (userModel.AccountDeletionModel, error) {
+	var deletions []userModel.AccountDeletionDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id=$1 LIMIT 1", tableConstant.U_ACCOUNT_DELETION)
+	if err := r.db.Select(&deletions, query, usersId); err != nil {
+		return userModel.AccountDeletionModel{}, err
+	}
+
+	if len(deletions) <= 0 {
+		return userModel.AccountDeletionModel{Scheduled: false}, nil
+	}
+
+	return userModel.AccountDeletionModel{
+		Scheduled:   true,
+		RequestedAt: &deletions[0].RequestedAt,
+		ScheduledAt: &deletions[0].ScheduledAt,
+	}, nil
+}
+
+/*
+* Запрос на удаление аккаунта: аккаунт удаляется по истечении периода ожидания, в течение которого запрос можно отменить.
+* Удаление недоступно, пока пользователь - единственный администратор-застройщик компании
+ */
+func (r *AccountPostgres) RequestDeletion(usersId int, password string) (userModel.AccountDeletionModel, error) {
+	var user userModel.UserModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1 LIMIT 1", tableConstant.U_USERS)
+	if err := r.db.Get(&user, query, usersId); err != nil {
+		return userModel.AccountDeletionModel{}, errors.New("Пользователь не найден!")
+	}
+
+	// Для аккаунта с локальным входом удаление подтверждается паролем
+	if err := checkAccountPassword(r.db, user, password); err != nil {
+		return userModel.AccountDeletionModel{}, err
+	}
+
+	companies, err := soleCompanyAdmin(r.db, usersId)
+	if err != nil {
+		return userModel.AccountDeletionModel{}, err
+	}
+
+	if len(companies) > 0 {
+		return userModel.AccountDeletionModel{}, errors.New(fmt.Sprintf(
+			"Вы являетесь единственным администратором компании (%s)! Передайте права администратора другому пользователю",
+			strings.Join(companies, ", "),
+		))
+	}
+
+	requestedAt := time.Now()
+	scheduledAt := requestedAt.Add(config.AppAccountConfig.DeletionGrace)
+
+	// Повторный запрос не переносит дату удаления
+	query = fmt.Sprintf(
+		`INSERT INTO %s (users_id, requested_at, scheduled_at) values ($1, $2, $3)
+		ON CONFLICT (users_id) DO NOTHING`,
+		tableConstant.U_ACCOUNT_DELETION,
+	)
+
+	result, err := r.db.Exec(query, usersId, requestedAt, scheduledAt)
+	if err != nil {
+		return userModel.AccountDeletionModel{}, err
+	}
+
+	if count, err := result.RowsAffected(); err == nil && count > 0 {
+		sendDeletionNotice(user.Email, scheduledAt)
+	}
+
+	return r.GetDeletion(usersId)
+}
+
+/* Отмена запроса на удаление аккаунта */
+func (r *AccountPostgres) CancelDeletion(usersId int) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.U_ACCOUNT_DELETION)
+
+	result, err := r.db.Exec(query, usersId)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count <= 0 {
+		return false, errors.New("Запрос на удаление аккаунта не найден!")
+	}
+
+	return true, nil
+}
+
+/* Удаление аккаунтов, период ожидания которых истёк (возвращается количество удалённых аккаунтов) */
+func (r *AccountPostgres) PurgeAccounts() (int, error) {
+	var usersId []int
+
+	query := fmt.Sprintf("SELECT users_id FROM %s WHERE scheduled_at <= $1 ORDER BY scheduled_at", tableConstant.U_ACCOUNT_DELETION)
+	if err := r.db.Select(&usersId, query, time.Now()); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range usersId {
+		if err := r.deleteAccount(id); err != nil {
+			logrus.Errorf("error deleting account %d: %s", id, err.Error())
+			continue
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+/*
+* Удаление аккаунта: токены отзываются, связанные записи (профиль, сессии, участие в компаниях и т.д.)
+* удаляются каскадно, правила casbin и загруженные файлы - после фиксации транзакции
+ */
+func (r *AccountPostgres) deleteAccount(usersId int) error {
+	// Пользователь мог стать единственным администратором компании в течение периода ожидания
+	companies, err := soleCompanyAdmin(r.db, usersId)
+	if err != nil {
+		return err
+	}
+
+	if len(companies) > 0 {
+		return errors.New(fmt.Sprintf("user is the only builder admin of companies %s", strings.Join(companies, ", ")))
+	}
+
+	profile, err := r.getProfile(usersId)
+	if err != nil {
+		return err
+	}
+
+	files, err := r.getUserFiles(usersId, profile)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := revokeUserSessions(tx, usersId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.A_FILES)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", tableConstant.U_USERS)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Удаление правил группировки и политик пользователя во всех доменах
+	if _, err := r.enforcer.DeleteUser(strconv.Itoa(usersId)); err != nil {
+		logrus.Errorf("error deleting casbin rules of user %d: %s", usersId, err.Error())
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file.Filepath); err != nil && !os.IsNotExist(err) {
+			logrus.Errorf("error deleting file %s: %s", file.Filepath, err.Error())
+		}
+	}
+
+	return nil
+}
+
+/* Уведомление пользователя о запланированном удалении аккаунта */
+func sendDeletionNotice(userEmail string, scheduledAt time.Time) {
+	err := smtpService.SendMessage(userEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{userEmail},
+		Subject: "Запрос на удаление аккаунта",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+		</style>
+		<body>
+			<h2>Запрос на удаление аккаунта</h2>
+			<br><text>Ваш аккаунт и все связанные с ним данные будут удалены %s.</text>
+			</br><text>До этого момента удаление можно отменить в профиле.</text>
+			<br><br><br>
+			<text>Если Вы не запрашивали удаление аккаунта, войдите в аккаунт, отмените удаление и смените пароль.</text>
+		</body>
+	</html>`, scheduledAt.Format("02.01.2006 15:04")),
+	}))
+
+	if err != nil {
+		logrus.Error(err.Error())
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	//middlewareConstant "main-server/pkg/constant/middleware"
 	"encoding/json"
+	"errors"
 	"fmt"
 	actionConstant "main-server/pkg/constant/action"
 	middlewareConstant "main-server/pkg/constant/middleware"
@@ -15,7 +16,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
@@ -23,7 +23,7 @@ import (
 
 type AdminPostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *Enforcer
 	domain   *DomainPostgres
 	role     *RolePostgres
 	user     *UserPostgres
@@ -32,7 +32,7 @@ type AdminPostgres struct {
 /* Function for create new struct of AdminPostgres */
 func NewAdminPostgres(
 	db *sqlx.DB,
-	enforcer *casbin.Enforcer,
+	enforcer *Enforcer,
 	domain *DomainPostgres,
 	role *RolePostgres,
 	user *UserPostgres,
@@ -127,7 +127,7 @@ func (r *AdminPostgres) CreateCompany(c *gin.Context, data adminModel.CompanyMod
 	}
 
 	// Получение роли администратора-застройщика, которая будет выдана пользователю
-	roleAdmin, err := r.role.Get("value", roleConstant.ROLE_BUILDER_ADMIN, true)
+	roleAdmin, err := r.role.GetInDomain(roleConstant.ROLE_BUILDER_ADMIN, domainsId.(int), true)
 	if err != nil {
 		tx.Rollback()
 		return adminModel.CompanyModel{}, err
@@ -164,9 +164,9 @@ func (r *AdminPostgres) CreateCompany(c *gin.Context, data adminModel.CompanyMod
 
 	// Обновление политик управления доступа для текущего пользователя (администратор, менеджер или "выше")
 	_, err = r.enforcer.AddPolicies([][]string{
-		{userId, domainId, companyUuid.String(), actionConstant.DELETE},
-		{userId, domainId, companyUuid.String(), actionConstant.MODIFY},
-		{userId, domainId, companyUuid.String(), actionConstant.READ},
+		{userId, domainId, companyUuid.String(), actionConstant.DELETE, actionConstant.ALLOW},
+		{userId, domainId, companyUuid.String(), actionConstant.MODIFY, actionConstant.ALLOW},
+		{userId, domainId, companyUuid.String(), actionConstant.READ, actionConstant.ALLOW},
 	})
 
 	if err != nil {
@@ -189,9 +189,9 @@ func (r *AdminPostgres) CreateCompany(c *gin.Context, data adminModel.CompanyMod
 	row = r.db.QueryRow(query, data.EmailAdmin)
 	if err := row.Scan(&userAdminId); err != nil {
 		r.enforcer.RemovePolicies([][]string{
-			{userId, domainId, companyUuid.String(), actionConstant.DELETE},
-			{userId, domainId, companyUuid.String(), actionConstant.MODIFY},
-			{userId, domainId, companyUuid.String(), actionConstant.READ},
+			{userId, domainId, companyUuid.String(), actionConstant.DELETE, actionConstant.ALLOW},
+			{userId, domainId, companyUuid.String(), actionConstant.MODIFY, actionConstant.ALLOW},
+			{userId, domainId, companyUuid.String(), actionConstant.READ, actionConstant.ALLOW},
 		})
 
 		tx.Rollback()
@@ -201,16 +201,16 @@ func (r *AdminPostgres) CreateCompany(c *gin.Context, data adminModel.CompanyMod
 	userId = strconv.Itoa(userAdminId)
 
 	_, err = r.enforcer.AddPolicies([][]string{
-		{userId, domainId, companyUuid.String(), actionConstant.MODIFY},
-		{userId, domainId, companyUuid.String(), actionConstant.READ},
-		{userId, domainId, companyUuid.String(), actionConstant.DELETE},
+		{userId, domainId, companyUuid.String(), actionConstant.MODIFY, actionConstant.ALLOW},
+		{userId, domainId, companyUuid.String(), actionConstant.READ, actionConstant.ALLOW},
+		{userId, domainId, companyUuid.String(), actionConstant.DELETE, actionConstant.ALLOW},
 	})
 
 	// Save results all operation into a tables
 	if err := tx.Commit(); err != nil {
 		r.enforcer.RemovePolicies([][]string{
-			{userId, domainId, companyUuid.String(), actionConstant.MODIFY},
-			{userId, domainId, companyUuid.String(), actionConstant.READ},
+			{userId, domainId, companyUuid.String(), actionConstant.MODIFY, actionConstant.ALLOW},
+			{userId, domainId, companyUuid.String(), actionConstant.READ, actionConstant.ALLOW},
 		})
 
 		tx.Rollback()
@@ -230,6 +230,11 @@ func (r *AdminPostgres) SystemAddManager(user *userModel.UserIdentityModel, data
 	if err != nil {
 		return false, err
 	}
+
+	// Назначается только роль из домена запроса
+	if roleInfo.DomainsId == nil || *roleInfo.DomainsId != user.DomainId {
+		return false, errors.New("Роль не принадлежит текущему домену!")
+	}
 	userInfo, err := r.user.Get("email", data.Email, true)
 	if err != nil {
 		return false, err
@@ -248,7 +253,7 @@ func (r *AdminPostgres) SystemAddManager(user *userModel.UserIdentityModel, data
 	if len(data.PermissionList) > 0 {
 		for _, item := range data.PermissionList {
 			for _, subItem := range item.ActionList {
-				r.enforcer.AddPolicy(strconv.Itoa(userInfo.Id), strconv.Itoa(user.DomainId), item.ObjectUuid, subItem)
+				r.enforcer.AddPolicy(strconv.Itoa(userInfo.Id), strconv.Itoa(user.DomainId), item.ObjectUuid, subItem, item.GetEffect())
 			}
 		}
 	}
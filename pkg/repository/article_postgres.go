@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	statusConstant "main-server/pkg/constant/status"
+	tableConstant "main-server/pkg/constant/table"
+	articleModel "main-server/pkg/model/article"
+	userModel "main-server/pkg/model/user"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ArticlePostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры ArticlePostgres */
+func NewArticlePostgres(db *sqlx.DB) *ArticlePostgres {
+	return &ArticlePostgres{
+		db: db,
+	}
+}
+
+/* Получение списка файлов, прикреплённых к статье */
+func (r *ArticlePostgres) getFiles(articleId int) ([]articleModel.ArticlesFilesDBModel, error) {
+	files := []articleModel.ArticlesFilesDBModel{}
+
+	query := fmt.Sprintf(
+		`SELECT af.files_id, af.index, f.filename, f.filepath FROM %s af
+		INNER JOIN %s f ON f.id = af.files_id
+		WHERE af.articles_id = $1 ORDER BY af.index`,
+		tableConstant.A_ARTICLES_FILES,
+		tableConstant.A_FILES,
+	)
+
+	if err := r.db.Select(&files, query, articleId); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+/* Преобразование записи таблицы статей в модель статьи вместе с файлами */
+func (r *ArticlePostgres) toModel(article *articleModel.ArticleDBModel) (articleModel.ArticleModel, error) {
+	files, err := r.getFiles(article.Id)
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	return articleModel.ArticleModel{
+		Uuid:      article.Uuid,
+		Filepath:  article.Filepath,
+		Title:     article.Title,
+		Text:      article.Text,
+		Tags:      article.Tags,
+		Status:    article.Status,
+		Comment:   article.Comment,
+		Files:     files,
+		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+	}, nil
+}
+
+/* Получение среза статей по условию */
+func (r *ArticlePostgres) getArticles(condition string, data articleModel.ArticleCountModel, args ...interface{}) (articleModel.ArticlesModel, error) {
+	var articles []articleModel.ArticleDBModel
+
+	args = append(args, data.Limit, data.Count)
+	query := fmt.Sprintf(
+		"SELECT * FROM %s tl WHERE %s ORDER BY tl.created_at DESC, tl.id DESC LIMIT $%d OFFSET $%d",
+		tableConstant.A_ARTICLES, condition, len(args)-1, len(args),
+	)
+
+	if err := r.db.Select(&articles, query, args...); err != nil {
+		return articleModel.ArticlesModel{}, err
+	}
+
+	result := articleModel.ArticlesModel{
+		Articles: []articleModel.ArticleModel{},
+	}
+
+	for i := range articles {
+		article, err := r.toModel(&articles[i])
+		if err != nil {
+			return articleModel.ArticlesModel{}, err
+		}
+
+		result.Articles = append(result.Articles, article)
+	}
+
+	result.Count = len(result.Articles)
+	return result, nil
+}
+
+/* Добавление файлов к статье в рамках транзакции */
+func addArticleFiles(tx *sqlx.Tx, userId, articleId, startIndex int, files *[]articleModel.ArticlesFilesDBModel) error {
+	if files == nil {
+		return nil
+	}
+
+	queryFile := fmt.Sprintf("INSERT INTO %s (filename, filepath, users_id) values ($1, $2, $3) RETURNING id", tableConstant.A_FILES)
+	queryLink := fmt.Sprintf("INSERT INTO %s (articles_id, files_id, index) values ($1, $2, $3)", tableConstant.A_ARTICLES_FILES)
+
+	for _, file := range *files {
+		var fileId int
+
+		row := tx.QueryRow(queryFile, file.Filename, file.Filepath, userId)
+		if err := row.Scan(&fileId); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(queryLink, articleId, fileId, startIndex+file.Index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Удаление файлов с диска (ошибки только логируются, так как записи в БД уже удалены) */
+func removeArticleFiles(filepaths []string) {
+	for _, filepath := range filepaths {
+		if filepath == "" {
+			continue
+		}
+
+		if err := os.Remove(filepath); err != nil && !os.IsNotExist(err) {
+			logrus.Error(err.Error())
+		}
+	}
+}
+
+/* Получение статьи с проверкой её принадлежности пользователю */
+func (r *ArticlePostgres) getOwned(user userModel.UserIdentityModel, articleUuid string) (*articleModel.ArticleDBModel, error) {
+	article, err := r.Get("uuid", articleUuid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.UsersId != user.UserId {
+		return nil, errors.New("Ошибка! Нет доступа!")
+	}
+
+	return article, nil
+}
+
+/* Создание новой статьи (статья отправляется на модерацию) */
+func (r *ArticlePostgres) CreateArticle(user userModel.UserIdentityModel, data articleModel.ArticleCreateRequestModel) (articleModel.ArticleModel, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	filename, filepath := "", ""
+	if data.Filename != nil && data.Filepath != nil {
+		filename, filepath = *data.Filename, *data.Filepath
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (uuid, users_id, filepath, filename, title, text, tags, status, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+		tableConstant.A_ARTICLES,
+	)
+
+	articleUuid := uuid.NewV4()
+	currentDate := time.Now()
+
+	var articleId int
+	row := tx.QueryRow(
+		query, articleUuid, user.UserId, filepath, filename, data.Title, data.Text,
+		data.Tags, statusConstant.ARTICLE_UNCHECKED, currentDate, currentDate,
+	)
+	if err := row.Scan(&articleId); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	if err := addArticleFiles(tx, user.UserId, articleId, 0, data.Files); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	return r.toModel(&articleModel.ArticleDBModel{
+		Id:        articleId,
+		Uuid:      articleUuid.String(),
+		UsersId:   user.UserId,
+		Filepath:  filepath,
+		Filename:  filename,
+		Title:     data.Title,
+		Text:      data.Text,
+		Tags:      data.Tags,
+		Status:    statusConstant.ARTICLE_UNCHECKED,
+		CreatedAt: currentDate,
+		UpdatedAt: currentDate,
+	})
+}
+
+/* Обновление статьи (после изменения статья повторно отправляется на модерацию) */
+func (r *ArticlePostgres) UpdateArticle(user userModel.UserIdentityModel, data articleModel.ArticleUpdateRequestModel) (articleModel.ArticleModel, error) {
+	article, err := r.getOwned(user, data.Uuid)
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	// Файлы, которые необходимо удалить с диска после фиксации транзакции
+	var removed []string
+
+	if data.Filename != nil && data.Filepath != nil {
+		removed = append(removed, article.Filepath)
+		article.Filename, article.Filepath = *data.Filename, *data.Filepath
+	}
+
+	if data.FilesDelete != nil && len(*data.FilesDelete) > 0 {
+		var filepaths []string
+
+		query := fmt.Sprintf(
+			`DELETE FROM %s f USING %s af
+			WHERE af.files_id = f.id AND af.articles_id = $1 AND f.id = ANY($2)
+			RETURNING f.filepath`,
+			tableConstant.A_FILES,
+			tableConstant.A_ARTICLES_FILES,
+		)
+
+		if err := tx.Select(&filepaths, query, article.Id, pq.Array(*data.FilesDelete)); err != nil {
+			tx.Rollback()
+			return articleModel.ArticleModel{}, err
+		}
+
+		removed = append(removed, filepaths...)
+	}
+
+	// Новые файлы добавляются после уже прикреплённых
+	var startIndex int
+	query := fmt.Sprintf("SELECT COALESCE(MAX(tl.index) + 1, 0) FROM %s tl WHERE tl.articles_id = $1", tableConstant.A_ARTICLES_FILES)
+	if err := tx.Get(&startIndex, query, article.Id); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	if err := addArticleFiles(tx, user.UserId, article.Id, startIndex, data.Files); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	article.Title = data.Title
+	article.Text = data.Text
+	article.Tags = data.Tags
+	article.Status = statusConstant.ARTICLE_UNCHECKED
+	article.Comment = nil
+	article.UpdatedAt = time.Now()
+
+	query = fmt.Sprintf(
+		`UPDATE %s tl SET filepath=$1, filename=$2, title=$3, text=$4, tags=$5, status=$6, comment=NULL, updated_at=$7
+		WHERE tl.id=$8`,
+		tableConstant.A_ARTICLES,
+	)
+
+	_, err = tx.Exec(
+		query, article.Filepath, article.Filename, article.Title, article.Text,
+		article.Tags, article.Status, article.UpdatedAt, article.Id,
+	)
+	if err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleModel{}, err
+	}
+
+	removeArticleFiles(removed)
+
+	return r.toModel(article)
+}
+
+/* Удаление статьи вместе с прикреплёнными файлами */
+func (r *ArticlePostgres) DeleteArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleSuccessModel, error) {
+	article, err := r.getOwned(user, data.Uuid)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	removed := []string{article.Filepath}
+
+	var filepaths []string
+	query := fmt.Sprintf(
+		`DELETE FROM %s f USING %s af
+		WHERE af.files_id = f.id AND af.articles_id = $1
+		RETURNING f.filepath`,
+		tableConstant.A_FILES,
+		tableConstant.A_ARTICLES_FILES,
+	)
+
+	if err := tx.Select(&filepaths, query, article.Id); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", tableConstant.A_ARTICLES)
+	if _, err := tx.Exec(query, article.Id); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	removeArticleFiles(append(removed, filepaths...))
+
+	return articleModel.ArticleSuccessModel{Success: true}, nil
+}
+
+/* Получение статьи пользователя */
+func (r *ArticlePostgres) GetUserArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error) {
+	article, err := r.getOwned(user, data.Uuid)
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	return r.toModel(article)
+}
+
+/* Получение среза статей пользователя (с любым статусом модерации) */
+func (r *ArticlePostgres) GetUserArticles(user userModel.UserIdentityModel, data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error) {
+	return r.getArticles("tl.users_id = $1", data, user.UserId)
+}
+
+/* Получение среза статей, ожидающих модерации */
+func (r *ArticlePostgres) GetUncheckedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error) {
+	return r.getArticles("tl.status = $1", data, statusConstant.ARTICLE_UNCHECKED)
+}
+
+/* Изменение статуса модерации статьи */
+func (r *ArticlePostgres) ModerateArticle(data articleModel.ArticleModerationModel, status string) (articleModel.ArticleSuccessModel, error) {
+	article, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	if article.Status != statusConstant.ARTICLE_UNCHECKED {
+		return articleModel.ArticleSuccessModel{Success: false}, errors.New("Ошибка: статья уже прошла модерацию!")
+	}
+
+	query := fmt.Sprintf("UPDATE %s tl SET status=$1, comment=$2 WHERE tl.id=$3", tableConstant.A_ARTICLES)
+	if _, err := r.db.Exec(query, status, data.Comment, article.Id); err != nil {
+		return articleModel.ArticleSuccessModel{Success: false}, err
+	}
+
+	return articleModel.ArticleSuccessModel{Success: true}, nil
+}
+
+/* Получение одобренной статьи */
+func (r *ArticlePostgres) GetApprovedArticle(data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error) {
+	article, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return articleModel.ArticleModel{}, err
+	}
+
+	if article.Status != statusConstant.ARTICLE_APPROVED {
+		return articleModel.ArticleModel{}, errors.New(fmt.Sprintf("Ошибка: статьи по запросу uuid:%s не найдено!", data.Uuid))
+	}
+
+	return r.toModel(article)
+}
+
+/* Получение среза одобренных статей */
+func (r *ArticlePostgres) GetApprovedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error) {
+	return r.getArticles("tl.status = $1", data, statusConstant.ARTICLE_APPROVED)
+}
+
+/* Получение экземпляра статьи из таблицы */
+func (r *ArticlePostgres) Get(column string, value interface{}, check bool) (*articleModel.ArticleDBModel, error) {
+	var articles []articleModel.ArticleDBModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s=$1", tableConstant.A_ARTICLES, column)
+
+	var err error
+
+	switch value.(type) {
+	case int:
+		err = r.db.Select(&articles, query, value.(int))
+		break
+	case string:
+		err = r.db.Select(&articles, query, value.(string))
+		break
+	}
+
+	if len(articles) <= 0 {
+		if check {
+			return nil, errors.New(fmt.Sprintf("Ошибка: статьи по запросу %s:%v не найдено!", column, value))
+		}
+
+		return nil, nil
+	}
+
+	return &articles[len(articles)-1], err
+}
@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"crypto/sha1"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,30 +17,92 @@ import (
 	"main-server/pkg/model/user"
 	userModel "main-server/pkg/model/user"
 	authService "main-server/pkg/service/auth"
+	"main-server/pkg/service/keyring"
+	passwordService "main-server/pkg/service/password"
 	smtpService "main-server/pkg/service/smtp"
 
 	roleConstant "main-server/pkg/constant/role"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
-	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
 )
 
 type AuthPostgres struct {
 	db           *sqlx.DB
-	enforcer     *casbin.Enforcer
+	enforcer     *Enforcer
 	userPostgres UserPostgres
 }
 
+/* Отправка письма со ссылкой на активацию аккаунта */
+func sendActivationEmail(userEmail, link string) error {
+	return smtpService.SendMessage(userEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{userEmail},
+		Subject: "Подтверждение аккаунта \"Rental housing\"",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+			button {
+				color: rgb(0, 0, 0);
+				outline: none;
+				border: none;
+				border-radius: 30px;
+				background-color: #B19472;
+				padding: 8px 16px;
+				margin-top: 16px;
+				cursor: pointer;
+			}
+		</style>
+		<body>
+			<h2>Подтверждение E-mail</h2>
+			<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в приложении "Rental housing".</text> 
+			</br><text>Чтобы подтвердить Вашу почту перейдите по ссылке: </text></br>
+			<a href="%s">
+			<button>Подтвердить E-mail</button>
+			</a>
+			<br><br><br>
+			<text>Если Вы не проходили процедуру регистрации в приложении "Rental housing", то не отвечайте на данное сообщение.</text>
+		</body>
+	</html>`, viper.GetString("api_url")+"/auth/activate/"+link),
+	}))
+}
+
+/* Получение домена, определённого для запроса (регистрация и вход выполняются в домене запроса) */
+func getRequestDomain(db *sqlx.DB, info userModel.SessionInfoModel) (rbacModel.DomainModel, error) {
+	var domain rbacModel.DomainModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id = $1 LIMIT 1", tableConstants.AC_DOMAINS)
+	if err := db.Get(&domain, query, info.DomainsId); err != nil {
+		return rbacModel.DomainModel{}, errors.New("Домена не существует!")
+	}
+
+	return domain, nil
+}
+
+/* Проверка наличия у пользователя хотя бы одной роли в домене */
+func hasDomainAccess(enforcer *Enforcer, usersId, domainsId int) bool {
+	roles, err := enforcer.GetRolesForUser(strconv.Itoa(usersId), strconv.Itoa(domainsId))
+	if err != nil {
+		return false
+	}
+
+	return len(roles) > 0
+}
+
 /*
 * Функция создания экземпляра сервиса
  */
-func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres UserPostgres) *AuthPostgres {
+func NewAuthPostgres(db *sqlx.DB, enforcer *Enforcer, userPostgres UserPostgres) *AuthPostgres {
 	return &AuthPostgres{
 		db:           db,
 		enforcer:     enforcer,
@@ -50,28 +111,27 @@ func NewAuthPostgres(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres UserPo
 }
 
 /* Method for create new user */
-func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	check := CheckRowExists(r.db, tableConstants.U_USERS, "email", user.Email)
 
 	if check {
 		return userModel.UserAuthDataModel{}, errors.New("Пользователь с данным email-адресом уже существует!")
 	}
 
-	// Начало транзакции
-	tx, err := r.db.Begin()
+	// Хэширование пароля
+	hashedPassword, err := passwordService.Hash(user.Password)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Хэширование пароля
-	// user.Password = generatePasswordHash(user.Password)
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), viper.GetInt("crypt.cost"))
+	user.Password = hashedPassword
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	user.Password = string(hashedPassword)
-
 	var id int
 	var userUuid string
 
@@ -107,12 +167,10 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.AC_DOMAINS)
-	var domain rbacModel.DomainModel
-	err = r.db.Get(&domain, query, viper.GetString("domain"))
+	domain, err := getRequestDomain(r.db, info)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Домена не существует!")
+		return userModel.UserAuthDataModel{}, err
 	}
 
 	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1", tableConstants.AC_ROLES)
@@ -143,86 +201,35 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 		return userModel.UserAuthDataModel{}, errors.New(err.Error())
 	}
 
-	query = fmt.Sprintf("INSERT INTO %s (users_id, auth_types_id) values ($1, $2)", tableConstants.U_USERS_AUTH_TYPES)
-	_, err = tx.Exec(query, id, authTypes.Id)
+	query = fmt.Sprintf("INSERT INTO %s (users_id, auth_types_id, email) values ($1, $2, $3)", tableConstants.U_USERS_AUTH_TYPES)
+	_, err = tx.Exec(query, id, authTypes.Id, user.Email)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	/* Generation token access and refresh */
-	accessToken, err := GenerateToken(userUuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	/* Creating a user session with access and refresh tokens */
+	tokens, err := startSession(tx, id, userUuid, authTypes.Uuid, info, nil, nil)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
-	}
-
-	/* Setting tokens for user */
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, id, accessToken, refreshToken)
-	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
-	}
-
-	/* Adding an account activation link */
+	/* Adding an account activation link (with a limited lifetime) */
 	u2 := uuid.NewV4()
+	currentDate = time.Now()
 
-	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.U_ACTIVATIONS)
-	_, err = tx.Exec(query, id, false, u2)
+	query = fmt.Sprintf(
+		"INSERT INTO %s (users_id, is_activated, activation_link, created_at, expires_at, last_sent_at) values ($1, $2, $3, $4, $5, $4)",
+		tableConstants.U_ACTIVATIONS,
+	)
+	_, err = tx.Exec(query, id, false, u2, currentDate, currentDate.Add(config.AppActivationConfig.TTL))
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	/*auth := smtp.PlainAuth("", viper.GetString("smtp.email"), os.Getenv("SMTP_PASSWORD"), viper.GetString("smtp.host"))
-
-	err = smtp.SendMail(viper.GetString("smtp.host")+":"+viper.GetString("smtp.port"), auth,
-		viper.GetString("smtp.email"), []string{user.Email}, []byte(`<p>Hello, world!</p>`))*/
-
-	err = smtpService.SendMessage(user.Email, smtpService.BuildMessage(email.Mail{
-		Sender:  viper.GetString("smtp.email"),
-		To:      []string{user.Email},
-		Subject: "Подтверждение аккаунта \"Rental housing\"",
-		Body: fmt.Sprintf(`<html>
-		<head>
-			<meta charset="utf-8" />
-			<title></title>
-		</head>
-		<style>
-			body {background-color: #FEFEF9;}
-			h2   {color: #181511;}
-			button {
-				color: rgb(0, 0, 0);
-				outline: none;
-				border: none;
-				border-radius: 30px;
-				background-color: #B19472;
-				padding: 8px 16px;
-				margin-top: 16px;
-				cursor: pointer;
-			}
-		</style>
-		<body>
-			<h2>Подтверждение E-mail</h2>
-			<br><text>Вы получили это письмо, так как Ваш почтовый адрес был указан в приложении "Rental housing".</text> 
-			</br><text>Чтобы подтвердить Вашу почту перейдите по ссылке: </text></br>
-			<a href="%s">
-			<button>Подтвердить E-mail</button>
-			</a>
-			<br><br><br>
-			<text>Если Вы не проходили процедуру регистрации в приложении "Rental housing", то не отвечайте на данное сообщение.</text>
-		</body>
-	</html>`, viper.GetString("api_url")+"/auth/activate/"+u2.String()),
-	}))
-
-	if err != nil {
+	if err := sendActivationEmail(user.Email, u2.String()); err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
@@ -233,10 +240,7 @@ func (r *AuthPostgres) CreateUser(user userModel.UserRegisterModel) (userModel.U
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return tokens, nil
 }
 
 func (r *AuthPostgres) UploadProfileImage(c *gin.Context, filepath string) (bool, error) {
@@ -295,51 +299,58 @@ func (r *AuthPostgres) UploadProfileImage(c *gin.Context, filepath string) (bool
 }
 
 /* Login user */
-func (r *AuthPostgres) LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) LoginUser(user userModel.UserLoginModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	var findUser userModel.UserModel
 	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.U_USERS)
 	if err := r.db.Get(&findUser, query, user.Email); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Пользователя с данным почтовым адресом не существует!")
+		// Сравнение с фиктивным хэшем выравнивает время ответа для несуществующих учётных записей
+		passwordService.VerifyDummy(user.Password)
+		return userModel.UserAuthDataModel{}, ErrInvalidCredentials
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(findUser.Password), []byte(user.Password)); err != nil {
-		return userModel.UserAuthDataModel{}, errors.New("Не правильный пароль! Повторите попытку")
+	ok, rehash, err := passwordService.Verify(findUser.Password, user.Password)
+	if !ok {
+		if err != nil && findUser.Password != "" {
+			logrus.Errorf("error verifying password of user %d: %s", findUser.Id, err.Error())
+		}
+
+		return userModel.UserAuthDataModel{}, ErrInvalidCredentials
 	}
 
-	tx, err := r.db.Begin()
-	if err != nil {
-		return userModel.UserAuthDataModel{}, err
+	// Хэш, сформированный другим алгоритмом или с устаревшими параметрами, пересчитывается
+	if rehash {
+		r.rehashPassword(findUser, user.Password)
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.U_TOKENS)
-	if _, err := r.db.Exec(query, findUser.Id); err != nil {
-		tx.Rollback()
+	if err := checkUserBan(r.db, findUser.Id); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.AC_DOMAINS)
-	var domain rbacModel.DomainModel
-	err = r.db.Get(&domain, query, viper.GetString("domain"))
+	// В режиме block вход до активации аккаунта запрещён
+	if config.AppActivationConfig.Mode == config.ACTIVATION_MODE_BLOCK {
+		activated, err := isUserActivated(r.db, findUser.Id)
+		if err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		if !activated {
+			return userModel.UserAuthDataModel{}, errors.New("Аккаунт не активирован! Перейдите по ссылке из письма или запросите его повторно")
+		}
+	}
+
+	tx, err := r.db.Begin()
 	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Домена не существует!")
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1", tableConstants.AC_ROLES)
-	var role rbacModel.RoleModel
-	err = r.db.Get(&role, query, roleConstant.ROLE_CLIENT, domain.Id)
+	domain, err := getRequestDomain(r.db, info)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Роли пользователя для данного домена не существует!")
+		return userModel.UserAuthDataModel{}, err
 	}
 
-	datas, _ := r.enforcer.GetRolesForUser(strconv.Itoa(findUser.Id), strconv.Itoa(domain.Id))
-
-	fmt.Println(datas)
-
-	flag, _ := r.enforcer.HasRoleForUser(strconv.Itoa(findUser.Id), strconv.Itoa(role.Id), strconv.Itoa(domain.Id))
-
-	if !flag {
+	// Наборы ролей доменов независимы: вход разрешён пользователю, у которого есть роль в домене запроса
+	if !hasDomainAccess(r.enforcer, findUser.Id, domain.Id) {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, errors.New("Данный пользователь не имеет доступа к данному домену!")
 	}
@@ -353,37 +364,39 @@ func (r *AuthPostgres) LoginUser(user userModel.UserLoginModel) (userModel.UserA
 		return userModel.UserAuthDataModel{}, errors.New(err.Error())
 	}
 
-	// Генерация токенов доступа и обновления
-	accessToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// При включённой (или обязательной для роли) 2FA вместо токенов выдаётся промежуточный токен второго шага
+	challenge, err := twoFactorChallengeForLogin(r.db, r.enforcer, findUser.Id, domain.Id, twoFactorLogin{AuthTypesId: authTypes.Id})
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-	if err != nil {
+	if challenge != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, err
+		return userModel.UserAuthDataModel{Challenge: challenge}, nil
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, findUser.Id, accessToken, refreshToken)
+	// Создание новой сессии (сессии на других устройствах сохраняются)
+	tokens, err := startSession(tx, findUser.Id, findUser.Uuid, authTypes.Uuid, info, nil, nil)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, tx.Commit()
+	return tokens, tx.Commit()
 }
 
 /*
 * Создание пользователя через OAuth2
  */
-func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token *oauth2.Token) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token *oauth2.Token, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return r.createUserOAuth2(user, token, authConstants.AUTH_TYPE_GOOGLE, info)
+}
+
+/*
+* Создание пользователя через OAuth2 с указанным типом аутентификации (google, vk)
+ */
+func (r *AuthPostgres) createUserOAuth2(user user.UserRegisterOAuth2Model, token *oauth2.Token, authType string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	check := CheckRowExists(r.db, tableConstants.U_USERS, "email", user.Email)
 
 	if check {
@@ -396,23 +409,16 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Хэширование пароля
-	// user.Password = generatePasswordHash(user.Password)
-	/*hashedPassword, err := bcrypt.GenerateFromPassword([]byte(token.AccessToken), viper.GetInt("crypt.cost"))
-	if err != nil {
-		return userModel.UserAuthDataModel{}, err
-	}*/
-
 	var id int
 	var userUuid string
 
-	// Запрос на добавление нового пользователя в систему
-	query := fmt.Sprintf("INSERT INTO %s (email, password, uuid) values ($1, $2, $3) RETURNING id, uuid", tableConstants.U_USERS)
+	// Запрос на добавление нового пользователя в систему (без пароля - он может быть установлен в профиле)
+	query := fmt.Sprintf("INSERT INTO %s (email, password, uuid) values ($1, '', $2) RETURNING id, uuid", tableConstants.U_USERS)
 
 	// Генерация UUID
 	u1 := uuid.NewV4()
 
-	row := tx.QueryRow(query, user.Email, token.AccessToken, u1)
+	row := tx.QueryRow(query, user.Email, u1)
 	if err := row.Scan(&id, &userUuid); err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, errors.New("Пользователь с данными регистрационными данными уже существует!")
@@ -420,8 +426,8 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 
 	// Запрос на добавление пользовательских данных
 	query = fmt.Sprintf(
-		`INSERT INTO %s (data, date_registration, users_id) 
-		values ($1, $2, $3)`,
+		`INSERT INTO %s (data, created_at, updated_at, users_id) 
+		values ($1, $2, $3, $4)`,
 		tableConstants.U_USERS_DATA)
 
 	userJsonb, err := json.Marshal(userModel.UserJSONBModel{
@@ -430,19 +436,18 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 		Nickname: user.GivenName,
 	})
 
-	_, err = tx.Exec(query, userJsonb, time.Now(), id)
+	currentDate := time.Now()
+	_, err = tx.Exec(query, userJsonb, currentDate, currentDate, id)
 
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 LIMIT 1", tableConstants.AC_DOMAINS)
-	var domain rbacModel.DomainModel
-	err = r.db.Get(&domain, query, viper.GetString("domain"))
+	domain, err := getRequestDomain(r.db, info)
 	if err != nil {
 		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New("Домена не существует!")
+		return userModel.UserAuthDataModel{}, err
 	}
 
 	query = fmt.Sprintf("SELECT * FROM %s WHERE value = $1 AND domains_id = $2 LIMIT 1", tableConstants.AC_ROLES)
@@ -456,169 +461,178 @@ func (r *AuthPostgres) CreateUserOAuth2(user user.UserRegisterOAuth2Model, token
 	/* Added default user roles */
 	r.enforcer.AddRoleForUserInDomain(strconv.Itoa(id), strconv.Itoa(role.Id), strconv.Itoa(domain.Id))
 
-	// Установка типа аутентификации пользователя (в данном случае - GOOGLE)
+	// Установка типа аутентификации пользователя
 	var authTypes userModel.AuthTypeModel
 	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.U_AUTH_TYPES)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_GOOGLE)
+	err = r.db.Get(&authTypes, query, authType)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, errors.New(err.Error())
 	}
 
-	query = fmt.Sprintf("INSERT INTO %s (users_id, auth_types_id) values ($1, $2)", tableConstants.U_USERS_AUTH_TYPES)
-	_, err = tx.Exec(query, id, authTypes.Id)
+	query = fmt.Sprintf(
+		"INSERT INTO %s (users_id, auth_types_id, subject, email, created_at) values ($1, $2, NULLIF($3, ''), $4, $5)",
+		tableConstants.U_USERS_AUTH_TYPES,
+	)
+	_, err = tx.Exec(query, id, authTypes.Id, user.Subject, user.Email, currentDate)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токенов доступа
-	accessToken, err := GenerateToken(userUuid, authTypes.Uuid, &token.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// Добавление ссылки на активацию аккаунта
+	// Генерация UUID
+	u2 := uuid.NewV4()
+	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.U_ACTIVATIONS)
+	_, err = tx.Exec(query, id, true, u2)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токена обновления
-	refreshToken, err := GenerateToken(userUuid, authTypes.Uuid, &token.RefreshToken, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_access"))
-	if err != nil {
-		tx.Rollback()
+	if err := tx.Commit(); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, id, accessToken, refreshToken)
+	// Роль нового пользователя может требовать 2FA - сессия создаётся только после проверки
+	return r.completeLoginOAuth2(id, userUuid, domain.Id, authTypes, token, info)
+}
+
+/* Формирование адреса страницы авторизации провайдера (с новым параметром state) */
+func (r *AuthPostgres) GetOAuth2URL(providerName string) (string, error) {
+	return createAuthState(r.db, providerName, 0)
+}
+
+/*
+* Функция авторизации пользователя через внешний провайдер аутентификации (Google, VK, OpenID Connect)
+* по перенаправлению со страницы авторизации. State обязателен и должен быть выдан GetOAuth2URL
+ */
+func (r *AuthPostgres) LoginUserOAuth2(providerName, code, state string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	// Параметры авторизационного запроса (PKCE, nonce), выданные при формировании адреса авторизации
+	params, err := consumeAuthState(r.db, providerName, state, 0)
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Добавление ссылки на активацию аккаунта
-	// Генерация UUID
-	u2 := uuid.NewV4()
-	query = fmt.Sprintf("INSERT INTO %s (users_id, is_activated, activation_link) values ($1, $2, $3)", tableConstants.U_ACTIVATIONS)
-	_, err = tx.Exec(query, id, true, u2)
+	token, userData, err := exchangeOAuth2(providerName, code, params)
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, tx.Commit()
+	return r.loginUserOAuth2(userData, token, strings.ToLower(providerName), info)
 }
 
 /*
-* Функция авторизации пользователя через Google OAuth2
+* Авторизация по коду, полученному клиентом через SDK провайдера (Google Sign-In): авторизационный
+* запрос формирует сам клиент, поэтому state, PKCE и nonce не используются
  */
-func (r *AuthPostgres) LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error) {
-	token, err := config.AppOAuth2Config.GoogleLogin.Exchange(oauth2.NoContext, code)
-
+func (r *AuthPostgres) LoginUserOAuth2SDK(providerName, code string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	token, userData, err := exchangeOAuth2(providerName, code, nil)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	is_verify, err := authService.VerifyAccessToken(token.AccessToken)
+	return r.loginUserOAuth2(userData, token, strings.ToLower(providerName), info)
+}
 
+/*
+* Авторизация (или регистрация) пользователя по данным, полученным от OAuth2 провайдера.
+* Пользователь определяется по привязанной учётной записи провайдера, а не по email-адресу
+ */
+func (r *AuthPostgres) loginUserOAuth2(userData userModel.UserRegisterOAuth2Model, token *oauth2.Token, authType string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	authTypes, err := getAuthType(r.db, authType)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	if !is_verify {
-		return userModel.UserAuthDataModel{}, errors.New("Данный токен не принадлежит данному пользователю!")
-	}
-
-	var findUser userModel.UserModel
-	var userData userModel.UserRegisterOAuth2Model
-
-	userData, err = authService.GetUserInfo(token)
-
+	usersId, err := findIdentityUser(r.db, authTypes.Id, userData)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.email = $1 LIMIT 1", tableConstants.U_USERS)
-	if err := r.db.Get(&findUser, query, userData.Email); err != nil {
-		// Если пользователя не существует - создаём его
-		return r.CreateUserOAuth2(userData, token)
-	}
+	if usersId == 0 {
+		// Учётная запись с тем же email-адресом привязывается только её владельцем из профиля
+		if CheckRowExists(r.db, tableConstants.U_USERS, "email", userData.Email) {
+			return userModel.UserAuthDataModel{}, ErrIdentityNotLinked
+		}
 
-	tx, err := r.db.Begin()
-	if err != nil {
-		return userModel.UserAuthDataModel{}, err
+		return r.createUserOAuth2(userData, token, authType, info)
 	}
 
-	/*hashedPassword, err := bcrypt.GenerateFromPassword([]byte(token.AccessToken), viper.GetInt("crypt.cost"))
-	if err != nil {
-		return userModel.UserAuthDataModel{}, err
-	}*/
-
-	// Запрос на обновление пароля в базе данных для пользователя
-	query = fmt.Sprintf("UPDATE %s SET password=$1 WHERE email=$2", tableConstants.U_USERS)
+	var findUser userModel.UserModel
 
-	if _, err := r.db.Exec(query, token.AccessToken, userData.Email); err != nil {
-		tx.Rollback()
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.id = $1 LIMIT 1", tableConstants.U_USERS)
+	if err := r.db.Get(&findUser, query, usersId); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstants.U_TOKENS)
-	if _, err := r.db.Exec(query, findUser.Id); err != nil {
-		tx.Rollback()
+	if err := checkUserBan(r.db, findUser.Id); err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Получение типа аутентификации (в данном случае - GOOGLE)
-	var authTypes userModel.AuthTypeModel
-	query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstants.U_AUTH_TYPES)
-	err = r.db.Get(&authTypes, query, authConstants.AUTH_TYPE_GOOGLE)
+	domain, err := getRequestDomain(r.db, info)
 	if err != nil {
-		tx.Rollback()
-		return userModel.UserAuthDataModel{}, errors.New(err.Error())
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if !hasDomainAccess(r.enforcer, findUser.Id, domain.Id) {
+		return userModel.UserAuthDataModel{}, errors.New("Данный пользователь не имеет доступа к данному домену!")
 	}
 
-	// Генерация токена доступа
-	accessToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, &token.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	return r.completeLoginOAuth2(findUser.Id, findUser.Uuid, domain.Id, authTypes, token, info)
+}
+
+/*
+* Завершение входа через внешний провайдер: при включённой (или обязательной для роли) 2FA
+* вместо токенов выдаётся промежуточный токен второго шага, токены провайдера сохраняются до его прохождения
+ */
+func (r *AuthPostgres) completeLoginOAuth2(usersId int, usersUuid string, domainsId int, authTypes userModel.AuthTypeModel, token *oauth2.Token, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	challenge, err := twoFactorChallengeForLogin(r.db, r.enforcer, usersId, domainsId, twoFactorLogin{
+		AuthTypesId:     authTypes.Id,
+		TokenApi:        &token.AccessToken,
+		TokenApiRefresh: &token.RefreshToken,
+	})
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Генерация токена обновления
-	refreshToken, err := GenerateToken(findUser.Uuid, authTypes.Uuid, &token.RefreshToken, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_access"))
+	if challenge != nil {
+		return userModel.UserAuthDataModel{Challenge: challenge}, nil
+	}
+
+	tx, err := r.db.Begin()
 	if err != nil {
-		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Установка токенов пользователю
-	query = fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token) values ($1, $2, $3)", tableConstants.U_TOKENS)
-	_, err = tx.Exec(query, findUser.Id, accessToken, refreshToken)
+	// Создание новой сессии (сессии на других устройствах сохраняются)
+	tokens, err := startSession(tx, usersId, usersUuid, authTypes.Uuid, info, &token.AccessToken, &token.RefreshToken)
 	if err != nil {
 		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, tx.Commit()
+	return tokens, tx.Commit()
 }
 
 /**
- * Функция для обновления токена доступа по токену обновления
+ * Функция для обновления токена доступа по токену обновления (с ротацией токена обновления)
  * @param {userModel.TokenLogoutDataModel} data - Подробная информация об авторизационной информации пользователя
  * @param {string} rToken - Токен обновления (refresh token)
- * @param {token userModel.TokenOutputParse} token - Данные, полученные после дешифровки токена доступа вне зависимости от его валидности
- * @returns {userModel.UserAuthDataModel, error} Пара токенов (access и refresh) или ошибка
+ * @param {token userModel.TokenOutputParse} token - Данные, полученные после дешифровки токена обновления вне зависимости от его валидности
+ * @param {userModel.SessionInfoModel} info - Данные об устройстве пользователя
+ * @returns {userModel.UserAuthDataModel, error} Новая пара токенов (access и refresh) или ошибка
  */
-func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken string, token userModel.TokenOutputParse) (userModel.UserAuthDataModel, error) {
+func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken string, token userModel.TokenOutputParse, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
 	user, err := r.userPostgres.Get("id", token.UsersId, true)
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
+	if err := checkUserBan(r.db, user.Id); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
 	var findToken userModel.TokenModel
 	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.refresh_token = $1 AND tl.users_id = $2 LIMIT 1", tableConstants.U_TOKENS)
 
@@ -626,80 +640,120 @@ func (r *AuthPostgres) Refresh(data userModel.TokenLogoutDataModel, rToken strin
 		return userModel.UserAuthDataModel{}, errors.New("Пользователя с данным токеном обновления не существует!")
 	}
 
-	isValid := ValidToken(rToken, viper.GetString("token.signing_key_refresh"))
+	// Повторное использование уже обменянного токена обновления - признак его кражи:
+	// завершается вся сессия (все токены, выданные по цепочке ротации)
+	if findToken.UsedAt != nil {
+		if findToken.SessionsId != nil {
+			if err := revokeSession(r.db, *findToken.SessionsId); err != nil {
+				return userModel.UserAuthDataModel{}, err
+			}
+		}
+
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: повторное использование токена обновления! Сессия завершена, авторизуйтесь повторно")
+	}
 
-	setValues := make([]string, 0)
-	args := make([]interface{}, 0)
-	argId := 1
+	if !ValidToken(rToken, viper.GetString("token.signing_key_refresh")) {
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: срок действия токена обновления истёк, авторизуйтесь повторно!")
+	}
 
-	var refreshToken string
+	// Внешний провайдер аутентификации (для локальной аутентификации отсутствует)
+	var accessTokenApi, refreshTokenApi *string
 
-	if !isValid {
-		switch token.AuthType.Value {
-		case "LOCAL":
-			refreshToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, nil, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-			break
+	if token.AuthType.Value != authConstants.AUTH_TYPE_LOCAL {
+		provider, err := authService.GetProvider(token.AuthType.Value)
+		if err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
 
-		case "GOOGLE":
-			// Если токен от Google OAuth2 не валиден, то нужно чтобы пользователь перезашёл в приложение заново
-			//google_oauth2.RevokeToken(*token.TokenApi)
-			//r.Logout(data)
-			refreshToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, token.TokenApi, authConstants.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
-			break
+		// Токен обновления провайдера хранится в токене обновления приложения
+		current := &oauth2.Token{}
+		if data.TokenApi != nil {
+			current.AccessToken = *data.TokenApi
+		}
+		if refreshTokenApi = token.TokenApi; refreshTokenApi != nil {
+			current.RefreshToken = *refreshTokenApi
 		}
 
+		providerToken, err := provider.RefreshToken(oauth2.NoContext, current)
 		if err != nil {
 			return userModel.UserAuthDataModel{}, err
 		}
 
-		setValues = append(setValues, fmt.Sprintf("refresh_token=$%d", argId))
-		args = append(args, refreshToken)
-		argId++
-	} else {
-		refreshToken = rToken
+		accessTokenApi = &providerToken.AccessToken
+		if providerToken.RefreshToken != "" {
+			refreshTokenApi = &providerToken.RefreshToken
+		}
 	}
 
-	var accessToken string
-
-	switch token.AuthType.Value {
-	case authConstants.AUTH_TYPE_LOCAL:
-		accessToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, nil, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
-		break
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	case authConstants.AUTH_TYPE_GOOGLE:
-		tokenData, err := authService.RefreshAccessToken(oauth2.NoContext, *token.TokenApi)
-		accessToken, err = GenerateToken(user.Uuid, token.AuthType.Uuid, &tokenData.AccessToken, authConstants.TOKEN_TLL_ACCESS, viper.GetString("token.signing_key_access"))
+	// Токены, выданные до появления сессий, переносятся в новую сессию
+	if findToken.SessionsId == nil {
+		query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", tableConstants.U_TOKENS)
+		if _, err := tx.Exec(query, findToken.Id); err != nil {
+			tx.Rollback()
+			return userModel.UserAuthDataModel{}, err
+		}
 
+		tokens, err := startSession(tx, user.Id, user.Uuid, token.AuthType.Uuid, info, accessTokenApi, refreshTokenApi)
 		if err != nil {
+			tx.Rollback()
 			return userModel.UserAuthDataModel{}, err
 		}
+
+		return tokens, tx.Commit()
 	}
 
+	var session userModel.SessionDbModel
+	query = fmt.Sprintf("SELECT * FROM %s WHERE id = $1 LIMIT 1", tableConstants.U_SESSIONS)
+	if err := r.db.Get(&session, query, *findToken.SessionsId); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if session.RevokedAt != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: сессия завершена, авторизуйтесь повторно!")
+	}
+
+	currentDate := time.Now()
+
+	// Отметка токена обновления как использованного (условие защищает от параллельного обмена)
+	query = fmt.Sprintf("UPDATE %s SET used_at=$1 WHERE id=$2 AND used_at IS NULL", tableConstants.U_TOKENS)
+	result, err := tx.Exec(query, currentDate, findToken.Id)
 	if err != nil {
+		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	setValues = append(setValues, fmt.Sprintf("access_token=$%d", argId))
-	args = append(args, accessToken)
-	argId++
+	if affected, err := result.RowsAffected(); err != nil || affected != 1 {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: токен обновления уже был использован!")
+	}
 
-	setQuery := strings.Join(setValues, ", ")
+	// Удаление использованных токенов сессии, срок действия которых уже истёк
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.sessions_id = $1 AND tl.used_at < $2", tableConstants.U_TOKENS)
+	if _, err := tx.Exec(query, session.Id, currentDate.Add(-authConstants.TOKEN_TLL_REFRESH)); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	query = fmt.Sprintf("UPDATE %s tl SET %s WHERE tl.users_id = $%d",
-		tableConstants.U_TOKENS, setQuery, argId)
-	args = append(args, user.Id)
+	query = fmt.Sprintf("UPDATE %s SET last_used_at=$1, ip=$2, user_agent=$3 WHERE id=$4", tableConstants.U_SESSIONS)
+	if _, err := tx.Exec(query, currentDate, info.Ip, info.UserAgent, session.Id); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
 
-	// Обновление данных о токене пользователя
-	_, err = r.db.Exec(query, args...)
+	tokens, err := issueSessionTokens(tx, user.Id, session.Id, user.Uuid, token.AuthType.Uuid, session.Uuid, accessTokenApi, refreshTokenApi)
 	if err != nil {
+		tx.Rollback()
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	// Возвращение авторизационных данных пользователя
-	return userModel.UserAuthDataModel{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return tokens, tx.Commit()
 }
 
 /*
@@ -710,48 +764,151 @@ func (r *AuthPostgres) Activate(link string) (bool, error) {
 	query := fmt.Sprintf("SELECT activation_link, is_activated FROM %s WHERE activation_link = $1", tableConstants.U_ACTIVATIONS)
 
 	if err := r.db.Get(&findActivate, query, link); err != nil {
-		return false, errors.New(err.Error())
+		return false, errors.New("Ссылка активации недействительна!")
 	}
 
 	if findActivate.IsActivated {
 		return true, nil
 	}
 
-	query = fmt.Sprintf("UPDATE %s SET is_activated=%s WHERE activation_link = $1", tableConstants.U_ACTIVATIONS, "true")
+	// Просроченная ссылка не активирует аккаунт (expires_at = NULL - ссылки, созданные до ограничения срока)
+	currentDate := time.Now()
+	query = fmt.Sprintf(
+		"UPDATE %s SET is_activated=true, activated_at=$2 WHERE activation_link = $1 AND (expires_at IS NULL OR expires_at > $2)",
+		tableConstants.U_ACTIVATIONS,
+	)
 
-	_, err := r.db.Exec(query, link)
+	result, err := r.db.Exec(query, link, currentDate)
+	if err != nil {
+		return false, err
+	}
 
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return false, err
 	}
 
+	if affected <= 0 {
+		return false, errors.New("Срок действия ссылки активации истёк! Запросите письмо для активации повторно")
+	}
+
 	return true, nil
 }
 
+/* Проверка активации аккаунта (пользователи без записи об активации считаются активированными) */
+func isUserActivated(db *sqlx.DB, usersId int) (bool, error) {
+	var activations []userModel.ActivationDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id=$1", tableConstants.U_ACTIVATIONS)
+	if err := db.Select(&activations, query, usersId); err != nil {
+		return false, err
+	}
+
+	if len(activations) <= 0 {
+		return true, nil
+	}
+
+	for _, item := range activations {
+		if item.IsActivated {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+/* Проверка активации аккаунта пользователя */
+func (r *AuthPostgres) CheckActivation(usersId int) (bool, error) {
+	return isUserActivated(r.db, usersId)
+}
+
 /*
-* Функция разлогирования пользователя
+* Повторная отправка письма для активации аккаунта с новой ссылкой (старая ссылка становится недействительной).
+* Письмо не отправляется, если учётной записи нет, она уже активирована или не истёк интервал между отправками,
+* но результат во всех случаях одинаков (true), чтобы по ответу нельзя было определить существование учётной записи
+ */
+func (r *AuthPostgres) ResendActivation(userEmail string) (bool, error) {
+	// Ответ не должен зависеть от существования учётной записи и состояния её активации
+	user, err := r.GetUser("email", userEmail)
+	if err != nil {
+		return true, nil
+	}
+
+	activated, err := isUserActivated(r.db, user.Id)
+	if err != nil {
+		return false, err
+	}
+
+	if activated {
+		return true, nil
+	}
+
+	currentDate := time.Now()
+
+	// Оставшееся время вычисляется в БД, так как даты хранятся без часового пояса
+	var seconds []float64
+	query := fmt.Sprintf(
+		"SELECT EXTRACT(EPOCH FROM (MAX(last_sent_at) - $2)) FROM %s WHERE users_id=$1 HAVING MAX(last_sent_at) > $2",
+		tableConstants.U_ACTIVATIONS,
+	)
+	if err := r.db.Select(&seconds, query, user.Id, currentDate.Add(-config.AppActivationConfig.ResendCooldown)); err != nil {
+		return false, err
+	}
+
+	// В течение интервала повторное письмо не отправляется, но ответ остаётся тем же
+	if len(seconds) > 0 && seconds[0] > 0 {
+		return true, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	link := uuid.NewV4().String()
+	query = fmt.Sprintf(
+		"UPDATE %s SET activation_link=$1, expires_at=$2, last_sent_at=$3 WHERE users_id=$4",
+		tableConstants.U_ACTIVATIONS,
+	)
+	if _, err := tx.Exec(query, link, currentDate.Add(config.AppActivationConfig.TTL), currentDate, user.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := sendActivationEmail(user.Email, link); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+/*
+* Функция разлогирования пользователя (завершение текущей сессии)
  */
 func (r *AuthPostgres) Logout(data userModel.TokenLogoutDataModel) (bool, error) {
 	// Выход из аккаунта зависит от метода аутентификации (предварительная проверка обязательна)
-	switch data.AuthTypeValue {
-	case authConstants.AUTH_TYPE_GOOGLE:
-		authService.RevokeToken(*data.TokenApi)
-		break
+	if provider, err := authService.GetProvider(data.AuthTypeValue); err == nil && data.TokenApi != nil {
+		provider.RevokeToken(*data.TokenApi)
 	}
 
-	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.access_token=$1 AND tl.refresh_token=$2 RETURNING id", tableConstants.U_TOKENS)
-	row := r.db.QueryRow(query, data.AccessToken, data.RefreshToken)
+	var findToken userModel.TokenModel
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.refresh_token = $1 LIMIT 1", tableConstants.U_TOKENS)
 
-	var id int
-	if err := row.Scan(&id); err != nil {
+	if err := r.db.Get(&findToken, query, data.RefreshToken); err != nil {
 		return false, err
 	}
 
-	return true, nil
-}
+	// Токен доступа из заголовка отзывается отдельно: он мог быть выдан до ротации токена обновления
+	if err := revokeAccessTokens(r.db, data.AccessToken); err != nil {
+		return false, err
+	}
+
+	if err := revokeTokenPair(r.db, findToken); err != nil {
+		return false, err
+	}
 
-type AuthTypeValue struct {
-	Value string `json:"value" db:"value"`
+	return true, nil
 }
 
 /*
@@ -759,27 +916,20 @@ type AuthTypeValue struct {
  */
 func (r *AuthPostgres) RecoveryPassword(userEmail string) (bool, error) {
 	// Check exists user in system
+	// Ответ не должен зависеть от существования учётной записи - письмо просто не отправляется
 	user, err := r.GetUser("email", userEmail)
 	if err != nil {
-		return false, errors.New("Пользователя с данным email-адресом не существует!")
+		return true, nil
 	}
 
-	query := fmt.Sprintf(`SELECT value FROM %s tl
-	INNER JOIN %s td on td.auth_types_id = tl.id WHERE td.users_id=$1 LIMIT 1`,
-		tableConstants.U_AUTH_TYPES, tableConstants.U_USERS_AUTH_TYPES)
-
-	var authType AuthTypeValue
-	err = r.db.Get(&authType, query, user.Id)
+	hasPassword, err := hasLocalIdentity(r.db, user.Id)
 	if err != nil {
 		return false, err
 	}
 
-	if authType.Value != authConstants.AUTH_TYPE_LOCAL {
-		return false, errors.New(`
-		Восстановление пароля для данного пользователя не поддерживается, так как 
-		пользователь авторизовался через сторонний сервис (Google, VK).
-		Пожалуйста, воспользуйтесь сторонним сервисом для авторизации пользователя
-		`)
+	// Восстановление пароля для аккаунтов без локального входа (только Google, VK) не поддерживается
+	if !hasPassword {
+		return true, nil
 	}
 
 	// Delete other reset tokens for current user
@@ -788,7 +938,7 @@ func (r *AuthPostgres) RecoveryPassword(userEmail string) (bool, error) {
 		return false, err
 	}
 
-	query = fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.U_RESET_TOKENS)
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE users_id=$1", tableConstants.U_RESET_TOKENS)
 
 	_, err = tx.Exec(query, user.Id)
 	if err != nil {
@@ -862,11 +1012,11 @@ func (r *AuthPostgres) ResetPassword(data userModel.ResetPasswordModel, token us
 	// Checking whether the token belongs to the current user
 	resetToken, err := r.GetResetToken("token", data.Token)
 	if err != nil {
-		return false, err
+		return false, ErrInvalidResetToken
 	}
 
 	if resetToken.UsersId != token.UsersId {
-		return false, errors.New("Данный токен сброса пароля не принадлежит данному пользователю")
+		return false, ErrInvalidResetToken
 	}
 
 	// Password reset procedure
@@ -875,13 +1025,14 @@ func (r *AuthPostgres) ResetPassword(data userModel.ResetPasswordModel, token us
 		return false, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), viper.GetInt("crypt.cost"))
+	hashedPassword, err := passwordService.Hash(data.Password)
 	if err != nil {
+		tx.Rollback()
 		return false, err
 	}
 
 	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", tableConstants.U_USERS)
-	_, err = tx.Exec(query, string(hashedPassword), token.UsersId)
+	_, err = tx.Exec(query, hashedPassword, token.UsersId)
 	if err != nil {
 		tx.Rollback()
 		return false, err
@@ -942,14 +1093,19 @@ func (r *AuthPostgres) GetResetToken(column, value string) (userModel.ResetToken
 	return token, err
 }
 
-/*
-* Password hashing function
- */
-func generatePasswordHash(password string) string {
-	hash := sha1.New()
-	hash.Write([]byte(password))
+/* Пересчёт хэша пароля текущим алгоритмом после успешного входа (ошибка не прерывает вход) */
+func (r *AuthPostgres) rehashPassword(user userModel.UserModel, password string) {
+	hashedPassword, err := passwordService.Hash(password)
+	if err != nil {
+		logrus.Errorf("error rehashing password of user %d: %s", user.Id, err.Error())
+		return
+	}
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(viper.GetString("crypt.salt"))))
+	// Хэш заменяется, только если пароль не был изменён параллельно
+	query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2 AND password=$3", tableConstants.U_USERS)
+	if _, err := r.db.Exec(query, hashedPassword, user.Id, user.Password); err != nil {
+		logrus.Errorf("error rehashing password of user %d: %s", user.Id, err.Error())
+	}
 }
 
 /* Working with user authentication tokens */
@@ -959,25 +1115,40 @@ type tokenClaims struct {
 	UsersId     string  `json:"users_id"`      // ID пользователя
 	AuthTypesId string  `json:"auth_types_id"` // Тип аутентификации пользователя
 	TokenApi    *string `json:"token_api"`     // Внешний токен доступа
+	SessionUuid *string `json:"session_uuid"`  // Сессия (устройство) пользователя
 }
 
-/*
-* Token generation function
- */
-func GenerateToken(uuid, authTypesUuid string, tokenApi *string, tokenTTL time.Duration, signingKey string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+/* Формирование полезных данных токена */
+func newTokenClaims(userUuid, authTypesUuid string, sessionUuid, tokenApi *string, tokenTTL time.Duration) *tokenClaims {
+	return &tokenClaims{
 		jwt.StandardClaims{
+			Id:        uuid.NewV4().String(),
 			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
-		uuid,
+		userUuid,
 		authTypesUuid,
 		tokenApi,
-	})
+		sessionUuid,
+	}
+}
+
+/*
+* Token generation function (HMAC, используется для токенов обновления)
+ */
+func GenerateToken(userUuid, authTypesUuid string, sessionUuid, tokenApi *string, tokenTTL time.Duration, signingKey string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newTokenClaims(userUuid, authTypesUuid, sessionUuid, tokenApi, tokenTTL))
 
 	return token.SignedString([]byte(signingKey))
 }
 
+/*
+* Генерация токена доступа, подписанного действующим асимметричным ключом (проверяется по JWKS)
+ */
+func GenerateAccessToken(userUuid, authTypesUuid string, sessionUuid, tokenApi *string, tokenTTL time.Duration) (string, error) {
+	return keyring.Sign(newTokenClaims(userUuid, authTypesUuid, sessionUuid, tokenApi, tokenTTL))
+}
+
 /*
 * Token validity verification function
  */
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var errAuthState = errors.New("Ошибка: неизвестный или устаревший параметр state!")
+
+/*
+* Создание авторизационного запроса к провайдеру (usersId - пользователь, привязывающий учётную запись
+* провайдера, 0 - вход). Параметры запроса сохраняются в БД, поэтому обратный вызов провайдера может
+* обработать любой экземпляр сервера
+ */
+func createAuthState(db *sqlx.DB, providerName string, usersId int) (string, error) {
+	request, err := authService.NewAuthRequest()
+	if err != nil {
+		return "", err
+	}
+
+	url, err := authService.AuthCodeURL(providerName, request)
+	if err != nil {
+		return "", err
+	}
+
+	var users *int
+	if usersId > 0 {
+		users = &usersId
+	}
+
+	currentDate := time.Now()
+
+	// Удаление просроченных запросов
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at <= $1", tableConstant.U_AUTH_STATES)
+	if _, err := db.Exec(query, currentDate); err != nil {
+		return "", err
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (state_hash, provider, users_id, code_verifier, nonce, created_at, expires_at)
+		values ($1, $2, $3, $4, $5, $6, $7)`,
+		tableConstant.U_AUTH_STATES,
+	)
+
+	if _, err := db.Exec(
+		query, hashOneTimeValue(request.State), strings.ToLower(providerName), users,
+		request.Params.CodeVerifier, request.Params.Nonce, currentDate, currentDate.Add(authService.AuthStateTTL),
+	); err != nil {
+		return "", err
+	}
+
+	return url, nil
+}
+
+/*
+* Получение (однократное) параметров авторизационного запроса по значению state.
+* Запрос должен быть выдан для того же провайдера и того же пользователя (защита от подстановки чужого кода)
+ */
+func consumeAuthState(db *sqlx.DB, providerName, state string, usersId int) (*authService.AuthParams, error) {
+	if state == "" {
+		return nil, errors.New("Ошибка: не передан параметр state авторизационного запроса!")
+	}
+
+	var items []userModel.AuthStateDbModel
+
+	// Удаление и получение выполняются одним запросом - state не может быть использован дважды
+	query := fmt.Sprintf("DELETE FROM %s WHERE state_hash=$1 RETURNING *", tableConstant.U_AUTH_STATES)
+	if err := db.Select(&items, query, hashOneTimeValue(state)); err != nil {
+		return nil, err
+	}
+
+	if len(items) <= 0 {
+		return nil, errAuthState
+	}
+
+	data := items[0]
+
+	owner := 0
+	if data.UsersId != nil {
+		owner = *data.UsersId
+	}
+
+	if data.Provider != strings.ToLower(providerName) || owner != usersId || time.Now().After(data.ExpiresAt) {
+		return nil, errAuthState
+	}
+
+	return &authService.AuthParams{
+		CodeVerifier: data.CodeVerifier,
+		Nonce:        data.Nonce,
+	}, nil
+}
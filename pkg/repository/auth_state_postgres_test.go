@@ -0,0 +1,78 @@
+package repository
+
+import (
+	authService "main-server/pkg/service/auth"
+	"net/url"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/oauth2"
+)
+
+/* Выдача авторизационного запроса и получение значения state из адреса авторизации */
+func newTestAuthState(t *testing.T, db *sqlx.DB, usersId int) string {
+	t.Helper()
+
+	address, err := createAuthState(db, "stub", usersId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.Query().Get("state")
+}
+
+func TestAuthStateIsSingleUseAndBound(t *testing.T) {
+	authService.RegisterProvider("stub", authService.NewVKProvider(oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{AuthURL: "https://provider.test/authorize"},
+	}, ""))
+
+	db := newTestDB(t)
+	db.MustExec(`INSERT INTO u_users (id, email, password) VALUES (7, 'owner@example.com', '')`)
+
+	if _, err := consumeAuthState(db, "stub", "", 0); err == nil {
+		t.Fatal("an empty state must be rejected")
+	}
+
+	state := newTestAuthState(t, db, 0)
+
+	params, err := consumeAuthState(db, "stub", state, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params.CodeVerifier == "" || params.Nonce == "" {
+		t.Fatalf("expected stored PKCE parameters, got %+v", params)
+	}
+
+	if _, err := consumeAuthState(db, "stub", state, 0); err == nil {
+		t.Fatal("a state must not be accepted twice")
+	}
+
+	// Запрос на привязку выдан пользователю 7 и не может быть использован другим пользователем или для входа
+	link := newTestAuthState(t, db, 7)
+	if _, err := consumeAuthState(db, "stub", link, 8); err == nil {
+		t.Fatal("a link state must be bound to its user")
+	}
+
+	link = newTestAuthState(t, db, 7)
+	if _, err := consumeAuthState(db, "stub", link, 0); err == nil {
+		t.Fatal("a link state must not be accepted for sign-in")
+	}
+
+	other := newTestAuthState(t, db, 0)
+	if _, err := consumeAuthState(db, "other", other, 0); err == nil {
+		t.Fatal("a state must be bound to its provider")
+	}
+
+	expired := newTestAuthState(t, db, 0)
+	db.MustExec(`UPDATE u_auth_states SET expires_at = created_at`)
+	if _, err := consumeAuthState(db, "stub", expired, 0); err == nil {
+		t.Fatal("an expired state must be rejected")
+	}
+}
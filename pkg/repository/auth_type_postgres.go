@@ -20,6 +20,16 @@ func NewAuthTypePostgres(db *sqlx.DB) *AuthTypePostgres {
 	return &AuthTypePostgres{db: db}
 }
 
+/*
+* Функция добавления типа аутентификации (если он ещё не существует)
+ */
+func (r *AuthTypePostgres) AddAuthType(value string) error {
+	query := fmt.Sprintf("INSERT INTO %s (value) VALUES ($1) ON CONFLICT (value) DO NOTHING", tableConstant.U_AUTH_TYPES)
+	_, err := r.db.Exec(query, value)
+
+	return err
+}
+
 /*
 * Функция получения данных о роли
  */
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/* Условие действующей блокировки пользователя */
+const activeBanCondition = "tl.revoked_at IS NULL AND (tl.expires_at IS NULL OR tl.expires_at > NOW())"
+
+type BanPostgres struct {
+	db   *sqlx.DB
+	user *UserPostgres
+}
+
+/* Функция создания нового экземпляра структуры BanPostgres */
+func NewBanPostgres(db *sqlx.DB, user *UserPostgres) *BanPostgres {
+	return &BanPostgres{
+		db:   db,
+		user: user,
+	}
+}
+
+/* Проверка наличия действующей блокировки пользователя (возвращает ошибку, если пользователь заблокирован) */
+func checkUserBan(db *sqlx.DB, usersId int) error {
+	var bans []userModel.BanDbModel
+
+	query := fmt.Sprintf(
+		"SELECT * FROM %s tl WHERE tl.users_id = $1 AND %s ORDER BY tl.expires_at DESC NULLS FIRST LIMIT 1",
+		tableConstant.U_BANS, activeBanCondition,
+	)
+
+	if err := db.Select(&bans, query, usersId); err != nil {
+		return err
+	}
+
+	if len(bans) <= 0 {
+		return nil
+	}
+
+	if bans[0].ExpiresAt == nil {
+		return errors.New(fmt.Sprintf("Пользователь заблокирован бессрочно. Причина: %s", bans[0].Reason))
+	}
+
+	return errors.New(fmt.Sprintf(
+		"Пользователь заблокирован до %s. Причина: %s",
+		bans[0].ExpiresAt.Format("02.01.2006 15:04"), bans[0].Reason,
+	))
+}
+
+/* Блокировка пользователя с отзывом всех его токенов */
+func (r *BanPostgres) BanUser(admin userModel.UserIdentityModel, data userModel.BanCreateModel) (userModel.BanModel, error) {
+	user, err := r.user.Get("uuid", data.UserUuid, true)
+	if err != nil {
+		return userModel.BanModel{}, err
+	}
+
+	if user.Id == admin.UserId {
+		return userModel.BanModel{}, errors.New("Ошибка: нельзя заблокировать самого себя!")
+	}
+
+	currentDate := time.Now()
+	if data.ExpiresAt != nil {
+		if !data.ExpiresAt.After(currentDate) {
+			return userModel.BanModel{}, errors.New("Ошибка: дата окончания блокировки должна быть в будущем!")
+		}
+
+		// Даты в БД хранятся в локальном времени сервера
+		expiresAt := data.ExpiresAt.Local()
+		data.ExpiresAt = &expiresAt
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.BanModel{}, err
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (users_id, admins_id, reason, created_at, expires_at) values ($1, $2, $3, $4, $5) RETURNING uuid",
+		tableConstant.U_BANS,
+	)
+
+	var banUuid string
+	row := tx.QueryRow(query, user.Id, admin.UserId, data.Reason, currentDate, data.ExpiresAt)
+	if err := row.Scan(&banUuid); err != nil {
+		tx.Rollback()
+		return userModel.BanModel{}, err
+	}
+
+	// Завершение всех сессий пользователя
+	if err := revokeUserSessions(tx, user.Id); err != nil {
+		tx.Rollback()
+		return userModel.BanModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return userModel.BanModel{}, err
+	}
+
+	return userModel.BanModel{
+		Uuid:      banUuid,
+		UserUuid:  user.Uuid,
+		Email:     user.Email,
+		Reason:    data.Reason,
+		CreatedAt: currentDate,
+		ExpiresAt: data.ExpiresAt,
+	}, nil
+}
+
+/* Снятие всех действующих блокировок пользователя */
+func (r *BanPostgres) UnbanUser(data userModel.BanUserUuidModel) (bool, error) {
+	user, err := r.user.Get("uuid", data.UserUuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s tl SET revoked_at = $1 WHERE tl.users_id = $2 AND %s",
+		tableConstant.U_BANS, activeBanCondition,
+	)
+
+	result, err := r.db.Exec(query, time.Now(), user.Id)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count <= 0 {
+		return false, errors.New("Ошибка: у пользователя нет действующих блокировок!")
+	}
+
+	return true, nil
+}
+
+/* Получение среза блокировок пользователей */
+func (r *BanPostgres) GetBans(data userModel.BanCountModel) (userModel.BanAnyCountModel, error) {
+	condition := "TRUE"
+	if data.Active {
+		condition = activeBanCondition
+	}
+
+	query := fmt.Sprintf(
+		`SELECT tl.uuid, u.uuid AS user_uuid, u.email, tl.reason, tl.created_at, tl.expires_at, tl.revoked_at
+		FROM %s tl INNER JOIN %s u ON u.id = tl.users_id
+		WHERE %s ORDER BY tl.created_at DESC, tl.id DESC LIMIT $1 OFFSET $2`,
+		tableConstant.U_BANS, tableConstant.U_USERS, condition,
+	)
+
+	bans := []userModel.BanModel{}
+	if err := r.db.Select(&bans, query, data.Limit, data.Count); err != nil {
+		return userModel.BanAnyCountModel{}, err
+	}
+
+	return userModel.BanAnyCountModel{
+		Bans:  bans,
+		Count: len(bans),
+	}, nil
+}
+
+/* Проверка блокировки пользователя */
+func (r *BanPostgres) CheckBan(usersId int) error {
+	return checkUserBan(r.db, usersId)
+}
@@ -12,13 +12,12 @@ import (
 	"sort"
 	"strconv"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/jmoiron/sqlx"
 )
 
 type CompanyPostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *Enforcer
 	role     *RolePostgres
 	user     *UserPostgres
 	wrapper  *WrapperPostgres
@@ -27,7 +26,7 @@ type CompanyPostgres struct {
 /* Function for create new struct of CompanyPostgres */
 func NewCompanyPostgres(
 	db *sqlx.DB,
-	enforcer *casbin.Enforcer,
+	enforcer *Enforcer,
 	role *RolePostgres,
 	user *UserPostgres,
 	wrapper *WrapperPostgres,
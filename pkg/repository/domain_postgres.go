@@ -3,21 +3,92 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"main-server/config"
 	tableConstants "main-server/pkg/constant/table"
 	rbacModel "main-server/pkg/model/rbac"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+/* Допустимый формат значений доменов, ролей и типов объектов */
+var rbacValuePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,62}$`)
+
+/* Проверка значения домена, роли или типа объекта */
+func checkRbacValue(value string) error {
+	if !rbacValuePattern.MatchString(value) {
+		return errors.New("Значение должно начинаться с латинской буквы и содержать только строчные латинские буквы, цифры и _ (от 2 до 63 символов)!")
+	}
+
+	return nil
+}
+
+/* Найденный домен и время окончания его хранения в кэше */
+type domainCacheEntry struct {
+	domain    rbacModel.DomainModel
+	expiresAt time.Time
+}
+
 type DomainPostgres struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	mu    sync.RWMutex
+	cache map[string]domainCacheEntry
 }
 
 /*
 * Функция создания экземпляра сервиса
  */
 func NewDomainPostgres(db *sqlx.DB) *DomainPostgres {
-	return &DomainPostgres{db: db}
+	return &DomainPostgres{
+		db:    db,
+		cache: make(map[string]domainCacheEntry),
+	}
+}
+
+/*
+* Определение домена запроса по значению (с кэшированием). Домен мог быть изменён другим экземпляром
+* сервера, поэтому записи кэша хранятся ограниченное время
+ */
+func (r *DomainPostgres) Resolve(value string) (*rbacModel.DomainModel, error) {
+	r.mu.RLock()
+	entry, ok := r.cache[value]
+	r.mu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return &entry.domain, nil
+	}
+
+	domain, err := r.Get("value", value, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain == nil {
+		return nil, errors.New("Домен не найден!")
+	}
+
+	r.mu.Lock()
+	r.cache[value] = domainCacheEntry{
+		domain:    *domain,
+		expiresAt: time.Now().Add(config.AppDomainConfig.CacheTTL),
+	}
+	r.mu.Unlock()
+
+	return domain, nil
+}
+
+/* Сброс кэша доменов (после изменения или удаления домена) */
+func (r *DomainPostgres) invalidate(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, value := range values {
+		delete(r.cache, value)
+	}
 }
 
 /* Получение информации о домене */
@@ -46,3 +117,146 @@ func (r *DomainPostgres) Get(column string, value interface{}, check bool) (*rba
 
 	return &domains[len(domains)-1], err
 }
+
+/* Получение списка доменов */
+func (r *DomainPostgres) GetDomains() (rbacModel.DomainsModel, error) {
+	domains := []rbacModel.DomainModel{}
+
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY id", tableConstants.AC_DOMAINS)
+	if err := r.db.Select(&domains, query); err != nil {
+		return rbacModel.DomainsModel{}, err
+	}
+
+	return rbacModel.DomainsModel{Domains: domains}, nil
+}
+
+/* Создание нового домена */
+func (r *DomainPostgres) CreateDomain(usersId int, data rbacModel.DomainCreateModel) (rbacModel.DomainModel, error) {
+	value := strings.TrimSpace(data.Value)
+	if err := checkRbacValue(value); err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	exists, err := r.Get("value", value, false)
+	if err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	if exists != nil {
+		return rbacModel.DomainModel{}, errors.New("Домен с данным значением уже существует!")
+	}
+
+	var domain rbacModel.DomainModel
+
+	query := fmt.Sprintf("INSERT INTO %s (value, description, users_id) values ($1, $2, $3) RETURNING *", tableConstants.AC_DOMAINS)
+	if err := r.db.Get(&domain, query, value, data.Description, usersId); err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	return domain, nil
+}
+
+/* Изменение значения или описания домена (значение доменов, заданных в конфигурации сервера, не изменяется) */
+func (r *DomainPostgres) UpdateDomain(data rbacModel.DomainUpdateModel) (rbacModel.DomainModel, error) {
+	domain, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	value := domain.Value
+	if data.Value != nil && strings.TrimSpace(*data.Value) != domain.Value {
+		value = strings.TrimSpace(*data.Value)
+
+		if err := checkRbacValue(value); err != nil {
+			return rbacModel.DomainModel{}, err
+		}
+
+		// Основной домен приложения и домены, привязанные к именам хостов, задаются в конфигурации по значению
+		if config.IsConfiguredDomain(domain.Value) {
+			return rbacModel.DomainModel{}, errors.New("Значение домена, заданного в конфигурации сервера, изменить нельзя!")
+		}
+
+		exists, err := r.Get("value", value, false)
+		if err != nil {
+			return rbacModel.DomainModel{}, err
+		}
+
+		if exists != nil {
+			return rbacModel.DomainModel{}, errors.New("Домен с данным значением уже существует!")
+		}
+	}
+
+	description := domain.Description
+	if data.Description != nil {
+		description = *data.Description
+	}
+
+	var result rbacModel.DomainModel
+
+	query := fmt.Sprintf("UPDATE %s SET value=$1, description=$2 WHERE id=$3 RETURNING *", tableConstants.AC_DOMAINS)
+	if err := r.db.Get(&result, query, value, description, domain.Id); err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	r.invalidate(domain.Value, result.Value)
+
+	return result, nil
+}
+
+/*
+* Удаление домена вместе с его ролями. Домен, на который ссылаются правила управления доступом,
+* и домены, заданные в конфигурации сервера, удалить нельзя
+ */
+func (r *DomainPostgres) DeleteDomain(data rbacModel.DomainUuidModel) (bool, error) {
+	domain, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	if config.IsConfiguredDomain(domain.Value) {
+		return false, errors.New("Домен, заданный в конфигурации сервера, удалить нельзя!")
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	// Блокировка домена исключает параллельное добавление ролей в удаляемый домен
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=$1 FOR UPDATE", tableConstants.AC_DOMAINS)
+	if _, err := tx.Exec(query, domain.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	var used bool
+
+	// В правилах группировки домен указывается в v2, в политиках - в v1
+	query = fmt.Sprintf(
+		`SELECT EXISTS (SELECT 1 FROM %s WHERE (ptype = 'g' AND v2 = $1) OR (ptype = 'p' AND v1 = $1))`,
+		tableConstants.AC_RULES,
+	)
+	if err := tx.Get(&used, query, strconv.Itoa(domain.Id)); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if used {
+		tx.Rollback()
+		return false, errors.New("Домен используется в правилах управления доступом и не может быть удалён!")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", tableConstants.AC_DOMAINS)
+	if _, err := tx.Exec(query, domain.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	r.invalidate(domain.Value)
+
+	return true, nil
+}
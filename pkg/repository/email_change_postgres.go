@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	tableConstant "main-server/pkg/constant/table"
+	"main-server/pkg/model/email"
+	userModel "main-server/pkg/model/user"
+	smtpService "main-server/pkg/service/smtp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/* Единая ошибка подтверждения или отмены смены email-адреса (токен некорректен, просрочен или уже использован) */
+var ErrInvalidEmailChangeToken = errors.New("Некорректная или устаревшая ссылка для смены email-адреса")
+
+type EmailChangePostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры EmailChangePostgres */
+func NewEmailChangePostgres(db *sqlx.DB) *EmailChangePostgres {
+	return &EmailChangePostgres{db: db}
+}
+
+/* Получение состояния запроса на смену email-адреса */
+func (r *EmailChangePostgres) GetEmailChange(usersId int) (userModel.EmailChangeStatusModel, error) {
+	var changes []userModel.EmailChangeDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id=$1 AND expires_at > $2 LIMIT 1", tableConstant.U_EMAIL_CHANGES)
+	if err := r.db.Select(&changes, query, usersId, time.Now()); err != nil {
+		return userModel.EmailChangeStatusModel{}, err
+	}
+
+	if len(changes) <= 0 {
+		return userModel.EmailChangeStatusModel{Pending: false}, nil
+	}
+
+	return userModel.EmailChangeStatusModel{
+		Pending:   true,
+		NewEmail:  changes[0].NewEmail,
+		ExpiresAt: &changes[0].ExpiresAt,
+	}, nil
+}
+
+/*
+* Запрос на смену email-адреса: на новый адрес отправляется ссылка подтверждения, на текущий - ссылка отмены.
+* Новый запрос заменяет предыдущий
+ */
+func (r *EmailChangePostgres) RequestEmailChange(usersId int, data userModel.EmailChangeModel) (bool, error) {
+	var user userModel.UserModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1 LIMIT 1", tableConstant.U_USERS)
+	if err := r.db.Get(&user, query, usersId); err != nil {
+		return false, errors.New("Пользователь не найден!")
+	}
+
+	if err := checkAccountPassword(r.db, user, data.Password); err != nil {
+		return false, err
+	}
+
+	newEmail := strings.TrimSpace(data.Email)
+	if strings.EqualFold(newEmail, user.Email) {
+		return false, errors.New("Новый email-адрес совпадает с текущим!")
+	}
+
+	var exists bool
+	query = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE LOWER(email) = LOWER($1))", tableConstant.U_USERS)
+	if err := r.db.Get(&exists, query, newEmail); err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, errors.New("Пользователь с данным email-адресом уже существует!")
+	}
+
+	confirmToken, err := randomHex(authConstant.EMAIL_CHANGE_TOKEN_BYTES)
+	if err != nil {
+		return false, err
+	}
+
+	cancelToken, err := randomHex(authConstant.EMAIL_CHANGE_TOKEN_BYTES)
+	if err != nil {
+		return false, err
+	}
+
+	currentDate := time.Now()
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (users_id, new_email, confirm_hash, cancel_hash, created_at, expires_at) values ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (users_id) DO UPDATE SET
+			new_email = EXCLUDED.new_email,
+			confirm_hash = EXCLUDED.confirm_hash,
+			cancel_hash = EXCLUDED.cancel_hash,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at`,
+		tableConstant.U_EMAIL_CHANGES,
+	)
+
+	_, err = r.db.Exec(query, usersId, newEmail, hashOneTimeValue(confirmToken), hashOneTimeValue(cancelToken),
+		currentDate, currentDate.Add(authConstant.TOKEN_TLL_EMAIL_CHANGE),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := sendEmailChangeConfirm(newEmail, confirmToken); err != nil {
+		return false, err
+	}
+
+	sendEmailChangeCancel(user.Email, newEmail, cancelToken)
+
+	return true, nil
+}
+
+/*
+* Подтверждение смены email-адреса токеном из письма, отправленного на новый адрес.
+* Адрес заменяется в одной транзакции с завершением всех сессий пользователя, кроме текущей
+ */
+func (r *EmailChangePostgres) ConfirmEmailChange(usersId int, token, currentSessionUuid string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	var newEmail string
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE users_id=$1 AND confirm_hash=$2 AND expires_at > $3 RETURNING new_email",
+		tableConstant.U_EMAIL_CHANGES,
+	)
+
+	row := tx.QueryRow(query, usersId, hashOneTimeValue(token), time.Now())
+	if err := row.Scan(&newEmail); err != nil {
+		tx.Rollback()
+		return false, ErrInvalidEmailChangeToken
+	}
+
+	// Адрес мог быть занят другим пользователем после создания запроса (email уникален)
+	query = fmt.Sprintf("UPDATE %s SET email=$1 WHERE id=$2", tableConstant.U_USERS)
+	if _, err := tx.Exec(query, newEmail, usersId); err != nil {
+		tx.Rollback()
+		return false, errors.New("Пользователь с данным email-адресом уже существует!")
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s tl SET email=$1 FROM %s tat
+		WHERE tat.id = tl.auth_types_id AND tl.users_id = $2 AND tat.value = $3`,
+		tableConstant.U_USERS_AUTH_TYPES, tableConstant.U_AUTH_TYPES,
+	)
+	if _, err := tx.Exec(query, newEmail, usersId, authConstant.AUTH_TYPE_LOCAL); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Токены сброса пароля содержат прежний email-адрес
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.U_RESET_TOKENS)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := revokeOtherUserSessions(tx, usersId, currentSessionUuid); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Отмена смены email-адреса по ссылке из письма, отправленного на текущий адрес (вход в аккаунт не требуется) */
+func (r *EmailChangePostgres) CancelEmailChange(token string) (bool, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE cancel_hash=$1", tableConstant.U_EMAIL_CHANGES)
+
+	result, err := r.db.Exec(query, hashOneTimeValue(token))
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count <= 0 {
+		return false, ErrInvalidEmailChangeToken
+	}
+
+	return true, nil
+}
+
+/* Отправка ссылки подтверждения на новый email-адрес */
+func sendEmailChangeConfirm(newEmail, token string) error {
+	return smtpService.SendMessage(newEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{newEmail},
+		Subject: "Подтверждение нового email-адреса",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+		</style>
+		<body>
+			<h2>Подтверждение нового email-адреса</h2>
+			<br><text>Этот адрес был указан как новый email-адрес аккаунта в приложении "МИСУ Мирный".</text>
+			</br><text>Чтобы подтвердить смену адреса, перейдите по ссылке (действительна %d ч.): </text></br>
+			<a href="%s">Подтвердить email-адрес</a>
+			<br><br><br>
+			<text>Если Вы не запрашивали смену email-адреса, то не отвечайте на данное сообщение.</text>
+		</body>
+	</html>`, int(authConstant.TOKEN_TLL_EMAIL_CHANGE.Hours()), viper.GetString("crm_url")+"/profile/email/confirm/"+token),
+	}))
+}
+
+/* Уведомление текущего email-адреса о запросе на смену со ссылкой отмены */
+func sendEmailChangeCancel(currentEmail, newEmail, token string) {
+	err := smtpService.SendMessage(currentEmail, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{currentEmail},
+		Subject: "Запрос на смену email-адреса",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+		</style>
+		<body>
+			<h2>Запрос на смену email-адреса</h2>
+			<br><text>Для Вашего аккаунта запрошена смена email-адреса на %s.</text>
+			</br><text>Если это были не Вы, отмените смену адреса по ссылке и смените пароль: </text></br>
+			<a href="%s">Отменить смену email-адреса</a>
+		</body>
+	</html>`, newEmail, viper.GetString("crm_url")+"/auth/email/cancel/"+token),
+	}))
+
+	if err != nil {
+		logrus.Error(err.Error())
+	}
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	userModel "main-server/pkg/model/user"
+	"strconv"
+)
+
+/* Проверка доступа пользователя хотя бы к одному из информационных ресурсов */
+func enforceAny(enforcer *Enforcer, user userModel.UserIdentityModel, action string, objects ...string) (bool, error) {
+	userIdStr := strconv.Itoa(user.UserId)
+	domainIdStr := strconv.Itoa(user.DomainId)
+
+	for _, object := range objects {
+		access, err := enforcer.Enforce(userIdStr, domainIdStr, object, action)
+		if err != nil {
+			return false, err
+		}
+
+		if access {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/casbin/casbin/v2"
+)
+
+/*
+* Enforcer приложения: проверки доступа и чтение правил выполняются под блокировкой чтения,
+* изменения правил (в том числе полученные наблюдателем от других экземпляров сервера) - под монопольной.
+* В используемой версии casbin блокировка SyncedEnforcer недоступна снаружи, поэтому изменения модели
+* наблюдателем не могли бы исключать параллельные проверки доступа. Методы встроенного casbin.Enforcer,
+* не переопределённые здесь, выполняются без блокировки
+ */
+type Enforcer struct {
+	*casbin.Enforcer
+	mu sync.RWMutex
+}
+
+/* Функция создания enforcer (параметры - как у casbin.NewEnforcer) */
+func NewEnforcer(params ...interface{}) (*Enforcer, error) {
+	enforcer, err := casbin.NewEnforcer(params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Enforcer{Enforcer: enforcer}, nil
+}
+
+/* Выполнение изменения модели под монопольной блокировкой */
+func (e *Enforcer) withLock(fn func() error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return fn()
+}
+
+func (e *Enforcer) AddFunction(name string, function func(args ...interface{}) (interface{}, error)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.Enforcer.AddFunction(name, function)
+}
+
+func (e *Enforcer) LoadPolicy() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.LoadPolicy()
+}
+
+func (e *Enforcer) Enforce(rvals ...interface{}) (bool, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.Enforce(rvals...)
+}
+
+func (e *Enforcer) EnforceEx(rvals ...interface{}) (bool, []string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.EnforceEx(rvals...)
+}
+
+func (e *Enforcer) GetPolicy() [][]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.GetPolicy()
+}
+
+func (e *Enforcer) GetFilteredPolicy(fieldIndex int, fieldValues ...string) [][]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.GetFilteredPolicy(fieldIndex, fieldValues...)
+}
+
+func (e *Enforcer) GetFilteredGroupingPolicy(fieldIndex int, fieldValues ...string) [][]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.GetFilteredGroupingPolicy(fieldIndex, fieldValues...)
+}
+
+func (e *Enforcer) GetRolesForUser(name string, domain ...string) ([]string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.GetRolesForUser(name, domain...)
+}
+
+func (e *Enforcer) HasRoleForUser(name string, role string, domain ...string) (bool, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.Enforcer.HasRoleForUser(name, role, domain...)
+}
+
+func (e *Enforcer) AddPolicy(params ...interface{}) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.AddPolicy(params...)
+}
+
+func (e *Enforcer) AddPolicies(rules [][]string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.AddPolicies(rules)
+}
+
+func (e *Enforcer) RemovePolicies(rules [][]string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.RemovePolicies(rules)
+}
+
+func (e *Enforcer) RemoveFilteredPolicy(fieldIndex int, fieldValues ...string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.RemoveFilteredPolicy(fieldIndex, fieldValues...)
+}
+
+func (e *Enforcer) AddRoleForUserInDomain(user string, role string, domain string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.AddRoleForUserInDomain(user, role, domain)
+}
+
+func (e *Enforcer) DeleteUser(user string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.Enforcer.DeleteUser(user)
+}
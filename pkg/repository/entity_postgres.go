@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	actionConstant "main-server/pkg/constant/action"
+	objectConstant "main-server/pkg/constant/object"
+	tableConstant "main-server/pkg/constant/table"
+	entityModel "main-server/pkg/model/entity"
+	rbacModel "main-server/pkg/model/rbac"
+	userModel "main-server/pkg/model/user"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+)
+
+type EntityPostgres struct {
+	db       *sqlx.DB
+	enforcer *Enforcer
+	object   *ObjectPostgres
+}
+
+/* Функция создания нового экземпляра структуры EntityPostgres */
+func NewEntityPostgres(
+	db *sqlx.DB,
+	enforcer *Enforcer,
+	object *ObjectPostgres,
+) *EntityPostgres {
+	return &EntityPostgres{
+		db:       db,
+		enforcer: enforcer,
+		object:   object,
+	}
+}
+
+/* Информация о проекте, к которому привязывается объект */
+type SQLResultEntityProject struct {
+	Id          int    `db:"id"`
+	CompanyUuid string `db:"company_uuid"`
+	ManagerId   *int   `db:"manager_id"`
+}
+
+/* Получение информации о проекте (компания и менеджер проекта) */
+func (r *EntityPostgres) getProject(projectUuid string) (*SQLResultEntityProject, error) {
+	var projects []SQLResultEntityProject
+
+	query := fmt.Sprintf(
+		`SELECT p.id, c.uuid AS company_uuid, w.users_id AS manager_id FROM %s p
+		INNER JOIN %s c ON c.id = p.companies_id
+		LEFT JOIN %s w ON w.id = p.workers_id
+		WHERE p.uuid = $1 LIMIT 1`,
+		tableConstant.CB_PROJECTS,
+		tableConstant.CB_COMPANIES,
+		tableConstant.CB_WORKERS,
+	)
+
+	if err := r.db.Select(&projects, query, projectUuid); err != nil {
+		return nil, err
+	}
+
+	if len(projects) <= 0 {
+		return nil, errors.New(fmt.Sprintf("Ошибка: проекта по запросу uuid:%s не найдено!", projectUuid))
+	}
+
+	return &projects[0], nil
+}
+
+/* Создание нового объекта (здания) в рамках проекта */
+func (r *EntityPostgres) CreateEntity(user userModel.UserIdentityModel, data entityModel.EntityCreateModel) (entityModel.EntityDbDataEx, error) {
+	project, err := r.getProject(data.ProjectUuid)
+	if err != nil {
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	// Создавать объекты могут менеджеры проекта и администраторы компании (права на компанию наследуются проектом)
+	access, err := enforceAny(r.enforcer, user, actionConstant.MODIFY, data.ProjectUuid)
+	if err != nil {
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	if !access {
+		return entityModel.EntityDbDataEx{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	entityData := entityModel.EntityDataModel{
+		Logo:        data.Logo,
+		Title:       data.Title,
+		Description: data.Description,
+		Address:     data.Address,
+		Floors:      data.Floors,
+	}
+
+	dataJson, err := json.Marshal(entityData)
+	if err != nil {
+		tx.Rollback()
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	// Добавление информации об объекте в БД
+	query := fmt.Sprintf("INSERT INTO %s (uuid, data, created_at, updated_at, projects_id) values ($1, $2, $3, $4, $5)", tableConstant.CB_ENTITIES)
+
+	entityUuid := uuid.NewV4()
+	currentDate := time.Now()
+
+	_, err = tx.Exec(query, entityUuid, dataJson, currentDate, currentDate, project.Id)
+	if err != nil {
+		tx.Rollback()
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	// Регистрация объекта как дочернего информационного ресурса проекта
+	var resource rbacModel.ResourceModel
+	resource.ParentUuid = &data.ProjectUuid
+	resource.TypeResource = objectConstant.ENTITY
+	resource.Resource.ResourceUuid = entityUuid.String()
+	resource.Resource.Description = fmt.Sprintf("Объект проекта: %s", data.Title)
+
+	_, err = r.object.AddResource(&resource)
+	if err != nil {
+		tx.Rollback()
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	// Права на объект получают создатель и менеджер проекта
+	usersId := []int{user.UserId}
+	if project.ManagerId != nil && *project.ManagerId != user.UserId {
+		usersId = append(usersId, *project.ManagerId)
+	}
+
+	var policies [][]string
+	for _, id := range usersId {
+		policies = append(policies,
+			[]string{strconv.Itoa(id), strconv.Itoa(user.DomainId), entityUuid.String(), actionConstant.DELETE, actionConstant.ALLOW},
+			[]string{strconv.Itoa(id), strconv.Itoa(user.DomainId), entityUuid.String(), actionConstant.MODIFY, actionConstant.ALLOW},
+			[]string{strconv.Itoa(id), strconv.Itoa(user.DomainId), entityUuid.String(), actionConstant.READ, actionConstant.ALLOW},
+		)
+	}
+
+	_, err = r.enforcer.AddPolicies(policies)
+	if err != nil {
+		tx.Rollback()
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.enforcer.RemovePolicies(policies)
+		tx.Rollback()
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	return entityModel.EntityDbDataEx{
+		Uuid:      entityUuid.String(),
+		Data:      entityData,
+		CreatedAt: currentDate,
+	}, nil
+}
+
+/* Обновление информации об объекте */
+func (r *EntityPostgres) EntityUpdate(user userModel.UserIdentityModel, data entityModel.EntityUpdateModel) (entityModel.EntityUpdateModel, error) {
+	access, err := enforceAny(r.enforcer, user, actionConstant.MODIFY, data.Uuid)
+	if err != nil {
+		return entityModel.EntityUpdateModel{}, err
+	}
+
+	if !access {
+		return entityModel.EntityUpdateModel{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	entity, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return entityModel.EntityUpdateModel{}, err
+	}
+
+	var entityData entityModel.EntityDataModel
+	if err := json.Unmarshal([]byte(entity.Data), &entityData); err != nil {
+		return entityModel.EntityUpdateModel{}, err
+	}
+
+	entityData.Title = data.Title
+	entityData.Description = data.Description
+	entityData.Address = data.Address
+	entityData.Floors = data.Floors
+
+	entityDataJson, err := json.Marshal(entityData)
+	if err != nil {
+		return entityModel.EntityUpdateModel{}, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s tl SET data=$1, updated_at=$2 WHERE tl.uuid=$3", tableConstant.CB_ENTITIES)
+
+	if _, err := r.db.Exec(query, entityDataJson, time.Now(), data.Uuid); err != nil {
+		return entityModel.EntityUpdateModel{}, err
+	}
+
+	return data, nil
+}
+
+/* Получение информации об одном объекте */
+func (r *EntityPostgres) GetEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (entityModel.EntityDbDataEx, error) {
+	access, err := enforceAny(r.enforcer, user, actionConstant.READ, data.Uuid)
+	if err != nil {
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	if !access {
+		return entityModel.EntityDbDataEx{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	entity, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	var entityData entityModel.EntityDataModel
+	if err := json.Unmarshal([]byte(entity.Data), &entityData); err != nil {
+		return entityModel.EntityDbDataEx{}, err
+	}
+
+	return entityModel.EntityDbDataEx{
+		Uuid:      entity.Uuid,
+		Data:      entityData,
+		CreatedAt: entity.CreatedAt,
+	}, nil
+}
+
+/* Получение среза объектов проекта */
+func (r *EntityPostgres) GetEntities(user userModel.UserIdentityModel, data entityModel.EntityCountModel) (entityModel.EntityAnyCountModel, error) {
+	project, err := r.getProject(data.Uuid)
+	if err != nil {
+		return entityModel.EntityAnyCountModel{}, err
+	}
+
+	access, err := enforceAny(r.enforcer, user, actionConstant.READ, data.Uuid)
+	if err != nil {
+		return entityModel.EntityAnyCountModel{}, err
+	}
+
+	if !access {
+		return entityModel.EntityAnyCountModel{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	var entities []entityModel.EntityLowInfoModel
+	sum := (data.Count + data.Limit)
+
+	query := fmt.Sprintf("SELECT uuid, data, created_at FROM %s tl WHERE tl.projects_id = $1", tableConstant.CB_ENTITIES)
+	if err := r.db.Select(&entities, query, project.Id); err != nil {
+		return entityModel.EntityAnyCountModel{}, err
+	}
+
+	var entitiesEx []entityModel.EntityDbDataEx
+	for _, element := range entities {
+		var entityData entityModel.EntityDataModel
+		if err := json.Unmarshal([]byte(element.Data), &entityData); err != nil {
+			return entityModel.EntityAnyCountModel{}, err
+		}
+
+		entitiesEx = append(entitiesEx, entityModel.EntityDbDataEx{
+			Uuid:      element.Uuid,
+			Data:      entityData,
+			CreatedAt: element.CreatedAt,
+		})
+	}
+
+	sort.SliceStable(entitiesEx, func(i, j int) bool {
+		return entitiesEx[i].CreatedAt.After(entitiesEx[j].CreatedAt)
+	})
+
+	if data.Count >= len(entitiesEx) {
+		return entityModel.EntityAnyCountModel{}, nil
+	}
+
+	if sum >= len(entitiesEx) {
+		sum -= (sum - len(entitiesEx))
+	}
+
+	return entityModel.EntityAnyCountModel{
+		Entities: entitiesEx[data.Count:sum],
+		Count:    (sum - data.Count),
+	}, nil
+}
+
+/* Удаление объекта вместе с его информационным ресурсом и правилами доступа */
+func (r *EntityPostgres) DeleteEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (bool, error) {
+	access, err := enforceAny(r.enforcer, user, actionConstant.DELETE, data.Uuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !access {
+		return false, errors.New("Ошибка! Нет доступа!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	// Помещения объекта удаляются каскадно, поэтому их идентификаторы сохраняются заранее
+	var subEntitiesUuid []string
+	query := fmt.Sprintf(
+		`SELECT s.uuid FROM %s s INNER JOIN %s e ON e.id = s.entities_id WHERE e.uuid = $1`,
+		tableConstant.CB_SUB_ENTITIES,
+		tableConstant.CB_ENTITIES,
+	)
+
+	if err := r.db.Select(&subEntitiesUuid, query, data.Uuid); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.uuid = $1", tableConstant.CB_ENTITIES)
+	if _, err := tx.Exec(query, data.Uuid); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Дочерние информационные ресурсы (помещения) удаляются каскадно по parent_id
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.value = $1", tableConstant.AC_OBJECTS)
+	if _, err := tx.Exec(query, data.Uuid); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Удаление всех правил, связанных с объектом и его помещениями
+	for _, value := range append(subEntitiesUuid, data.Uuid) {
+		if _, err := r.enforcer.RemoveFilteredPolicy(2, value); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+/* Получение экземпляра объекта таблицы */
+func (r *EntityPostgres) Get(column string, value interface{}, check bool) (*entityModel.EntityDbModel, error) {
+	var entities []entityModel.EntityDbModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s=$1", tableConstant.CB_ENTITIES, column)
+
+	var err error
+
+	switch value.(type) {
+	case int:
+		err = r.db.Select(&entities, query, value.(int))
+		break
+	case string:
+		err = r.db.Select(&entities, query, value.(string))
+		break
+	}
+
+	if len(entities) <= 0 {
+		if check {
+			return nil, errors.New(fmt.Sprintf("Ошибка: объекта по запросу %s:%s не найдено!", column, value))
+		}
+
+		return nil, nil
+	}
+
+	return &entities[len(entities)-1], err
+}
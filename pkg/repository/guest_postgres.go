@@ -0,0 +1,433 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	statusConstant "main-server/pkg/constant/status"
+	tableConstant "main-server/pkg/constant/table"
+	guestModel "main-server/pkg/model/guest"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/* Формат представления даты в курсоре постраничной навигации */
+const guestCursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+type GuestPostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры GuestPostgres */
+func NewGuestPostgres(db *sqlx.DB) *GuestPostgres {
+	return &GuestPostgres{
+		db: db,
+	}
+}
+
+/* Колонки, по которым допускается сортировка помещений, и их типы в БД */
+var guestSubEntitySort = map[string][2]string{
+	"price":      {"s.price", "numeric"},
+	"area":       {"s.area", "numeric"},
+	"rooms":      {"s.rooms", "integer"},
+	"created_at": {"s.created_at", "timestamp"},
+}
+
+/* Ключ сортировки, к которой привязан курсор (поле и направление) */
+func guestCursorSort(sortBy, sortOrder string) string {
+	return sortBy + ":" + sortOrder
+}
+
+/* Проверка соответствия значения курсора типу колонки сортировки */
+func isGuestCursorValue(value, cast string) bool {
+	var err error
+
+	switch cast {
+	case "numeric":
+		_, err = strconv.ParseFloat(value, 64)
+	case "integer":
+		_, err = strconv.Atoi(value)
+	case "timestamp":
+		_, err = time.Parse(guestCursorTimeLayout, value)
+	}
+
+	return err == nil
+}
+
+/* Кодирование курсора постраничной навигации */
+func encodeGuestCursor(sort, value string, id int) (*string, error) {
+	cursorJson, err := json.Marshal(guestModel.CursorModel{
+		Sort:  sort,
+		Value: value,
+		Id:    id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := base64.RawURLEncoding.EncodeToString(cursorJson)
+	return &cursor, nil
+}
+
+/*
+ * Формирование условия выборки следующей страницы по курсору (keyset pagination).
+ * Сравнение выполняется по паре (колонка сортировки, id), что исключает пропуски
+ * и повторы записей при одинаковых значениях сортируемой колонки.
+ * Курсор, выданный для другой сортировки, отклоняется.
+ */
+func guestCursorCondition(cursor *string, sort, column, idColumn, cast string, desc bool, args *[]interface{}) (string, error) {
+	if cursor == nil || *cursor == "" {
+		return "", nil
+	}
+
+	cursorJson, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil {
+		return "", errors.New("Ошибка: некорректный курсор!")
+	}
+
+	var data guestModel.CursorModel
+	if err := json.Unmarshal(cursorJson, &data); err != nil {
+		return "", errors.New("Ошибка: некорректный курсор!")
+	}
+
+	if data.Sort != sort {
+		return "", errors.New("Ошибка: курсор не соответствует выбранной сортировке!")
+	}
+
+	if !isGuestCursorValue(data.Value, cast) {
+		return "", errors.New("Ошибка: некорректный курсор!")
+	}
+
+	operator := ">"
+	if desc {
+		operator = "<"
+	}
+
+	*args = append(*args, data.Value, data.Id)
+
+	return fmt.Sprintf(
+		" AND (%s, %s) %s ($%d::%s, $%d)",
+		column, idColumn, operator, len(*args)-1, cast, len(*args),
+	), nil
+}
+
+/* Получение списка компаний */
+func (r *GuestPostgres) GetCompanies(data guestModel.GuestCompanyQueryModel) (guestModel.GuestCompanyAnyCountModel, error) {
+	var args []interface{}
+	query := fmt.Sprintf("SELECT c.id, c.uuid, c.data, c.created_at FROM %s c WHERE TRUE", tableConstant.CB_COMPANIES)
+
+	if data.Title != nil && *data.Title != "" {
+		args = append(args, "%"+*data.Title+"%")
+		query += fmt.Sprintf(" AND c.data->>'title' ILIKE $%d", len(args))
+	}
+
+	condition, err := guestCursorCondition(data.Cursor, guestCursorSort("created_at", "desc"), "c.created_at", "c.id", "timestamp", true, &args)
+	if err != nil {
+		return guestModel.GuestCompanyAnyCountModel{}, err
+	}
+
+	args = append(args, data.Limit+1)
+	query += condition + fmt.Sprintf(" ORDER BY c.created_at DESC, c.id DESC LIMIT $%d", len(args))
+
+	var rows []struct {
+		Id        int       `db:"id"`
+		Uuid      string    `db:"uuid"`
+		Data      string    `db:"data"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return guestModel.GuestCompanyAnyCountModel{}, err
+	}
+
+	var result guestModel.GuestCompanyAnyCountModel
+
+	for i, row := range rows {
+		if i >= data.Limit {
+			last := rows[i-1]
+			if result.NextCursor, err = encodeGuestCursor(guestCursorSort("created_at", "desc"), last.CreatedAt.Format(guestCursorTimeLayout), last.Id); err != nil {
+				return guestModel.GuestCompanyAnyCountModel{}, err
+			}
+			break
+		}
+
+		var companyData guestModel.GuestCompanyDataModel
+		if err := json.Unmarshal([]byte(row.Data), &companyData); err != nil {
+			return guestModel.GuestCompanyAnyCountModel{}, err
+		}
+
+		result.Companies = append(result.Companies, guestModel.GuestCompanyModel{
+			Uuid:      row.Uuid,
+			Data:      companyData,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	result.Count = len(result.Companies)
+	return result, nil
+}
+
+/* Получение списка проектов */
+func (r *GuestPostgres) GetProjects(data guestModel.GuestProjectQueryModel) (guestModel.GuestProjectAnyCountModel, error) {
+	var args []interface{}
+	query := fmt.Sprintf(
+		`SELECT p.id, p.uuid, p.data, p.created_at, c.uuid AS company_uuid FROM %s p
+		INNER JOIN %s c ON c.id = p.companies_id WHERE TRUE`,
+		tableConstant.CB_PROJECTS,
+		tableConstant.CB_COMPANIES,
+	)
+
+	if data.CompanyUuid != nil && *data.CompanyUuid != "" {
+		args = append(args, *data.CompanyUuid)
+		query += fmt.Sprintf(" AND c.uuid = $%d", len(args))
+	}
+
+	condition, err := guestCursorCondition(data.Cursor, guestCursorSort("created_at", "desc"), "p.created_at", "p.id", "timestamp", true, &args)
+	if err != nil {
+		return guestModel.GuestProjectAnyCountModel{}, err
+	}
+
+	args = append(args, data.Limit+1)
+	query += condition + fmt.Sprintf(" ORDER BY p.created_at DESC, p.id DESC LIMIT $%d", len(args))
+
+	var rows []struct {
+		Id          int       `db:"id"`
+		Uuid        string    `db:"uuid"`
+		Data        string    `db:"data"`
+		CreatedAt   time.Time `db:"created_at"`
+		CompanyUuid string    `db:"company_uuid"`
+	}
+
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return guestModel.GuestProjectAnyCountModel{}, err
+	}
+
+	var result guestModel.GuestProjectAnyCountModel
+
+	for i, row := range rows {
+		if i >= data.Limit {
+			last := rows[i-1]
+			if result.NextCursor, err = encodeGuestCursor(guestCursorSort("created_at", "desc"), last.CreatedAt.Format(guestCursorTimeLayout), last.Id); err != nil {
+				return guestModel.GuestProjectAnyCountModel{}, err
+			}
+			break
+		}
+
+		var projectData guestModel.GuestProjectDataModel
+		if err := json.Unmarshal([]byte(row.Data), &projectData); err != nil {
+			return guestModel.GuestProjectAnyCountModel{}, err
+		}
+
+		result.Projects = append(result.Projects, guestModel.GuestProjectModel{
+			Uuid:        row.Uuid,
+			CompanyUuid: row.CompanyUuid,
+			Data:        projectData,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+
+	result.Count = len(result.Projects)
+	return result, nil
+}
+
+/* Строка выборки помещения вместе с публичной информацией о его объекте, проекте и компании */
+type guestSubEntityRow struct {
+	Id           int       `db:"id"`
+	Uuid         string    `db:"uuid"`
+	Data         string    `db:"data"`
+	Rooms        int       `db:"rooms"`
+	Area         float64   `db:"area"`
+	Floor        int       `db:"floor"`
+	Price        float64   `db:"price"`
+	Status       string    `db:"status"`
+	CreatedAt    time.Time `db:"created_at"`
+	Address      string    `db:"address"`
+	EntityUuid   string    `db:"entity_uuid"`
+	EntityTitle  string    `db:"entity_title"`
+	ProjectUuid  string    `db:"project_uuid"`
+	CompanyUuid  string    `db:"company_uuid"`
+	CompanyTitle string    `db:"company_title"`
+}
+
+/* Базовый запрос выборки доступных для аренды помещений */
+func guestSubEntityQuery() string {
+	return fmt.Sprintf(
+		`SELECT s.id, s.uuid, s.data, s.rooms, s.area, s.floor, s.price, s.status, s.created_at,
+			COALESCE(e.data->>'address', '') AS address, e.uuid AS entity_uuid,
+			COALESCE(e.data->>'title', '') AS entity_title, p.uuid AS project_uuid,
+			c.uuid AS company_uuid, COALESCE(c.data->>'title', '') AS company_title
+		FROM %s s
+		INNER JOIN %s e ON e.id = s.entities_id
+		INNER JOIN %s p ON p.id = e.projects_id
+		INNER JOIN %s c ON c.id = p.companies_id
+		WHERE s.status = '%s'`,
+		tableConstant.CB_SUB_ENTITIES,
+		tableConstant.CB_ENTITIES,
+		tableConstant.CB_PROJECTS,
+		tableConstant.CB_COMPANIES,
+		statusConstant.SUB_ENTITY_AVAILABLE,
+	)
+}
+
+/* Преобразование строки выборки в публичную модель помещения */
+func (row *guestSubEntityRow) toModel() (guestModel.GuestSubEntityModel, error) {
+	var data struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+
+	if err := json.Unmarshal([]byte(row.Data), &data); err != nil {
+		return guestModel.GuestSubEntityModel{}, err
+	}
+
+	return guestModel.GuestSubEntityModel{
+		Uuid:         row.Uuid,
+		Title:        data.Title,
+		Description:  data.Description,
+		Rooms:        row.Rooms,
+		Area:         row.Area,
+		Floor:        row.Floor,
+		Price:        row.Price,
+		Status:       row.Status,
+		Address:      row.Address,
+		EntityUuid:   row.EntityUuid,
+		EntityTitle:  row.EntityTitle,
+		ProjectUuid:  row.ProjectUuid,
+		CompanyUuid:  row.CompanyUuid,
+		CompanyTitle: row.CompanyTitle,
+		CreatedAt:    row.CreatedAt,
+	}, nil
+}
+
+/* Значение колонки сортировки для формирования курсора */
+func (row *guestSubEntityRow) sortValue(sortBy string) string {
+	switch sortBy {
+	case "price":
+		return strconv.FormatFloat(row.Price, 'f', -1, 64)
+	case "area":
+		return strconv.FormatFloat(row.Area, 'f', -1, 64)
+	case "rooms":
+		return strconv.Itoa(row.Rooms)
+	default:
+		return row.CreatedAt.Format(guestCursorTimeLayout)
+	}
+}
+
+/* Получение списка доступных помещений с фильтрацией и сортировкой */
+func (r *GuestPostgres) GetSubEntities(data guestModel.GuestSubEntityQueryModel) (guestModel.GuestSubEntityAnyCountModel, error) {
+	var args []interface{}
+	query := guestSubEntityQuery()
+
+	if data.CompanyUuid != nil && *data.CompanyUuid != "" {
+		args = append(args, *data.CompanyUuid)
+		query += fmt.Sprintf(" AND c.uuid = $%d", len(args))
+	}
+
+	if data.ProjectUuid != nil && *data.ProjectUuid != "" {
+		args = append(args, *data.ProjectUuid)
+		query += fmt.Sprintf(" AND p.uuid = $%d", len(args))
+	}
+
+	// Поиск по городу или адресу объекта
+	if data.Address != nil && *data.Address != "" {
+		args = append(args, "%"+*data.Address+"%")
+		query += fmt.Sprintf(" AND e.data->>'address' ILIKE $%d", len(args))
+	}
+
+	if data.PriceMin != nil {
+		args = append(args, *data.PriceMin)
+		query += fmt.Sprintf(" AND s.price >= $%d", len(args))
+	}
+
+	if data.PriceMax != nil {
+		args = append(args, *data.PriceMax)
+		query += fmt.Sprintf(" AND s.price <= $%d", len(args))
+	}
+
+	if data.RoomsMin != nil {
+		args = append(args, *data.RoomsMin)
+		query += fmt.Sprintf(" AND s.rooms >= $%d", len(args))
+	}
+
+	if data.RoomsMax != nil {
+		args = append(args, *data.RoomsMax)
+		query += fmt.Sprintf(" AND s.rooms <= $%d", len(args))
+	}
+
+	// По-умолчанию помещения сортируются от новых к старым
+	if data.SortBy == "" {
+		data.SortBy = "created_at"
+	}
+
+	if data.SortOrder == "" {
+		data.SortOrder = "desc"
+		if data.SortBy != "created_at" {
+			data.SortOrder = "asc"
+		}
+	}
+
+	sort, ok := guestSubEntitySort[data.SortBy]
+	if !ok {
+		return guestModel.GuestSubEntityAnyCountModel{}, errors.New("Ошибка: недопустимое поле сортировки!")
+	}
+
+	desc := data.SortOrder == "desc"
+	condition, err := guestCursorCondition(data.Cursor, guestCursorSort(data.SortBy, data.SortOrder), sort[0], "s.id", sort[1], desc, &args)
+	if err != nil {
+		return guestModel.GuestSubEntityAnyCountModel{}, err
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	args = append(args, data.Limit+1)
+	query += condition + fmt.Sprintf(" ORDER BY %s %s, s.id %s LIMIT $%d", sort[0], direction, direction, len(args))
+
+	var rows []guestSubEntityRow
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return guestModel.GuestSubEntityAnyCountModel{}, err
+	}
+
+	var result guestModel.GuestSubEntityAnyCountModel
+
+	for i := range rows {
+		if i >= data.Limit {
+			last := rows[i-1]
+			if result.NextCursor, err = encodeGuestCursor(guestCursorSort(data.SortBy, data.SortOrder), last.sortValue(data.SortBy), last.Id); err != nil {
+				return guestModel.GuestSubEntityAnyCountModel{}, err
+			}
+			break
+		}
+
+		item, err := rows[i].toModel()
+		if err != nil {
+			return guestModel.GuestSubEntityAnyCountModel{}, err
+		}
+
+		result.SubEntities = append(result.SubEntities, item)
+	}
+
+	result.Count = len(result.SubEntities)
+	return result, nil
+}
+
+/* Получение публичной информации о доступном помещении */
+func (r *GuestPostgres) GetSubEntity(data guestModel.GuestUuidModel) (guestModel.GuestSubEntityModel, error) {
+	var rows []guestSubEntityRow
+	query := guestSubEntityQuery() + " AND s.uuid = $1"
+
+	if err := r.db.Select(&rows, query, data.Uuid); err != nil {
+		return guestModel.GuestSubEntityModel{}, err
+	}
+
+	if len(rows) <= 0 {
+		return guestModel.GuestSubEntityModel{}, errors.New(fmt.Sprintf("Ошибка: помещения по запросу uuid:%s не найдено!", data.Uuid))
+	}
+
+	return rows[0].toModel()
+}
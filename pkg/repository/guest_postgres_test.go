@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuestCursorIsBoundToSort(t *testing.T) {
+	cursor, err := encodeGuestCursor(guestCursorSort("price", "asc"), "1500.5", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var args []interface{}
+	condition, err := guestCursorCondition(cursor, guestCursorSort("price", "asc"), "s.price", "s.id", "numeric", false, &args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if condition != " AND (s.price, s.id) > ($1::numeric, $2)" || len(args) != 2 {
+		t.Fatalf("unexpected condition %q with args %v", condition, args)
+	}
+
+	// Курсор, выданный для сортировки по цене, не применяется к сортировке по дате
+	args = nil
+	if _, err := guestCursorCondition(cursor, guestCursorSort("created_at", "desc"), "s.created_at", "s.id", "timestamp", true, &args); err == nil {
+		t.Fatal("a cursor issued for another sort must be rejected")
+	}
+
+	if _, err := guestCursorCondition(cursor, guestCursorSort("price", "desc"), "s.price", "s.id", "numeric", true, &args); err == nil {
+		t.Fatal("a cursor issued for another sort order must be rejected")
+	}
+
+	if len(args) != 0 {
+		t.Fatalf("rejected cursors must not add arguments, got %v", args)
+	}
+}
+
+func TestGuestCursorRejectsMalformedValue(t *testing.T) {
+	sort := guestCursorSort("created_at", "desc")
+
+	cursor, err := encodeGuestCursor(sort, "1500.5", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var args []interface{}
+	if _, err := guestCursorCondition(cursor, sort, "s.created_at", "s.id", "timestamp", true, &args); err == nil {
+		t.Fatal("a cursor value that does not match the column type must be rejected")
+	}
+
+	cursor, err = encodeGuestCursor(sort, time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC).Format(guestCursorTimeLayout), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := guestCursorCondition(cursor, sort, "s.created_at", "s.id", "timestamp", true, &args); err != nil {
+		t.Fatal(err)
+	}
+}
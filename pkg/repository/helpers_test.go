@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sqlite "github.com/glebarez/go-sqlite"
+	"github.com/jmoiron/sqlx"
+)
+
+/* Полезные нагрузки, отправленные через pg_notify (по каналам) */
+var testNotifications = struct {
+	sync.Mutex
+	payloads map[string][]string
+}{payloads: make(map[string][]string)}
+
+func init() {
+	// Функции Postgres, используемые в запросах репозитория
+	sqlite.MustRegisterScalarFunction("now", 0, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return time.Now(), nil
+	})
+
+	sqlite.MustRegisterScalarFunction("pg_notify", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		testNotifications.Lock()
+		defer testNotifications.Unlock()
+
+		channel, payload := args[0].(string), args[1].(string)
+		testNotifications.payloads[channel] = append(testNotifications.payloads[channel], payload)
+
+		return nil, nil
+	})
+}
+
+/* Каталог миграций, по которым строится схема тестовой БД */
+const testMigrationsDir = "../migration/sql"
+
+/* Замены конструкций Postgres, отсутствующих в SQLite */
+var testSchemaReplacer = strings.NewReplacer(
+	"SERIAL PRIMARY KEY", "INTEGER PRIMARY KEY",
+	"gen_random_uuid()", "(lower(hex(randomblob(16))))",
+	"NOW()", "CURRENT_TIMESTAMP",
+	"'{}'::jsonb", "'{}'",
+	"JSONB", "TEXT",
+	" UUID ", " TEXT ",
+)
+
+var (
+	testSchemaOnce sync.Once
+	testSchema     string
+	testSchemaErr  error
+
+	testCommentPattern   = regexp.MustCompile(`(?s)/\*.*?\*/|--[^\n]*`)
+	testAlterPattern     = regexp.MustCompile(`(?s)^ALTER TABLE (\w+)\s+(.*)$`)
+	testAddColumnPattern = regexp.MustCompile(`(?s)^ADD COLUMN (?:IF NOT EXISTS )?(.*)$`)
+	testColumnsSeparator = regexp.MustCompile(`,\s*ADD COLUMN `)
+	testCreateTable      = regexp.MustCompile(`^CREATE TABLE (?:IF NOT EXISTS )?(\w+)`)
+
+	// Колонки, имена которых являются ключевыми словами SQLite (в Postgres допустимы без кавычек)
+	testKeywordColumn = regexp.MustCompile(`(?m)^(\s+)(index)(\s)`)
+)
+
+/*
+ * Построение схемы тестовой БД (SQLite в памяти вместо Postgres) из up-миграций.
+ * Применяется только DDL: начальные данные и исправления данных тесты создают сами.
+ * Добавляемые через ALTER TABLE колонки переносятся в CREATE TABLE, так как SQLite
+ * не допускает ADD COLUMN с невычислимым по умолчанию значением (NOW())
+ */
+func buildTestSchema() (string, error) {
+	files, err := filepath.Glob(filepath.Join(testMigrationsDir, "*.up.sql"))
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(files)
+
+	var statements []string
+	tables := make(map[string]int)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		for _, statement := range strings.Split(testCommentPattern.ReplaceAllString(string(content), ""), ";") {
+			statement = testKeywordColumn.ReplaceAllString(testSchemaReplacer.Replace(statement), `$1"$2"$3`)
+			statement = strings.TrimSpace(statement)
+
+			switch {
+			case statement == "",
+				strings.HasPrefix(statement, "CREATE EXTENSION"),
+				strings.HasPrefix(statement, "INSERT"),
+				strings.HasPrefix(statement, "UPDATE"):
+				continue
+
+			case testCreateTable.MatchString(statement):
+				tables[testCreateTable.FindStringSubmatch(statement)[1]] = len(statements)
+				statements = append(statements, statement)
+
+			case strings.HasPrefix(statement, "CREATE INDEX"), strings.HasPrefix(statement, "CREATE UNIQUE INDEX"):
+				statements = append(statements, statement)
+
+			case testAlterPattern.MatchString(statement):
+				match := testAlterPattern.FindStringSubmatch(statement)
+
+				index, ok := tables[match[1]]
+				if !ok {
+					return "", fmt.Errorf("%s: таблица %s не создана миграциями", file, match[1])
+				}
+
+				for _, column := range testColumnsSeparator.Split(match[2], -1) {
+					if !strings.HasPrefix(column, "ADD COLUMN ") {
+						column = "ADD COLUMN " + column
+					}
+
+					definition := testAddColumnPattern.FindStringSubmatch(column)
+					if definition == nil {
+						return "", fmt.Errorf("%s: неподдерживаемое изменение таблицы: %s", file, column)
+					}
+
+					open := strings.Index(statements[index], "(") + 1
+					statements[index] = statements[index][:open] + "\n    " + definition[1] + "," + statements[index][open:]
+				}
+
+			default:
+				return "", fmt.Errorf("%s: неподдерживаемая инструкция: %s", file, statement)
+			}
+		}
+	}
+
+	return strings.Join(statements, ";\n") + ";", nil
+}
+
+func newTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Каждое соединение с :memory: открывает отдельную базу
+	db.SetMaxOpenConns(1)
+
+	testSchemaOnce.Do(func() { testSchema, testSchemaErr = buildTestSchema() })
+	if testSchemaErr != nil {
+		t.Fatal(testSchemaErr)
+	}
+
+	if _, err := db.Exec(testSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+/* Enforcer с моделью приложения (без адаптера правила хранятся только в памяти) */
+func newTestEnforcer(t *testing.T, params ...interface{}) *Enforcer {
+	t.Helper()
+
+	enforcer, err := NewEnforcer(append([]interface{}{"../../config/model.conf"}, params...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enforcer.AddFunction(OBJECT_MATCH_FUNC, func(args ...interface{}) (interface{}, error) {
+		return args[0] == args[1], nil
+	})
+
+	return enforcer
+}
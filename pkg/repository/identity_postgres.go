@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	authService "main-server/pkg/service/auth"
+	passwordService "main-server/pkg/service/password"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/oauth2"
+)
+
+/* Вход через провайдера, не привязанного к существующей учётной записи с тем же email-адресом */
+var ErrIdentityNotLinked = errors.New("Пользователь с данным email-адресом уже существует! Войдите в аккаунт и привяжите учётную запись провайдера в профиле")
+
+type IdentityPostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры IdentityPostgres */
+func NewIdentityPostgres(db *sqlx.DB) *IdentityPostgres {
+	return &IdentityPostgres{db: db}
+}
+
+/* Обмен кода авторизации на токен провайдера и получение данных пользователя */
+func exchangeOAuth2(providerName, code string, params *authService.AuthParams) (*oauth2.Token, userModel.UserRegisterOAuth2Model, error) {
+	provider, err := authService.GetProvider(providerName)
+	if err != nil {
+		return nil, userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	token, err := provider.Exchange(oauth2.NoContext, code, params)
+	if err != nil {
+		return nil, userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	isVerify, err := provider.VerifyAccessToken(token.AccessToken)
+	if err != nil {
+		return nil, userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	if !isVerify {
+		return nil, userModel.UserRegisterOAuth2Model{}, errors.New("Данный токен не принадлежит данному пользователю!")
+	}
+
+	userData, err := provider.GetUserInfo(token)
+	if err != nil {
+		return nil, userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	return token, userData, nil
+}
+
+/* Получение типа аутентификации по значению */
+func getAuthType(db *sqlx.DB, value string) (userModel.AuthTypeModel, error) {
+	var authType userModel.AuthTypeModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstant.U_AUTH_TYPES)
+	if err := db.Get(&authType, query, strings.ToLower(value)); err != nil {
+		return userModel.AuthTypeModel{}, errors.New("Тип аутентификации не найден!")
+	}
+
+	return authType, nil
+}
+
+/* Проверка наличия у пользователя локального способа входа (пароля) */
+func hasLocalIdentity(db sqlx.Queryer, usersId int) (bool, error) {
+	var exists bool
+
+	query := fmt.Sprintf(`SELECT EXISTS (
+		SELECT 1 FROM %s tl INNER JOIN %s tat ON tat.id = tl.auth_types_id
+		WHERE tl.users_id = $1 AND tat.value = $2
+	)`, tableConstant.U_USERS_AUTH_TYPES, tableConstant.U_AUTH_TYPES)
+
+	err := sqlx.Get(db, &exists, query, usersId, authConstant.AUTH_TYPE_LOCAL)
+
+	return exists, err
+}
+
+/* Подтверждение действия паролем (для аккаунтов без локального входа пароль не требуется) */
+func checkAccountPassword(db *sqlx.DB, user userModel.UserModel, password string) error {
+	hasPassword, err := hasLocalIdentity(db, user.Id)
+	if err != nil {
+		return err
+	}
+
+	if hasPassword {
+		if ok, _, _ := passwordService.Verify(user.Password, password); !ok {
+			return errors.New("Неверный пароль! Повторите попытку")
+		}
+	}
+
+	return nil
+}
+
+/*
+* Поиск пользователя, к которому привязана учётная запись провайдера (0 - не найден).
+* Привязки, созданные до появления идентификаторов провайдера, сопоставляются по email-адресу
+* и дополняются идентификатором при первом входе
+ */
+func findIdentityUser(db *sqlx.DB, authTypesId int, data userModel.UserRegisterOAuth2Model) (int, error) {
+	var usersId []int
+
+	query := fmt.Sprintf("SELECT users_id FROM %s WHERE auth_types_id=$1 AND subject=$2 LIMIT 1", tableConstant.U_USERS_AUTH_TYPES)
+	if err := db.Select(&usersId, query, authTypesId, data.Subject); err != nil {
+		return 0, err
+	}
+
+	if len(usersId) > 0 {
+		return usersId[0], nil
+	}
+
+	query = fmt.Sprintf(`UPDATE %s tl SET subject=$1, email=$2 FROM %s tu
+		WHERE tu.id = tl.users_id AND tl.auth_types_id = $3 AND tl.subject IS NULL AND LOWER(tu.email) = LOWER($2)
+		RETURNING tl.users_id`,
+		tableConstant.U_USERS_AUTH_TYPES, tableConstant.U_USERS,
+	)
+
+	if err := db.Select(&usersId, query, data.Subject, data.Email, authTypesId); err != nil {
+		return 0, err
+	}
+
+	if len(usersId) > 0 {
+		return usersId[0], nil
+	}
+
+	return 0, nil
+}
+
+/* Получение списка привязанных к пользователю способов входа */
+func (r *IdentityPostgres) GetIdentities(usersId int) ([]userModel.IdentityModel, error) {
+	var identities []userModel.IdentityModel
+
+	query := fmt.Sprintf(`SELECT tat.value, tl.email, tl.created_at FROM %s tl
+		INNER JOIN %s tat ON tat.id = tl.auth_types_id
+		WHERE tl.users_id = $1 ORDER BY tl.created_at, tl.id`,
+		tableConstant.U_USERS_AUTH_TYPES, tableConstant.U_AUTH_TYPES,
+	)
+
+	if err := r.db.Select(&identities, query, usersId); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+/* Адрес страницы авторизации провайдера для привязки его учётной записи к пользователю usersId */
+func (r *IdentityPostgres) GetLinkURL(usersId int, providerName string) (string, error) {
+	return createAuthState(r.db, providerName, usersId)
+}
+
+/*
+* Привязка учётной записи провайдера к пользователю.
+* Код авторизации принимается только вместе с state, выданным этому же пользователю
+ */
+func (r *IdentityPostgres) LinkIdentity(usersId int, providerName, code, state string) (bool, error) {
+	params, err := consumeAuthState(r.db, providerName, state, usersId)
+	if err != nil {
+		return false, err
+	}
+
+	_, data, err := exchangeOAuth2(providerName, code, params)
+	if err != nil {
+		return false, err
+	}
+
+	authType, err := getAuthType(r.db, providerName)
+	if err != nil {
+		return false, err
+	}
+
+	var linked []userModel.UserAuthTypeModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE auth_types_id=$1 AND subject=$2 LIMIT 1", tableConstant.U_USERS_AUTH_TYPES)
+	if err := r.db.Select(&linked, query, authType.Id, data.Subject); err != nil {
+		return false, err
+	}
+
+	if len(linked) > 0 {
+		if linked[0].UsersId != usersId {
+			return false, errors.New("Данная учётная запись провайдера уже привязана к другому пользователю!")
+		}
+
+		return true, nil
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s WHERE users_id=$1 AND auth_types_id=$2 LIMIT 1", tableConstant.U_USERS_AUTH_TYPES)
+	if err := r.db.Select(&linked, query, usersId, authType.Id); err != nil {
+		return false, err
+	}
+
+	// Привязка без идентификатора (созданная до его появления) дополняется данными провайдера
+	if len(linked) > 0 {
+		if linked[0].Subject != nil {
+			return false, errors.New("К аккаунту уже привязана другая учётная запись данного провайдера! Сначала отвяжите её")
+		}
+
+		query = fmt.Sprintf("UPDATE %s SET subject=$1, email=$2 WHERE id=$3", tableConstant.U_USERS_AUTH_TYPES)
+		if _, err := r.db.Exec(query, data.Subject, data.Email, linked[0].Id); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (users_id, auth_types_id, subject, email, created_at) values ($1, $2, $3, $4, $5)",
+		tableConstant.U_USERS_AUTH_TYPES,
+	)
+
+	if _, err := r.db.Exec(query, usersId, authType.Id, data.Subject, data.Email, time.Now()); err != nil {
+		return false, errors.New("Не удалось привязать учётную запись провайдера!")
+	}
+
+	return true, nil
+}
+
+/*
+* Отвязка способа входа. Единственный способ входа отвязать нельзя,
+* при отвязке локального входа пароль и токены сброса пароля удаляются
+ */
+func (r *IdentityPostgres) UnlinkIdentity(usersId int, authTypeValue string) (bool, error) {
+	authType, err := getAuthType(r.db, authTypeValue)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	// Блокировка строки пользователя исключает одновременную отвязку двух последних способов входа
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=$1 FOR UPDATE", tableConstant.U_USERS)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	var authTypesId []int
+
+	query = fmt.Sprintf("SELECT auth_types_id FROM %s WHERE users_id=$1", tableConstant.U_USERS_AUTH_TYPES)
+	if err := tx.Select(&authTypesId, query, usersId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	found := false
+	for _, id := range authTypesId {
+		if id == authType.Id {
+			found = true
+		}
+	}
+
+	if !found {
+		tx.Rollback()
+		return false, errors.New("Данный способ входа не привязан к аккаунту!")
+	}
+
+	if len(authTypesId) <= 1 {
+		tx.Rollback()
+		return false, errors.New("Нельзя отвязать единственный способ входа в аккаунт!")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id=$1 AND auth_types_id=$2", tableConstant.U_USERS_AUTH_TYPES)
+	if _, err := tx.Exec(query, usersId, authType.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if authType.Value == authConstant.AUTH_TYPE_LOCAL {
+		query = fmt.Sprintf("UPDATE %s SET password='' WHERE id=$1", tableConstant.U_USERS)
+		if _, err := tx.Exec(query, usersId); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+
+		query = fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.U_RESET_TOKENS)
+		if _, err := tx.Exec(query, usersId); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Установка локального пароля для учётной записи, созданной через провайдера */
+func (r *IdentityPostgres) SetPassword(usersId int, password string) (bool, error) {
+	authType, err := getAuthType(r.db, authConstant.AUTH_TYPE_LOCAL)
+	if err != nil {
+		return false, err
+	}
+
+	var user userModel.UserModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id=$1 LIMIT 1", tableConstant.U_USERS)
+	if err := r.db.Get(&user, query, usersId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, errors.New("Пользователь не найден!")
+		}
+
+		return false, err
+	}
+
+	hashedPassword, err := passwordService.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	// Повторная установка пароля недоступна - для смены пароля используется обновление профиля
+	query = fmt.Sprintf(
+		`INSERT INTO %s (users_id, auth_types_id, email, created_at) values ($1, $2, $3, $4)
+		ON CONFLICT (users_id, auth_types_id) DO NOTHING`,
+		tableConstant.U_USERS_AUTH_TYPES,
+	)
+
+	result, err := tx.Exec(query, usersId, authType.Id, user.Email, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if count, err := result.RowsAffected(); err != nil || count <= 0 {
+		tx.Rollback()
+		return false, errors.New("Пароль для аккаунта уже установлен!")
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", tableConstant.U_USERS)
+	if _, err := tx.Exec(query, hashedPassword, usersId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
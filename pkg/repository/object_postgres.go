@@ -12,15 +12,18 @@ import (
 type ObjectPostgres struct {
 	db           *sqlx.DB
 	acTypeObject *TypeObjectPostgres
+	tree         *ObjectTree
 }
 
 func NewObjectPostgres(
 	db *sqlx.DB,
 	acTypeObject *TypeObjectPostgres,
+	tree *ObjectTree,
 ) *ObjectPostgres {
 	return &ObjectPostgres{
 		db:           db,
 		acTypeObject: acTypeObject,
+		tree:         tree,
 	}
 }
 
@@ -64,7 +67,7 @@ func (r *ObjectPostgres) AddResource(resource *rbacModel.ResourceModel) (*rbacMo
 
 	if resource.ParentUuid != nil {
 		// Получение родительского объекта
-		parentObject, err = r.Get("value", resource.ParentUuid, true)
+		parentObject, err = r.Get("value", *resource.ParentUuid, true)
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +104,9 @@ func (r *ObjectPostgres) AddResource(resource *rbacModel.ResourceModel) (*rbacMo
 		return nil, err
 	}
 
+	// Ресурс мог быть закэширован как отсутствующий до его добавления
+	r.tree.Invalidate(resource.Resource.ResourceUuid)
+
 	var object rbacModel.ObjectDbModel
 	object.Id = objectId
 	object.Value = resource.Resource.ResourceUuid
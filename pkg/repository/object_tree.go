@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	tableConstant "main-server/pkg/constant/table"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Имя функции сопоставления объектов в матчере casbin (config/model.conf)
+	OBJECT_MATCH_FUNC = "objectMatch"
+
+	// Время хранения в кэше отсутствующего объекта (объект мог быть создан другим экземпляром сервера)
+	objectTreeMissTTL = time.Minute
+
+	// Максимальное количество записей в кэше (при превышении кэш очищается)
+	objectTreeMaxEntries = 100000
+
+	// Максимальная глубина дерева информационных ресурсов
+	objectTreeMaxDepth = 32
+)
+
+/* Цепочка родительских ресурсов объекта (от непосредственного родителя к корню) */
+type objectAncestry struct {
+	chain     []string
+	ancestors map[string]struct{}
+	expiresAt time.Time
+}
+
+/*
+* Кэш дерева информационных ресурсов (ac_objects.parent_id). Используется матчером casbin:
+* право на ресурс распространяется на все его дочерние ресурсы (компания -> проект -> объект -> помещение)
+ */
+type ObjectTree struct {
+	db      *sqlx.DB
+	mu      sync.RWMutex
+	entries map[string]objectAncestry
+
+	// Рассылка сброса кэша другим экземплярам сервера
+	publish func(values ...string) error
+}
+
+func NewObjectTree(db *sqlx.DB) *ObjectTree {
+	return &ObjectTree{
+		db:      db,
+		entries: make(map[string]objectAncestry),
+	}
+}
+
+/* Загрузка цепочки родительских ресурсов объекта из БД */
+func (t *ObjectTree) load(value string) (objectAncestry, error) {
+	var ancestors []string
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE tree AS (
+			SELECT id, value, parent_id, 0 AS depth FROM %s WHERE value = $1
+			UNION ALL
+			SELECT o.id, o.value, o.parent_id, tree.depth + 1 FROM %s o
+			INNER JOIN tree ON o.id = tree.parent_id
+			WHERE tree.depth < $2
+		)
+		SELECT value FROM tree ORDER BY depth`,
+		tableConstant.AC_OBJECTS, tableConstant.AC_OBJECTS,
+	)
+
+	if err := t.db.Select(&ancestors, query, value, objectTreeMaxDepth); err != nil {
+		return objectAncestry{}, err
+	}
+
+	entry := objectAncestry{ancestors: make(map[string]struct{}, len(ancestors))}
+
+	// Родитель ресурса не изменяется, поэтому найденная цепочка хранится бессрочно
+	if len(ancestors) <= 0 {
+		entry.expiresAt = time.Now().Add(objectTreeMissTTL)
+	}
+
+	for _, item := range ancestors {
+		if item != value {
+			entry.chain = append(entry.chain, item)
+			entry.ancestors[item] = struct{}{}
+		}
+	}
+
+	return entry, nil
+}
+
+/* Получение цепочки родительских ресурсов объекта (из кэша или из БД) */
+func (t *ObjectTree) get(value string) (objectAncestry, error) {
+	t.mu.RLock()
+	entry, ok := t.entries[value]
+	t.mu.RUnlock()
+
+	if ok && (entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt)) {
+		return entry, nil
+	}
+
+	entry, err := t.load(value)
+	if err != nil {
+		return objectAncestry{}, err
+	}
+
+	t.mu.Lock()
+	if len(t.entries) >= objectTreeMaxEntries {
+		t.entries = make(map[string]objectAncestry)
+	}
+	t.entries[value] = entry
+	t.mu.Unlock()
+
+	return entry, nil
+}
+
+/* Сброс кэша для объектов (после добавления ресурса в дерево) на всех экземплярах сервера */
+func (t *ObjectTree) Invalidate(values ...string) {
+	t.invalidate(values...)
+
+	t.mu.RLock()
+	publish := t.publish
+	t.mu.RUnlock()
+
+	if publish == nil {
+		return
+	}
+
+	// Без рассылки другие экземпляры увидят ресурс после истечения objectTreeMissTTL или полной перезагрузки правил
+	if err := publish(values...); err != nil {
+		logrus.Errorf("object tree: error publishing invalidation: %s", err.Error())
+	}
+}
+
+/* Сброс кэша для объектов на текущем экземпляре сервера */
+func (t *ObjectTree) invalidate(values ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, value := range values {
+		delete(t.entries, value)
+	}
+}
+
+/* Полная очистка кэша (при полной перезагрузке правил сообщения о сбросе могли быть потеряны) */
+func (t *ObjectTree) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = make(map[string]objectAncestry)
+}
+
+func (t *ObjectTree) setPublisher(publish func(values ...string) error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.publish = publish
+}
+
+/* Родительские ресурсы объекта (от непосредственного родителя к корню) */
+func (t *ObjectTree) Ancestors(value string) ([]string, error) {
+	entry, err := t.get(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, entry.chain...), nil
+}
+
+/* Проверка того, что ресурс policyObject совпадает с ресурсом requestObject или является его предком */
+func (t *ObjectTree) Match(requestObject, policyObject string) (bool, error) {
+	if requestObject == policyObject {
+		return true, nil
+	}
+
+	entry, err := t.get(requestObject)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := entry.ancestors[policyObject]
+	return ok, nil
+}
+
+/* Функция objectMatch(r.obj, p.obj) для матчера casbin */
+func (t *ObjectTree) MatchFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return false, errors.New("objectMatch: expected 2 arguments")
+	}
+
+	requestObject, ok := args[0].(string)
+	if !ok {
+		return false, errors.New("objectMatch: request object must be a string")
+	}
+
+	policyObject, ok := args[1].(string)
+	if !ok {
+		return false, errors.New("objectMatch: policy object must be a string")
+	}
+
+	return t.Match(requestObject, policyObject)
+}
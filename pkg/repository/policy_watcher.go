@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"encoding/json"
+	"main-server/config"
+	"sync"
+	"time"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+/* Операции над правилами, передаваемые между экземплярами сервера */
+const (
+	policyOpAdd            = "add"
+	policyOpRemove         = "remove"
+	policyOpRemoveFiltered = "remove_filtered"
+	policyOpReload         = "reload"
+
+	// Сброс кэша дерева информационных ресурсов (Rules[0] - значения объектов)
+	policyOpTreeInvalidate = "invalidate_objects"
+)
+
+const (
+	// Максимальный размер полезной нагрузки NOTIFY (ограничение Postgres - 8000 байт)
+	policyPayloadMaxSize = 7900
+
+	// Интервал проверки соединения слушателя
+	policyListenerPing = 90 * time.Second
+)
+
+/* Сообщение об изменении правил доступа */
+type policyMessage struct {
+	Instance   string     `json:"instance"`              // Экземпляр сервера, изменивший правила
+	Seq        uint64     `json:"seq"`                   // Порядковый номер сообщения экземпляра
+	Op         string     `json:"op"`                    // Операция
+	Sec        string     `json:"sec,omitempty"`         // Секция модели (p, g)
+	Ptype      string     `json:"ptype,omitempty"`       // Тип правила
+	FieldIndex int        `json:"field_index,omitempty"` // Индекс поля фильтра (remove_filtered)
+	Rules      [][]string `json:"rules,omitempty"`       // Правила, значения полей фильтра или объектов
+}
+
+/*
+* Наблюдатель casbin на основе Postgres LISTEN/NOTIFY: рассылает изменения правил другим экземплярам
+* сервера и применяет полученные изменения к локальному enforcer. При пропуске сообщения
+* (разрыв соединения, пропуск порядкового номера) правила перезагружаются полностью
+ */
+type PolicyWatcher struct {
+	db       *sqlx.DB
+	enforcer *Enforcer
+	tree     *ObjectTree
+	listener *pq.Listener
+	channel  string
+	instance string
+
+	mu       sync.Mutex
+	seq      uint64
+	callback func(string)
+
+	received map[string]uint64
+	done     chan struct{}
+	once     sync.Once
+}
+
+/* Функция создания наблюдателя и подписки на канал изменений правил */
+func NewPolicyWatcher(cfg Config, db *sqlx.DB, enforcer *Enforcer) (*PolicyWatcher, error) {
+	watcher := newPolicyWatcher(db, enforcer, config.AppPolicyWatcherConfig.Channel)
+
+	watcher.listener = pq.NewListener(
+		cfg.ConnString(),
+		config.AppPolicyWatcherConfig.MinReconnect,
+		config.AppPolicyWatcherConfig.MaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				logrus.Errorf("policy watcher listener: %s", err.Error())
+			}
+		},
+	)
+
+	if err := watcher.listener.Listen(watcher.channel); err != nil {
+		watcher.listener.Close()
+		return nil, err
+	}
+
+	go watcher.run()
+
+	return watcher, nil
+}
+
+func newPolicyWatcher(db *sqlx.DB, enforcer *Enforcer, channel string) *PolicyWatcher {
+	return &PolicyWatcher{
+		db:       db,
+		enforcer: enforcer,
+		channel:  channel,
+		instance: uuid.NewV4().String(),
+		received: make(map[string]uint64),
+		done:     make(chan struct{}),
+	}
+}
+
+/*
+* Синхронизация кэша дерева информационных ресурсов между экземплярами сервера: добавление ресурса
+* рассылается через канал наблюдателя, при полной перезагрузке правил кэш очищается
+ */
+func (w *PolicyWatcher) WatchObjectTree(tree *ObjectTree) {
+	w.mu.Lock()
+	w.tree = tree
+	w.mu.Unlock()
+
+	tree.setPublisher(func(values ...string) error {
+		return w.publish(policyMessage{Op: policyOpTreeInvalidate, Rules: [][]string{values}})
+	})
+}
+
+func (w *PolicyWatcher) objectTree() *ObjectTree {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.tree
+}
+
+/* Обработка уведомлений и плановая полная перезагрузка правил */
+func (w *PolicyWatcher) run() {
+	reload := time.NewTicker(config.AppPolicyWatcherConfig.ReloadInterval)
+	defer reload.Stop()
+
+	// Таймер не пересоздаётся на каждой итерации, поэтому срабатывает и при постоянном потоке уведомлений
+	ping := time.NewTicker(policyListenerPing)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case notification, ok := <-w.listener.Notify:
+			if !ok {
+				return
+			}
+
+			// После переподключения уведомления могли быть потеряны
+			if notification == nil {
+				w.reload("listener reconnected")
+				continue
+			}
+
+			w.handle(notification.Extra)
+
+		case <-reload.C:
+			w.reload("")
+
+		case <-ping.C:
+			go w.listener.Ping()
+		}
+	}
+}
+
+/* Применение полученного сообщения к локальному enforcer */
+func (w *PolicyWatcher) handle(payload string) {
+	var message policyMessage
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		w.reload("invalid message")
+		return
+	}
+
+	if message.Instance == w.instance {
+		return
+	}
+
+	// Пропуск порядкового номера означает потерянное сообщение
+	last, seen := w.received[message.Instance]
+	w.received[message.Instance] = message.Seq
+
+	if seen && message.Seq != last+1 {
+		w.reload("missed message")
+		return
+	}
+
+	if message.Op == policyOpTreeInvalidate {
+		if tree := w.objectTree(); tree != nil && len(message.Rules) == 1 {
+			tree.invalidate(message.Rules[0]...)
+		}
+
+		return
+	}
+
+	if err := w.enforcer.withLock(func() error { return w.apply(message) }); err != nil {
+		w.reload(err.Error())
+		return
+	}
+
+	w.notify(payload)
+}
+
+/*
+* Изменение правил в модели без сохранения в БД и без повторной рассылки.
+* Вызывается под монопольной блокировкой enforcer
+ */
+func (w *PolicyWatcher) apply(message policyMessage) error {
+	perm := w.enforcer.GetModel()
+
+	var op model.PolicyOp
+	var rules [][]string
+
+	switch message.Op {
+	case policyOpAdd:
+		op = model.PolicyAdd
+		rules = perm.AddPoliciesWithAffected(message.Sec, message.Ptype, message.Rules)
+	case policyOpRemove:
+		op = model.PolicyRemove
+		rules = perm.RemovePoliciesWithEffected(message.Sec, message.Ptype, message.Rules)
+	case policyOpRemoveFiltered:
+		if len(message.Rules) != 1 {
+			return w.enforcer.Enforcer.LoadPolicy()
+		}
+
+		op = model.PolicyRemove
+		_, rules = perm.RemoveFilteredPolicy(message.Sec, message.Ptype, message.FieldIndex, message.Rules[0]...)
+	default:
+		return w.enforcer.Enforcer.LoadPolicy()
+	}
+
+	if message.Sec == "g" && len(rules) > 0 {
+		return w.enforcer.Enforcer.BuildIncrementalRoleLinks(op, message.Ptype, rules)
+	}
+
+	return nil
+}
+
+/* Полная перезагрузка правил из БД */
+func (w *PolicyWatcher) reload(reason string) {
+	if reason != "" {
+		logrus.Warnf("policy watcher: full reload (%s)", reason)
+	}
+
+	if tree := w.objectTree(); tree != nil {
+		tree.reset()
+	}
+
+	if err := w.enforcer.LoadPolicy(); err != nil {
+		logrus.Errorf("policy watcher: error reloading policy: %s", err.Error())
+		return
+	}
+
+	w.notify(policyOpReload)
+}
+
+func (w *PolicyWatcher) notify(value string) {
+	w.mu.Lock()
+	callback := w.callback
+	w.mu.Unlock()
+
+	if callback != nil {
+		callback(value)
+	}
+}
+
+/* Рассылка сообщения другим экземплярам сервера */
+func (w *PolicyWatcher) publish(message policyMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.seq++
+	message.Instance = w.instance
+	message.Seq = w.seq
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// Крупные изменения не помещаются в уведомление - получатели перезагружают правила полностью
+	if len(payload) > policyPayloadMaxSize {
+		payload, err = json.Marshal(policyMessage{
+			Instance: message.Instance,
+			Seq:      message.Seq,
+			Op:       policyOpReload,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = w.db.Exec(`SELECT pg_notify($1, $2)`, w.channel, string(payload))
+	return err
+}
+
+func (w *PolicyWatcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.callback = callback
+	return nil
+}
+
+func (w *PolicyWatcher) Update() error {
+	return w.publish(policyMessage{Op: policyOpReload})
+}
+
+func (w *PolicyWatcher) UpdateForAddPolicy(sec, ptype string, params ...string) error {
+	return w.publish(policyMessage{Op: policyOpAdd, Sec: sec, Ptype: ptype, Rules: [][]string{params}})
+}
+
+func (w *PolicyWatcher) UpdateForRemovePolicy(sec, ptype string, params ...string) error {
+	return w.publish(policyMessage{Op: policyOpRemove, Sec: sec, Ptype: ptype, Rules: [][]string{params}})
+}
+
+func (w *PolicyWatcher) UpdateForRemoveFilteredPolicy(sec, ptype string, fieldIndex int, fieldValues ...string) error {
+	return w.publish(policyMessage{
+		Op:         policyOpRemoveFiltered,
+		Sec:        sec,
+		Ptype:      ptype,
+		FieldIndex: fieldIndex,
+		Rules:      [][]string{fieldValues},
+	})
+}
+
+func (w *PolicyWatcher) UpdateForSavePolicy(_ model.Model) error {
+	return w.Update()
+}
+
+func (w *PolicyWatcher) UpdateForAddPolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(policyMessage{Op: policyOpAdd, Sec: sec, Ptype: ptype, Rules: rules})
+}
+
+func (w *PolicyWatcher) UpdateForRemovePolicies(sec string, ptype string, rules ...[]string) error {
+	return w.publish(policyMessage{Op: policyOpRemove, Sec: sec, Ptype: ptype, Rules: rules})
+}
+
+/* Остановка наблюдателя */
+func (w *PolicyWatcher) Close() {
+	w.once.Do(func() {
+		close(w.done)
+		w.listener.Close()
+	})
+}
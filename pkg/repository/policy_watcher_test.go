@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicyWatcherReloadsOnSeqGap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.csv")
+	if err := os.WriteFile(path, []byte("p, 1, 1, stored, read, allow\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	enforcer := newTestEnforcer(t, path)
+	watcher := newPolicyWatcher(nil, enforcer, "test_seq_gap")
+
+	// Правило пропадает из памяти, но остаётся в хранилище - его вернёт только полная перезагрузка
+	stored := []string{"1", "1", "stored", "read", "allow"}
+	enforcer.GetModel().RemovePolicy("p", "p", stored)
+
+	publish := func(seq uint64, object string) {
+		payload, err := json.Marshal(policyMessage{
+			Instance: "other",
+			Seq:      seq,
+			Op:       policyOpAdd,
+			Sec:      "p",
+			Ptype:    "p",
+			Rules:    [][]string{{"1", "1", object, "read", "allow"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		watcher.handle(string(payload))
+	}
+
+	publish(1, "first")
+	publish(2, "second")
+
+	if !enforcer.HasPolicy("1", "1", "second", "read", "allow") || enforcer.HasPolicy(stored) {
+		t.Fatal("consecutive messages must be applied incrementally")
+	}
+
+	// Сообщение 3 потеряно
+	publish(4, "fourth")
+
+	if !enforcer.HasPolicy(stored) {
+		t.Fatal("expected a full reload after a sequence gap")
+	}
+
+	if enforcer.HasPolicy("1", "1", "second", "read", "allow") || enforcer.HasPolicy("1", "1", "fourth", "read", "allow") {
+		t.Fatal("reload must replace the policy with the stored one")
+	}
+}
+
+func TestPolicyWatcherSendsReloadForOversizedPayload(t *testing.T) {
+	const channel = "test_oversized"
+
+	watcher := newPolicyWatcher(newTestDB(t), newTestEnforcer(t), channel)
+
+	small := [][]string{{"1", "1", "object", "read", "allow"}}
+	if err := watcher.UpdateForAddPolicies("p", "p", small...); err != nil {
+		t.Fatal(err)
+	}
+
+	large := make([][]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		large = append(large, []string{"1", "1", strings.Repeat("o", 64), "read", "allow"})
+	}
+
+	if err := watcher.UpdateForAddPolicies("p", "p", large...); err != nil {
+		t.Fatal(err)
+	}
+
+	testNotifications.Lock()
+	payloads := testNotifications.payloads[channel]
+	testNotifications.Unlock()
+
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(payloads))
+	}
+
+	var first, second policyMessage
+	if err := json.Unmarshal([]byte(payloads[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal([]byte(payloads[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Op != policyOpAdd || len(first.Rules) != 1 {
+		t.Fatalf("small change must be sent incrementally, got %+v", first)
+	}
+
+	if len(payloads[1]) > policyPayloadMaxSize || second.Op != policyOpReload || len(second.Rules) != 0 {
+		t.Fatalf("oversized change must be sent as a reload, got %s", payloads[1])
+	}
+
+	// Порядковый номер сохраняется, чтобы получатели не считали сообщение потерянным
+	if second.Seq != first.Seq+1 {
+		t.Fatalf("expected seq %d, got %d", first.Seq+1, second.Seq)
+	}
+}
+
+func TestPolicyWatcherInvalidatesObjectTree(t *testing.T) {
+	const channel = "test_object_tree"
+
+	path := filepath.Join(t.TempDir(), "policy.csv")
+	if err := os.WriteFile(path, []byte(""), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	db := newTestDB(t)
+	watcher := newPolicyWatcher(db, newTestEnforcer(t, path), channel)
+
+	tree := NewObjectTree(db)
+	watcher.WatchObjectTree(tree)
+
+	cached := func(value string) bool {
+		tree.mu.RLock()
+		defer tree.mu.RUnlock()
+
+		_, ok := tree.entries[value]
+		return ok
+	}
+
+	// Ресурсы закэшированы как отсутствующие до их добавления другим экземпляром сервера
+	tree.entries["added"] = objectAncestry{}
+	tree.entries["other"] = objectAncestry{}
+
+	// Добавление ресурса на текущем экземпляре рассылается остальным
+	tree.Invalidate("local")
+
+	testNotifications.Lock()
+	payloads := testNotifications.payloads[channel]
+	testNotifications.Unlock()
+
+	var sent policyMessage
+	if len(payloads) != 1 || json.Unmarshal([]byte(payloads[0]), &sent) != nil ||
+		sent.Op != policyOpTreeInvalidate || len(sent.Rules) != 1 || sent.Rules[0][0] != "local" {
+		t.Fatalf("expected a tree invalidation message, got %v", payloads)
+	}
+
+	payload, err := json.Marshal(policyMessage{
+		Instance: "other",
+		Seq:      1,
+		Op:       policyOpTreeInvalidate,
+		Rules:    [][]string{{"added"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watcher.handle(string(payload))
+
+	if cached("added") || !cached("other") {
+		t.Fatal("expected only the received objects to be invalidated")
+	}
+
+	// При полной перезагрузке сообщения о сбросе могли быть потеряны - кэш очищается полностью
+	watcher.reload("")
+
+	if cached("other") {
+		t.Fatal("expected the object tree to be reset on a full reload")
+	}
+}
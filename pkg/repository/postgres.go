@@ -17,11 +17,16 @@ type Config struct {
 	SSLMode  string
 }
 
+/* Строка подключения к базе данных */
+func (cfg Config) ConnString() string {
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.Username, cfg.DBName, cfg.Password, cfg.SSLMode)
+}
+
 /* Создание нового подключения к базе данных */
 func NewPostgresDB(cfg Config) (*sqlx.DB, error) {
 	// Открытие подключения к базе данных
-	db, err := sqlx.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.Username, cfg.DBName, cfg.Password, cfg.SSLMode))
+	db, err := sqlx.Open("postgres", cfg.ConnString())
 
 	if err != nil {
 		return nil, err
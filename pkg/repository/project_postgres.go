@@ -19,14 +19,13 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
 )
 
 type ProjectPostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *Enforcer
 	role     *RolePostgres
 	user     *UserPostgres
 	object   *ObjectPostgres
@@ -36,7 +35,7 @@ type ProjectPostgres struct {
 /* Функция создания нового экземпляра структуры ProjectPostgres */
 func NewProjectPostgres(
 	db *sqlx.DB,
-	enforcer *casbin.Enforcer,
+	enforcer *Enforcer,
 	role *RolePostgres,
 	user *UserPostgres,
 	object *ObjectPostgres,
@@ -154,7 +153,7 @@ func (r *ProjectPostgres) CreateProject(userId, domainId int, data projectModel.
 	resource.Resource.ResourceUuid = projectUuid.String()
 	resource.Resource.Description = fmt.Sprintf("Проект компании %s", company.Data.Title)
 
-	role, err := r.role.Get("value", roleConstant.ROLE_BUILDER_MANAGER, true)
+	role, err := r.role.GetInDomain(roleConstant.ROLE_BUILDER_MANAGER, domainId, true)
 	if err != nil {
 		tx.Rollback()
 		return projectModel.ProjectCreateModel{}, err
@@ -173,9 +172,9 @@ func (r *ProjectPostgres) CreateProject(userId, domainId int, data projectModel.
 
 	// Добавление пользователю прав для данного проекта
 	_, err = r.enforcer.AddPolicies([][]string{
-		{strconv.Itoa(manager.Id), strconv.Itoa(domainId), projectUuid.String(), actionConstant.DELETE},
-		{strconv.Itoa(manager.Id), strconv.Itoa(domainId), projectUuid.String(), actionConstant.MODIFY},
-		{strconv.Itoa(manager.Id), strconv.Itoa(domainId), projectUuid.String(), actionConstant.READ},
+		{strconv.Itoa(manager.Id), strconv.Itoa(domainId), projectUuid.String(), actionConstant.DELETE, actionConstant.ALLOW},
+		{strconv.Itoa(manager.Id), strconv.Itoa(domainId), projectUuid.String(), actionConstant.MODIFY, actionConstant.ALLOW},
+		{strconv.Itoa(manager.Id), strconv.Itoa(domainId), projectUuid.String(), actionConstant.READ, actionConstant.ALLOW},
 	})
 	if err != nil {
 		tx.Rollback()
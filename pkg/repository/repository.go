@@ -2,44 +2,131 @@ package repository
 
 import (
 	adminModel "main-server/pkg/model/admin"
+	articleModel "main-server/pkg/model/article"
 	companyModel "main-server/pkg/model/company"
 	emailModel "main-server/pkg/model/email"
+	entityModel "main-server/pkg/model/entity"
 	excelModel "main-server/pkg/model/excel"
+	guestModel "main-server/pkg/model/guest"
 	projectModel "main-server/pkg/model/project"
 	rbacModel "main-server/pkg/model/rbac"
+	serviceModel "main-server/pkg/model/service"
+	subEntityModel "main-server/pkg/model/sub_entity"
 	userModel "main-server/pkg/model/user"
 	workerModel "main-server/pkg/model/worker"
 	infoModel "main-server/pkg/module/excel_analysis/model"
+	"time"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/oauth2"
 )
 
 type Authorization interface {
-	CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error)
+	CreateUser(user userModel.UserRegisterModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	UploadProfileImage(c *gin.Context, filepath string) (bool, error)
-	LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error)
-	LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error)
-	CreateUserOAuth2(user userModel.UserRegisterOAuth2Model, token *oauth2.Token) (userModel.UserAuthDataModel, error)
-	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, token userModel.TokenOutputParse) (userModel.UserAuthDataModel, error)
+	LoginUser(user userModel.UserLoginModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginUserOAuth2(provider, code, state string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginUserOAuth2SDK(provider, code string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	GetOAuth2URL(provider string) (string, error)
+	CreateUserOAuth2(user userModel.UserRegisterOAuth2Model, token *oauth2.Token, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, token userModel.TokenOutputParse, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	Logout(tokens userModel.TokenLogoutDataModel) (bool, error)
 	Activate(link string) (bool, error)
 	GetUser(column, value string) (userModel.UserModel, error)
 	GetRole(column, value string) (rbacModel.RoleModel, error)
+	CheckActivation(usersId int) (bool, error)
+	ResendActivation(email string) (bool, error)
 	RecoveryPassword(email string) (bool, error)
 	ResetPassword(data userModel.ResetPasswordModel, token userModel.ResetTokenOutputParse) (bool, error)
 }
 
+type Session interface {
+	GetSessions(usersId int, currentSessionUuid string) (userModel.SessionsModel, error)
+	RevokeSession(usersId int, data userModel.SessionUuidModel) (bool, error)
+	RevokeOtherSessions(usersId int, currentSessionUuid string) (bool, error)
+	CheckSession(usersId int, sessionUuid string) error
+}
+
+type Identity interface {
+	GetIdentities(usersId int) ([]userModel.IdentityModel, error)
+	GetLinkURL(usersId int, provider string) (string, error)
+	LinkIdentity(usersId int, provider, code, state string) (bool, error)
+	UnlinkIdentity(usersId int, authType string) (bool, error)
+	SetPassword(usersId int, password string) (bool, error)
+}
+
+type Account interface {
+	GetExportData(usersId int) (userModel.AccountExportModel, error)
+	GetDeletion(usersId int) (userModel.AccountDeletionModel, error)
+	RequestDeletion(usersId int, password string) (userModel.AccountDeletionModel, error)
+	CancelDeletion(usersId int) (bool, error)
+	PurgeAccounts() (int, error)
+}
+
+type EmailChange interface {
+	GetEmailChange(usersId int) (userModel.EmailChangeStatusModel, error)
+	RequestEmailChange(usersId int, data userModel.EmailChangeModel) (bool, error)
+	ConfirmEmailChange(usersId int, token, currentSessionUuid string) (bool, error)
+	CancelEmailChange(token string) (bool, error)
+}
+
+type Revocation interface {
+	IsTokenRevoked(jti string) (bool, error)
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+	RevokeRefreshToken(refreshToken string) (bool, error)
+}
+
+type Throttle interface {
+	Check(action, email, ip string) (time.Duration, error)
+	Fail(action, email, ip string) error
+	Reset(action, email string) error
+}
+
+type SigningKey interface {
+	GetSigningKeys() ([]userModel.SigningKeyDbModel, error)
+	IsRotationDue(algorithm string, rotationInterval time.Duration) (bool, error)
+	CreateSigningKey(key userModel.SigningKeyDbModel, rotationInterval time.Duration) (bool, error)
+	DeleteRetiredSigningKeys(retention time.Duration) error
+	UpdateSigningKeyPrivateKey(kid, privateKey string) error
+}
+
+type TwoFactor interface {
+	Enroll(usersId int) (userModel.TwoFactorEnrollModel, error)
+	Confirm(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error)
+	Disable(user userModel.UserIdentityModel, data userModel.TwoFactorCodeModel) (bool, error)
+	RegenerateRecoveryCodes(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error)
+	GetStatus(user userModel.UserIdentityModel) (userModel.TwoFactorStatusModel, error)
+	SetupChallenge(data userModel.TwoFactorChallengeTokenModel) (userModel.TwoFactorEnrollModel, error)
+	GetChallengeEmail(token string) (string, error)
+	SignIn(data userModel.TwoFactorSignInModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	GetRequiredRoles(domainsId int) (userModel.TwoFactorRolesModel, error)
+	RequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error)
+	UnrequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error)
+}
+
+/* Интерфейс объяснения решений о доступе (casbin) */
+type Access interface {
+	Explain(usersId, domainsId int, data rbacModel.AccessExplainModel) (rbacModel.AccessExplainResultModel, error)
+}
+
 type Role interface {
 	HasRole(usersId, domainsId int, roleValue string) (bool, error)
+	GetRoles(data rbacModel.DomainUuidModel) (rbacModel.RolesModel, error)
+	CreateRole(usersId int, data rbacModel.RoleCreateModel) (rbacModel.RoleModel, error)
+	UpdateRole(data rbacModel.RoleUpdateModel) (rbacModel.RoleModel, error)
+	DeleteRole(data rbacModel.RoleUuidModel) (bool, error)
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*rbacModel.RoleModel, error)
 }
 
 type Domain interface {
+	GetDomains() (rbacModel.DomainsModel, error)
+	CreateDomain(usersId int, data rbacModel.DomainCreateModel) (rbacModel.DomainModel, error)
+	UpdateDomain(data rbacModel.DomainUpdateModel) (rbacModel.DomainModel, error)
+	DeleteDomain(data rbacModel.DomainUuidModel) (bool, error)
+	Resolve(value string) (*rbacModel.DomainModel, error)
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error)
@@ -56,6 +143,14 @@ type User interface {
 	Get(column string, value interface{}, check bool) (*userModel.UserModel, error)
 }
 
+/* Интерфейс репозитория для таблицы u_bans */
+type Ban interface {
+	BanUser(admin userModel.UserIdentityModel, data userModel.BanCreateModel) (userModel.BanModel, error)
+	UnbanUser(data userModel.BanUserUuidModel) (bool, error)
+	GetBans(data userModel.BanCountModel) (userModel.BanAnyCountModel, error)
+	CheckBan(usersId int) error
+}
+
 type Admin interface {
 	GetAllUsers(c *gin.Context) (adminModel.UsersResponseModel, error)
 	CreateCompany(c *gin.Context, data adminModel.CompanyModel) (adminModel.CompanyModel, error)
@@ -63,6 +158,7 @@ type Admin interface {
 }
 
 type AuthType interface {
+	AddAuthType(value string) error
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*userModel.AuthTypeModel, error)
@@ -79,6 +175,54 @@ type Project interface {
 	GetByWorker(id int, check bool) ([]projectModel.ProjectDbModel, error)
 }
 
+/* Интерфейс репозитория для таблицы cb_entities */
+type Entity interface {
+	CreateEntity(user userModel.UserIdentityModel, data entityModel.EntityCreateModel) (entityModel.EntityDbDataEx, error)
+	EntityUpdate(user userModel.UserIdentityModel, data entityModel.EntityUpdateModel) (entityModel.EntityUpdateModel, error)
+	GetEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (entityModel.EntityDbDataEx, error)
+	GetEntities(user userModel.UserIdentityModel, data entityModel.EntityCountModel) (entityModel.EntityAnyCountModel, error)
+	DeleteEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (bool, error)
+
+	// CRUD
+	Get(column string, value interface{}, check bool) (*entityModel.EntityDbModel, error)
+}
+
+/* Интерфейс репозитория для таблицы cb_sub_entities */
+type SubEntity interface {
+	CreateSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityCreateModel) (subEntityModel.SubEntityInfoModel, error)
+	SubEntityUpdate(user userModel.UserIdentityModel, data subEntityModel.SubEntityUpdateModel) (subEntityModel.SubEntityUpdateModel, error)
+	GetSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (subEntityModel.SubEntityInfoModel, error)
+	GetSubEntities(user userModel.UserIdentityModel, data subEntityModel.SubEntityCountModel) (subEntityModel.SubEntityAnyCountModel, error)
+	DeleteSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (bool, error)
+
+	// CRUD
+	Get(column string, value interface{}, check bool) (*subEntityModel.SubEntityDbModel, error)
+}
+
+/* Интерфейс репозитория публичного каталога */
+type Guest interface {
+	GetCompanies(data guestModel.GuestCompanyQueryModel) (guestModel.GuestCompanyAnyCountModel, error)
+	GetProjects(data guestModel.GuestProjectQueryModel) (guestModel.GuestProjectAnyCountModel, error)
+	GetSubEntities(data guestModel.GuestSubEntityQueryModel) (guestModel.GuestSubEntityAnyCountModel, error)
+	GetSubEntity(data guestModel.GuestUuidModel) (guestModel.GuestSubEntityModel, error)
+}
+
+/* Интерфейс репозитория для таблицы a_articles */
+type Article interface {
+	CreateArticle(user userModel.UserIdentityModel, data articleModel.ArticleCreateRequestModel) (articleModel.ArticleModel, error)
+	UpdateArticle(user userModel.UserIdentityModel, data articleModel.ArticleUpdateRequestModel) (articleModel.ArticleModel, error)
+	DeleteArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleSuccessModel, error)
+	GetUserArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error)
+	GetUserArticles(user userModel.UserIdentityModel, data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error)
+	GetUncheckedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error)
+	ModerateArticle(data articleModel.ArticleModerationModel, status string) (articleModel.ArticleSuccessModel, error)
+	GetApprovedArticle(data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error)
+	GetApprovedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error)
+
+	// CRUD
+	Get(column string, value interface{}, check bool) (*articleModel.ArticleDBModel, error)
+}
+
 type Company interface {
 	GetManagers(userId, domainId int, data companyModel.ManagerCountModel) (companyModel.ManagerAnyCountModel, error)
 	GetManager(user userModel.UserIdentityModel, data companyModel.ManagerUuidModel) (companyModel.ManagerCompanyModel, error)
@@ -98,7 +242,15 @@ type Wrapper interface {
 }
 
 type ServiceMain interface {
-	SendEmail(*userModel.UserIdentityModel, *emailModel.MessageInputModel) (bool, error)
+	SendEmail(clientId string, body *emailModel.MessageInputModel) (bool, error)
+}
+
+type ServiceClient interface {
+	CreateClient(data serviceModel.ServiceClientCreateModel) (serviceModel.ServiceClientSecretModel, error)
+	GetClients() (serviceModel.ServiceClientsModel, error)
+	RevokeClient(data serviceModel.ServiceClientIdModel) (bool, error)
+	GetActiveClient(clientId string) (serviceModel.ServiceClientDbModel, error)
+	IssueToken(data serviceModel.ServiceTokenRequestModel) (serviceModel.ServiceTokenModel, error)
 }
 
 type ExcelAnalysis interface {
@@ -115,6 +267,10 @@ type Object interface {
 
 /* Интерфейс репозитория для таблицы ac_types_objects */
 type TypeObject interface {
+	GetTypes() (rbacModel.TypeObjectsModel, error)
+	CreateType(usersId int, data rbacModel.TypeObjectCreateModel) (rbacModel.TypeObjectDbModel, error)
+	UpdateType(data rbacModel.TypeObjectUpdateModel) (rbacModel.TypeObjectDbModel, error)
+	DeleteType(data rbacModel.TypeObjectValueModel) (bool, error)
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*rbacModel.TypeObjectDbModel, error)
@@ -129,45 +285,82 @@ type Worker interface {
 
 type Repository struct {
 	Authorization
+	Access
 	Role
 	Domain
 	User
 	Admin
+	Ban
+	Session
+	Identity
+	Account
+	EmailChange
+	Revocation
+	TwoFactor
+	Throttle
+	SigningKey
 	AuthType
 	Project
+	Entity
+	SubEntity
+	Guest
+	Article
 	Company
 	Wrapper
 	ServiceMain
+	ServiceClient
 	ExcelAnalysis
 	Object
 	TypeObject
 	Worker
 }
 
-func NewRepository(db *sqlx.DB, enforcer *casbin.Enforcer) *Repository {
+func NewRepository(db *sqlx.DB, enforcer *Enforcer, watcher *PolicyWatcher) *Repository {
 	wrapper := NewWrapperPostgres(db)
 	domain := NewDomainPostgres(db)
 	acTypeObject := NewTypeObjectPostgres(db)
-	object := NewObjectPostgres(db, acTypeObject)
+	objectTree := NewObjectTree(db)
+	object := NewObjectPostgres(db, acTypeObject, objectTree)
 	role := NewRolePostgres(db, enforcer)
 	user := NewUserPostgres(db, enforcer, domain, role)
 	admin := NewAdminPostgres(db, enforcer, domain, role, user)
 	company := NewCompanyPostgres(db, enforcer, role, user, wrapper)
 	project := NewProjectPostgres(db, enforcer, role, user, object, company)
+	entity := NewEntityPostgres(db, enforcer, object)
+	subEntity := NewSubEntityPostgres(db, enforcer, object, entity)
 	serviceMain := NewServiceMainRepository(db, enforcer, user)
 	worker := NewWorkerPostgres(db, company)
 
+	// Наследование прав по дереву информационных ресурсов (функция используется в матчере config/model.conf)
+	enforcer.AddFunction(OBJECT_MATCH_FUNC, objectTree.MatchFunc)
+	watcher.WatchObjectTree(objectTree)
+
 	return &Repository{
 		Authorization: NewAuthPostgres(db, enforcer, *user),
+		Access:        NewAccessPostgres(enforcer, objectTree, role),
 		Role:          role,
 		Domain:        domain,
 		User:          user,
 		Admin:         admin,
+		Ban:           NewBanPostgres(db, user),
+		Session:       NewSessionPostgres(db),
+		Identity:      NewIdentityPostgres(db),
+		Account:       NewAccountPostgres(db, enforcer),
+		EmailChange:   NewEmailChangePostgres(db),
+		Revocation:    NewRevocationPostgres(db),
+		TwoFactor:     NewTwoFactorPostgres(db, enforcer, user),
+		Throttle:      NewThrottlePostgres(db),
+		SigningKey:    NewSigningKeyPostgres(db),
 		AuthType:      NewAuthTypePostgres(db),
 		Project:       project,
+		Entity:        entity,
+		SubEntity:     subEntity,
+		Guest:         NewGuestPostgres(db),
+		Article:       NewArticlePostgres(db),
 		Company:       company,
 		Wrapper:       wrapper,
 		ServiceMain:   serviceMain,
+		ServiceClient: NewServiceClientPostgres(db),
 		ExcelAnalysis: NewExcelAnalysis(),
 		Object:        object,
 		TypeObject:    acTypeObject,
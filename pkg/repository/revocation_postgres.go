@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
+)
+
+type RevocationPostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры RevocationPostgres */
+func NewRevocationPostgres(db *sqlx.DB) *RevocationPostgres {
+	return &RevocationPostgres{db: db}
+}
+
+/* Добавление идентификатора токена в список отозванных (с удалением записей об истёкших токенах) */
+func insertRevokedToken(db sqlx.Execer, jti string, expiresAt time.Time) error {
+	currentDate := time.Now()
+	if jti == "" || !expiresAt.After(currentDate) {
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (jti, expires_at, revoked_at) values ($1, $2, $3) ON CONFLICT (jti) DO NOTHING",
+		tableConstant.U_REVOKED_TOKENS,
+	)
+
+	if _, err := db.Exec(query, jti, expiresAt, currentDate); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE expires_at < $1", tableConstant.U_REVOKED_TOKENS)
+	_, err := db.Exec(query, currentDate)
+
+	return err
+}
+
+/* Отзыв токенов доступа по их значению; подпись не проверяется, так как токены взяты из БД */
+func revokeAccessTokens(db sqlx.Execer, accessTokens ...string) error {
+	for _, accessToken := range accessTokens {
+		claims := &tokenClaims{}
+		if _, _, err := new(jwt.Parser).ParseUnverified(accessToken, claims); err != nil {
+			continue
+		}
+
+		if err := insertRevokedToken(db, claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Отзыв токенов доступа, сохранённых для сессии или пользователя (column - sessions_id или users_id) */
+func revokeStoredAccessTokens(tx *sql.Tx, column string, value int) error {
+	query := fmt.Sprintf("SELECT access_token FROM %s WHERE %s = $1", tableConstant.U_TOKENS, column)
+
+	rows, err := tx.Query(query, value)
+	if err != nil {
+		return err
+	}
+
+	var accessTokens []string
+	for rows.Next() {
+		var accessToken string
+		if err := rows.Scan(&accessToken); err != nil {
+			rows.Close()
+			return err
+		}
+
+		accessTokens = append(accessTokens, accessToken)
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return revokeAccessTokens(tx, accessTokens...)
+}
+
+/* Отзыв пары токенов: сессия завершается целиком, токены без сессии удаляются вместе с токеном доступа */
+func revokeTokenPair(db *sqlx.DB, token userModel.TokenModel) error {
+	if token.SessionsId != nil {
+		return revokeSession(db, *token.SessionsId)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := revokeAccessTokens(tx, token.AccessToken); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.id = $1", tableConstant.U_TOKENS)
+	if _, err := tx.Exec(query, token.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+/* Проверка, отозван ли токен доступа */
+func (r *RevocationPostgres) IsTokenRevoked(jti string) (bool, error) {
+	var revoked bool
+
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE jti=$1)", tableConstant.U_REVOKED_TOKENS)
+	if err := r.db.Get(&revoked, query, jti); err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+/* Отзыв токена доступа до истечения его срока действия */
+func (r *RevocationPostgres) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	return insertRevokedToken(r.db, jti, expiresAt)
+}
+
+/* Отзыв токена обновления (вместе с сессией); false - токен не найден */
+func (r *RevocationPostgres) RevokeRefreshToken(refreshToken string) (bool, error) {
+	var tokens []userModel.TokenModel
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.refresh_token = $1 LIMIT 1", tableConstant.U_TOKENS)
+	if err := r.db.Select(&tokens, query, refreshToken); err != nil {
+		return false, err
+	}
+
+	if len(tokens) <= 0 {
+		return false, nil
+	}
+
+	if err := revokeTokenPair(r.db, tokens[0]); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -3,21 +3,37 @@ package repository
 import (
 	"errors"
 	"fmt"
+	roleConstant "main-server/pkg/constant/role"
 	tableConstant "main-server/pkg/constant/table"
 	rbacModel "main-server/pkg/model/rbac"
 	"strconv"
+	"strings"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
 )
 
+/*
+* Роли, на значения которых опирается приложение: их нельзя удалить или переименовать. Роли ищутся
+* по значению в домене запроса, поэтому каждый домен может иметь собственный набор системных ролей
+ */
+var systemRoles = []string{
+	roleConstant.ROLE_CLIENT,
+	roleConstant.ROLE_BUILDER_ADMIN,
+	roleConstant.ROLE_BUILDER_MANAGER,
+	roleConstant.ROLE_MANAGER,
+	roleConstant.ROLE_ADMIN,
+	roleConstant.ROLE_SUPER_ADMIN,
+	roleConstant.ROLE_MODERATOR,
+}
+
 type RolePostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *Enforcer
 }
 
 /* Создание нового экземпляра структуры RolePostgres */
-func NewRolePostgres(db *sqlx.DB, enforcer *casbin.Enforcer) *RolePostgres {
+func NewRolePostgres(db *sqlx.DB, enforcer *Enforcer) *RolePostgres {
 	return &RolePostgres{
 		db:       db,
 		enforcer: enforcer,
@@ -51,14 +67,33 @@ func (r *RolePostgres) Get(column string, value interface{}, check bool) (*rbacM
 	return &roles[len(roles)-1], err
 }
 
+/* Получение роли домена по значению (наборы ролей доменов независимы, значения ролей в разных доменах совпадают) */
+func (r *RolePostgres) GetInDomain(value string, domainsId int, check bool) (*rbacModel.RoleModel, error) {
+	var roles []rbacModel.RoleModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE value=$1 AND domains_id=$2 LIMIT 1", tableConstant.AC_ROLES)
+	if err := r.db.Select(&roles, query, value, domainsId); err != nil {
+		return nil, err
+	}
+
+	if len(roles) <= 0 {
+		if check {
+			return nil, fmt.Errorf("Ошибка: роли %s в домене не найдено!", value)
+		}
+
+		return nil, nil
+	}
+
+	return &roles[0], nil
+}
+
 /* Проверка присутствия у пользователя определённой роли (принадлежность к группе пользователей) */
 func (r *RolePostgres) HasRole(usersId, domainsId int, roleValue string) (bool, error) {
-	data, err := r.Get("value", roleValue, true)
+	data, err := r.GetInDomain(roleValue, domainsId, true)
 	if err != nil {
 		return false, err
 	}
 
-	r.enforcer.LoadPolicy()
 	has, err := r.enforcer.HasRoleForUser(
 		strconv.Itoa(usersId),
 		strconv.Itoa(data.Id),
@@ -67,3 +102,196 @@ func (r *RolePostgres) HasRole(usersId, domainsId int, roleValue string) (bool,
 
 	return has, err
 }
+
+/* Проверка принадлежности значения к системным ролям */
+func isSystemRole(value string) bool {
+	for _, role := range systemRoles {
+		if role == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+/* Поиск домена по uuid */
+func (r *RolePostgres) getDomain(domainUuid string) (rbacModel.DomainModel, error) {
+	var domains []rbacModel.DomainModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE uuid::text=$1 LIMIT 1", tableConstant.AC_DOMAINS)
+	if err := r.db.Select(&domains, query, domainUuid); err != nil {
+		return rbacModel.DomainModel{}, err
+	}
+
+	if len(domains) <= 0 {
+		return rbacModel.DomainModel{}, errors.New("Домен не найден!")
+	}
+
+	return domains[0], nil
+}
+
+/* Проверка отсутствия роли с тем же значением в домене */
+func (r *RolePostgres) checkRoleUnique(value string, domainsId int) error {
+	var exists bool
+
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE value=$1 AND domains_id=$2)", tableConstant.AC_ROLES)
+	if err := r.db.Get(&exists, query, value, domainsId); err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.New("Роль с данным значением в домене уже существует!")
+	}
+
+	return nil
+}
+
+/* Получение списка ролей домена */
+func (r *RolePostgres) GetRoles(data rbacModel.DomainUuidModel) (rbacModel.RolesModel, error) {
+	domain, err := r.getDomain(data.Uuid)
+	if err != nil {
+		return rbacModel.RolesModel{}, err
+	}
+
+	roles := []rbacModel.RoleModel{}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE domains_id=$1 ORDER BY id", tableConstant.AC_ROLES)
+	if err := r.db.Select(&roles, query, domain.Id); err != nil {
+		return rbacModel.RolesModel{}, err
+	}
+
+	return rbacModel.RolesModel{Roles: roles}, nil
+}
+
+/* Создание новой роли в домене */
+func (r *RolePostgres) CreateRole(usersId int, data rbacModel.RoleCreateModel) (rbacModel.RoleModel, error) {
+	value := strings.TrimSpace(data.Value)
+	if err := checkRbacValue(value); err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	domain, err := r.getDomain(data.DomainUuid)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	if err := r.checkRoleUnique(value, domain.Id); err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	var role rbacModel.RoleModel
+
+	query := fmt.Sprintf("INSERT INTO %s (value, description, users_id, domains_id) values ($1, $2, $3, $4) RETURNING *", tableConstant.AC_ROLES)
+	if err := r.db.Get(&role, query, value, data.Description, usersId, domain.Id); err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	return role, nil
+}
+
+/* Изменение значения или описания роли (у системных ролей изменяется только описание) */
+func (r *RolePostgres) UpdateRole(data rbacModel.RoleUpdateModel) (rbacModel.RoleModel, error) {
+	role, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	value := role.Value
+	if data.Value != nil && strings.TrimSpace(*data.Value) != role.Value {
+		value = strings.TrimSpace(*data.Value)
+
+		if err := checkRbacValue(value); err != nil {
+			return rbacModel.RoleModel{}, err
+		}
+
+		if isSystemRole(role.Value) {
+			return rbacModel.RoleModel{}, errors.New("Значение системной роли изменить нельзя!")
+		}
+
+		if role.DomainsId != nil {
+			if err := r.checkRoleUnique(value, *role.DomainsId); err != nil {
+				return rbacModel.RoleModel{}, err
+			}
+		}
+	}
+
+	description := role.Description
+	if data.Description != nil {
+		description = *data.Description
+	}
+
+	var result rbacModel.RoleModel
+
+	query := fmt.Sprintf("UPDATE %s SET value=$1, description=$2 WHERE id=$3 RETURNING *", tableConstant.AC_ROLES)
+	if err := r.db.Get(&result, query, value, description, role.Id); err != nil {
+		return rbacModel.RoleModel{}, err
+	}
+
+	return result, nil
+}
+
+/* Правила доступа (p), субъектом которых является роль ("<id роли>" или "<id роли>;<uuid объекта>") */
+func (r *RolePostgres) getRolePolicies(roleId int) [][]string {
+	subject := strconv.Itoa(roleId)
+
+	return lo.Filter(r.enforcer.GetPolicy(), func(rule []string, _ int) bool {
+		return rule[0] == subject || strings.HasPrefix(rule[0], subject+rbacModel.Separator)
+	})
+}
+
+/*
+* Удаление роли вместе с её правилами доступа (p). Роль, на которую ссылаются правила группировки casbin
+* (вида "<id роли>" или "<id роли>;<uuid объекта>"), удалить нельзя. Проверка не блокирует параллельное
+* назначение роли: правила группировки записываются адаптером casbin в отдельном соединении
+ */
+func (r *RolePostgres) DeleteRole(data rbacModel.RoleUuidModel) (bool, error) {
+	role, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return false, err
+	}
+
+	if isSystemRole(role.Value) {
+		return false, errors.New("Системную роль удалить нельзя!")
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+
+	query := fmt.Sprintf(
+		"SELECT COUNT(*) FROM %s WHERE ptype = 'g' AND split_part(v1, $2, 1) = $1",
+		tableConstant.AC_RULES,
+	)
+	if err := tx.Get(&count, query, strconv.Itoa(role.Id), rbacModel.Separator); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if count > 0 {
+		tx.Rollback()
+		return false, fmt.Errorf("Роль назначена пользователям (правил группировки: %d) и не может быть удалена!", count)
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", tableConstant.AC_ROLES)
+	if _, err := tx.Exec(query, role.Id); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	// Правила удаляются через enforcer (адаптер и другие экземпляры сервера) в отдельной транзакции адаптера,
+	// поэтому только после удаления роли: правила оставшейся роли не теряются при ошибке фиксации
+	if rules := r.getRolePolicies(role.Id); len(rules) > 0 {
+		if _, err := r.enforcer.RemovePolicies(rules); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
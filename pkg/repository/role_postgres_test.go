@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"main-server/config"
+	roleConstant "main-server/pkg/constant/role"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHasRoleUsesRequestDomain(t *testing.T) {
+	config.AppDomainConfig.CacheTTL = time.Minute
+
+	db := newTestDB(t)
+	enforcer := newTestEnforcer(t)
+
+	db.MustExec(`INSERT INTO ac_domains (id, value) VALUES (1, 'rental'), (2, 'crm')`)
+	db.MustExec(`INSERT INTO ac_roles (id, value, domains_id) VALUES (10, $1, 1), (20, $1, 2)`, roleConstant.ROLE_CLIENT)
+
+	domains := NewDomainPostgres(db)
+	roles := NewRolePostgres(db, enforcer)
+
+	rental, err := domains.Resolve("rental")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crm, err := domains.Resolve("crm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for domainsId, roleId := range map[int]int{rental.Id: 10, crm.Id: 20} {
+		role, err := roles.GetInDomain(roleConstant.ROLE_CLIENT, domainsId, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if role.Id != roleId {
+			t.Fatalf("domain %d: expected role %d, got %d", domainsId, roleId, role.Id)
+		}
+	}
+
+	// Пользователь является клиентом только в CRM
+	if _, err := enforcer.AddRoleForUserInDomain("7", "20", strconv.Itoa(crm.Id)); err != nil {
+		t.Fatal(err)
+	}
+
+	has, err := roles.HasRole(7, crm.Id, roleConstant.ROLE_CLIENT)
+	if err != nil || !has {
+		t.Fatalf("expected client role in crm, got %v (%v)", has, err)
+	}
+
+	has, err = roles.HasRole(7, rental.Id, roleConstant.ROLE_CLIENT)
+	if err != nil || has {
+		t.Fatalf("expected no client role in rental, got %v (%v)", has, err)
+	}
+
+	if _, err := domains.Resolve("unknown"); err == nil {
+		t.Fatal("expected unknown domain to be rejected")
+	}
+}
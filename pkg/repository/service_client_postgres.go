@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	tableConstant "main-server/pkg/constant/table"
+	serviceModel "main-server/pkg/model/service"
+	"main-server/pkg/service/keyring"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+	uuid "github.com/satori/go.uuid"
+)
+
+/* Единая ошибка аутентификации клиента: не позволяет определить, существует ли client_id */
+var ErrInvalidClient = errors.New("Неверный идентификатор или секрет клиента")
+
+/* Запрошена область доступа, не разрешённая клиенту */
+var ErrInvalidScope = errors.New("Запрошенная область доступа не разрешена клиенту")
+
+type ServiceClientPostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры ServiceClientPostgres */
+func NewServiceClientPostgres(db *sqlx.DB) *ServiceClientPostgres {
+	return &ServiceClientPostgres{db: db}
+}
+
+/* Преобразование записи клиента в модель ответа (без хэша секрета) */
+func toServiceClientModel(client serviceModel.ServiceClientDbModel) serviceModel.ServiceClientModel {
+	return serviceModel.ServiceClientModel{
+		ClientId:  client.ClientId,
+		Name:      client.Name,
+		Scopes:    strings.Fields(client.Scopes),
+		CreatedAt: client.CreatedAt,
+		RevokedAt: client.RevokedAt,
+	}
+}
+
+/* Регистрация сервисного клиента; секрет хранится только в виде хэша */
+func (r *ServiceClientPostgres) CreateClient(data serviceModel.ServiceClientCreateModel) (serviceModel.ServiceClientSecretModel, error) {
+	scopes := lo.Uniq(data.Scopes)
+	if len(scopes) <= 0 {
+		return serviceModel.ServiceClientSecretModel{}, errors.New("Не указаны области доступа клиента")
+	}
+
+	for _, scope := range scopes {
+		if !lo.Contains(authConstant.SERVICE_SCOPES, scope) {
+			return serviceModel.ServiceClientSecretModel{}, fmt.Errorf("Неизвестная область доступа: %s", scope)
+		}
+	}
+
+	secret, err := randomHex(authConstant.SERVICE_SECRET_BYTES)
+	if err != nil {
+		return serviceModel.ServiceClientSecretModel{}, err
+	}
+
+	clientId := uuid.NewV4().String()
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (client_id, name, secret_hash, scopes, created_at) values ($1, $2, $3, $4, $5)",
+		tableConstant.U_SERVICE_CLIENTS,
+	)
+
+	_, err = r.db.Exec(query, clientId, data.Name, hashOneTimeValue(secret), strings.Join(scopes, " "), time.Now())
+	if err != nil {
+		return serviceModel.ServiceClientSecretModel{}, err
+	}
+
+	return serviceModel.ServiceClientSecretModel{
+		ClientId:     clientId,
+		ClientSecret: secret,
+		Name:         data.Name,
+		Scopes:       scopes,
+	}, nil
+}
+
+/* Получение всех сервисных клиентов */
+func (r *ServiceClientPostgres) GetClients() (serviceModel.ServiceClientsModel, error) {
+	var clients []serviceModel.ServiceClientDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY created_at DESC", tableConstant.U_SERVICE_CLIENTS)
+	if err := r.db.Select(&clients, query); err != nil {
+		return serviceModel.ServiceClientsModel{}, err
+	}
+
+	result := serviceModel.ServiceClientsModel{Clients: []serviceModel.ServiceClientModel{}}
+	for _, client := range clients {
+		result.Clients = append(result.Clients, toServiceClientModel(client))
+	}
+
+	return result, nil
+}
+
+/* Отключение сервисного клиента (выданные ему токены перестают приниматься) */
+func (r *ServiceClientPostgres) RevokeClient(data serviceModel.ServiceClientIdModel) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=$1 WHERE client_id=$2 AND revoked_at IS NULL", tableConstant.U_SERVICE_CLIENTS)
+
+	result, err := r.db.Exec(query, time.Now(), data.ClientId)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if count <= 0 {
+		return false, errors.New("Сервисный клиент не найден или уже отключён")
+	}
+
+	return true, nil
+}
+
+/* Получение действующего (не отключённого) сервисного клиента */
+func (r *ServiceClientPostgres) GetActiveClient(clientId string) (serviceModel.ServiceClientDbModel, error) {
+	var clients []serviceModel.ServiceClientDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE client_id=$1 AND revoked_at IS NULL LIMIT 1", tableConstant.U_SERVICE_CLIENTS)
+	if err := r.db.Select(&clients, query, clientId); err != nil {
+		return serviceModel.ServiceClientDbModel{}, err
+	}
+
+	if len(clients) <= 0 {
+		return serviceModel.ServiceClientDbModel{}, errors.New("Сервисный клиент не найден или отключён")
+	}
+
+	return clients[0], nil
+}
+
+/*
+* Выдача токена по client credentials: проверка секрета и запрошенных областей доступа.
+* Если области не указаны, выдаются все области, разрешённые клиенту
+ */
+func (r *ServiceClientPostgres) IssueToken(data serviceModel.ServiceTokenRequestModel) (serviceModel.ServiceTokenModel, error) {
+	client, err := r.GetActiveClient(data.ClientId)
+	if err != nil {
+		return serviceModel.ServiceTokenModel{}, ErrInvalidClient
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashOneTimeValue(data.ClientSecret)), []byte(client.SecretHash)) != 1 {
+		return serviceModel.ServiceTokenModel{}, ErrInvalidClient
+	}
+
+	allowed := strings.Fields(client.Scopes)
+	scopes := allowed
+
+	if requested := lo.Uniq(strings.Fields(data.Scope)); len(requested) > 0 {
+		for _, scope := range requested {
+			if !lo.Contains(allowed, scope) {
+				return serviceModel.ServiceTokenModel{}, ErrInvalidScope
+			}
+		}
+
+		scopes = requested
+	}
+
+	scope := strings.Join(scopes, " ")
+
+	token, err := keyring.Sign(&serviceModel.ServiceTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewV4().String(),
+			Subject:   client.ClientId,
+			ExpiresAt: time.Now().Add(authConstant.SERVICE_TOKEN_TTL).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		ClientId: client.ClientId,
+		Scope:    scope,
+	})
+
+	if err != nil {
+		return serviceModel.ServiceTokenModel{}, err
+	}
+
+	return serviceModel.ServiceTokenModel{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(authConstant.SERVICE_TOKEN_TTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
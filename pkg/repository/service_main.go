@@ -3,23 +3,22 @@ package repository
 import (
 	"main-server/pkg/model/email"
 	emailModel "main-server/pkg/model/email"
-	userModel "main-server/pkg/model/user"
 	smtpService "main-server/pkg/service/smtp"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
 /* Структура описывающая пакет текущего репозитория */
 type ServiceMainRepository struct {
 	db           *sqlx.DB
-	enforcer     *casbin.Enforcer
+	enforcer     *Enforcer
 	userPostgres *UserPostgres
 }
 
 /* Создание нового экземпляра репозитория */
-func NewServiceMainRepository(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgres *UserPostgres) *ServiceMainRepository {
+func NewServiceMainRepository(db *sqlx.DB, enforcer *Enforcer, userPostgres *UserPostgres) *ServiceMainRepository {
 	return &ServiceMainRepository{
 		db:           db,
 		enforcer:     enforcer,
@@ -28,7 +27,7 @@ func NewServiceMainRepository(db *sqlx.DB, enforcer *casbin.Enforcer, userPostgr
 }
 
 /* Отправка сообщения пользователю */
-func (r *ServiceMainRepository) SendEmail(user *userModel.UserIdentityModel, body *emailModel.MessageInputModel) (bool, error) {
+func (r *ServiceMainRepository) SendEmail(clientId string, body *emailModel.MessageInputModel) (bool, error) {
 	var emailReceivers []string
 
 	// Конвертация UUID пользователей в Email-адреса
@@ -56,5 +55,7 @@ func (r *ServiceMainRepository) SendEmail(user *userModel.UserIdentityModel, bod
 		return false, err
 	}
 
+	logrus.Infof("service client %s sent email to %d receivers", clientId, len(emailReceivers))
+
 	return true, nil
 }
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
+)
+
+type SessionPostgres struct {
+	db *sqlx.DB
+}
+
+/*
+* Функция создания экземпляра сервиса
+ */
+func NewSessionPostgres(db *sqlx.DB) *SessionPostgres {
+	return &SessionPostgres{db: db}
+}
+
+/*
+* Создание новой сессии пользователя и выдача пары токенов, привязанных к ней.
+* tokenApi / tokenApiRefresh - токены внешнего провайдера (для локальной аутентификации - nil)
+ */
+func startSession(
+	tx *sql.Tx,
+	usersId int,
+	usersUuid, authTypesUuid string,
+	info userModel.SessionInfoModel,
+	tokenApi, tokenApiRefresh *string,
+) (userModel.UserAuthDataModel, error) {
+	currentDate := time.Now()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (users_id, device_name, ip, user_agent, created_at, last_used_at) 
+		values ($1, $2, $3, $4, $5, $6) RETURNING id, uuid`,
+		tableConstant.U_SESSIONS,
+	)
+
+	var sessionId int
+	var sessionUuid string
+
+	row := tx.QueryRow(query, usersId, info.DeviceName, info.Ip, info.UserAgent, currentDate, currentDate)
+	if err := row.Scan(&sessionId, &sessionUuid); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return issueSessionTokens(tx, usersId, sessionId, usersUuid, authTypesUuid, sessionUuid, tokenApi, tokenApiRefresh)
+}
+
+/* Генерация пары токенов для сессии и их сохранение */
+func issueSessionTokens(
+	tx *sql.Tx,
+	usersId, sessionId int,
+	usersUuid, authTypesUuid, sessionUuid string,
+	tokenApi, tokenApiRefresh *string,
+) (userModel.UserAuthDataModel, error) {
+	accessToken, err := GenerateAccessToken(usersUuid, authTypesUuid, &sessionUuid, tokenApi, authConstant.TOKEN_TLL_ACCESS)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	refreshToken, err := GenerateToken(usersUuid, authTypesUuid, &sessionUuid, tokenApiRefresh, authConstant.TOKEN_TLL_REFRESH, viper.GetString("token.signing_key_refresh"))
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (users_id, access_token, refresh_token, sessions_id) values ($1, $2, $3, $4)", tableConstant.U_TOKENS)
+	if _, err := tx.Exec(query, usersId, accessToken, refreshToken, sessionId); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return userModel.UserAuthDataModel{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+/* Завершение сессии: отметка об отзыве и удаление всех токенов сессии */
+func revokeSession(db *sqlx.DB, sessionId int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=$1 WHERE id=$2 AND revoked_at IS NULL", tableConstant.U_SESSIONS)
+	if _, err := tx.Exec(query, time.Now(), sessionId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := revokeStoredAccessTokens(tx, "sessions_id", sessionId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.sessions_id = $1", tableConstant.U_TOKENS)
+	if _, err := tx.Exec(query, sessionId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+/* Завершение всех сессий пользователя, кроме текущей, в рамках транзакции (смена email-адреса и т.д.) */
+func revokeOtherUserSessions(tx *sql.Tx, usersId int, currentSessionUuid string) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET revoked_at=$1 WHERE users_id=$2 AND revoked_at IS NULL AND uuid::text <> $3 RETURNING id",
+		tableConstant.U_SESSIONS,
+	)
+
+	rows, err := tx.Query(query, time.Now(), usersId, currentSessionUuid)
+	if err != nil {
+		return err
+	}
+
+	var sessionsId []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+
+		sessionsId = append(sessionsId, id)
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range sessionsId {
+		if err := revokeStoredAccessTokens(tx, "sessions_id", id); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.sessions_id = $1", tableConstant.U_TOKENS)
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* Завершение всех сессий пользователя в рамках транзакции (блокировка, смена пароля и т.д.) */
+func revokeUserSessions(tx *sql.Tx, usersId int) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=$1 WHERE users_id=$2 AND revoked_at IS NULL", tableConstant.U_SESSIONS)
+	if _, err := tx.Exec(query, time.Now(), usersId); err != nil {
+		return err
+	}
+
+	if err := revokeStoredAccessTokens(tx, "users_id", usersId); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.users_id = $1", tableConstant.U_TOKENS)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/* Получение сессии пользователя по uuid */
+func (r *SessionPostgres) getSession(usersId int, sessionUuid string) (*userModel.SessionDbModel, error) {
+	var sessions []userModel.SessionDbModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE uuid=$1 AND users_id=$2", tableConstant.U_SESSIONS)
+
+	if err := r.db.Select(&sessions, query, sessionUuid, usersId); err != nil {
+		return nil, err
+	}
+
+	if len(sessions) <= 0 {
+		return nil, errors.New("Ошибка: сессия не найдена!")
+	}
+
+	return &sessions[len(sessions)-1], nil
+}
+
+/* Проверка, что сессия пользователя не завершена */
+func (r *SessionPostgres) CheckSession(usersId int, sessionUuid string) error {
+	session, err := r.getSession(usersId, sessionUuid)
+	if err != nil {
+		return err
+	}
+
+	if session.RevokedAt != nil {
+		return errors.New("Ошибка: сессия завершена, авторизуйтесь повторно!")
+	}
+
+	return nil
+}
+
+/* Получение списка активных сессий пользователя */
+func (r *SessionPostgres) GetSessions(usersId int, currentSessionUuid string) (userModel.SessionsModel, error) {
+	var sessions []userModel.SessionDbModel
+	query := fmt.Sprintf(
+		"SELECT * FROM %s WHERE users_id=$1 AND revoked_at IS NULL ORDER BY last_used_at DESC",
+		tableConstant.U_SESSIONS,
+	)
+
+	if err := r.db.Select(&sessions, query, usersId); err != nil {
+		return userModel.SessionsModel{}, err
+	}
+
+	result := []userModel.SessionModel{}
+	for _, item := range sessions {
+		result = append(result, userModel.SessionModel{
+			Uuid:       item.Uuid,
+			DeviceName: item.DeviceName,
+			Ip:         item.Ip,
+			UserAgent:  item.UserAgent,
+			CreatedAt:  item.CreatedAt,
+			LastUsedAt: item.LastUsedAt,
+			IsCurrent:  item.Uuid == currentSessionUuid,
+		})
+	}
+
+	return userModel.SessionsModel{
+		Sessions: result,
+	}, nil
+}
+
+/* Завершение сессии пользователя */
+func (r *SessionPostgres) RevokeSession(usersId int, data userModel.SessionUuidModel) (bool, error) {
+	session, err := r.getSession(usersId, data.Uuid)
+	if err != nil {
+		return false, err
+	}
+
+	if err := revokeSession(r.db, session.Id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Завершение всех сессий пользователя, кроме текущей */
+func (r *SessionPostgres) RevokeOtherSessions(usersId int, currentSessionUuid string) (bool, error) {
+	var sessions []userModel.SessionDbModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id=$1 AND revoked_at IS NULL", tableConstant.U_SESSIONS)
+
+	if err := r.db.Select(&sessions, query, usersId); err != nil {
+		return false, err
+	}
+
+	for _, item := range sessions {
+		if item.Uuid == currentSessionUuid {
+			continue
+		}
+
+		if err := revokeSession(r.db, item.Id); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/* Идентификатор advisory-блокировки, исключающей одновременную ротацию ключей несколькими экземплярами */
+const signingKeyRotationLock = 7310482
+
+type SigningKeyPostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры SigningKeyPostgres */
+func NewSigningKeyPostgres(db *sqlx.DB) *SigningKeyPostgres {
+	return &SigningKeyPostgres{db: db}
+}
+
+/* Получение всех ключей подписи (от новых к старым) */
+func (r *SigningKeyPostgres) GetSigningKeys() ([]userModel.SigningKeyDbModel, error) {
+	var keys []userModel.SigningKeyDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY created_at DESC, id DESC", tableConstant.U_SIGNING_KEYS)
+	if err := r.db.Select(&keys, query); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+/* Проверка необходимости ротации: нет действующего ключа заданного алгоритма моложе интервала ротации */
+func (r *SigningKeyPostgres) IsRotationDue(algorithm string, rotationInterval time.Duration) (bool, error) {
+	var due bool
+
+	query := fmt.Sprintf(
+		"SELECT NOT EXISTS(SELECT 1 FROM %s WHERE retired_at IS NULL AND algorithm=$1 AND created_at > $2)",
+		tableConstant.U_SIGNING_KEYS,
+	)
+
+	if err := r.db.Get(&due, query, algorithm, time.Now().Add(-rotationInterval)); err != nil {
+		return false, err
+	}
+
+	return due, nil
+}
+
+/*
+* Добавление нового действующего ключа с выводом предыдущих из ротации.
+* Необходимость ротации перепроверяется под блокировкой: ключ мог быть создан другим экземпляром
+ */
+func (r *SigningKeyPostgres) CreateSigningKey(key userModel.SigningKeyDbModel, rotationInterval time.Duration) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", signingKeyRotationLock); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	currentDate := time.Now()
+
+	var due bool
+	query := fmt.Sprintf(
+		"SELECT NOT EXISTS(SELECT 1 FROM %s WHERE retired_at IS NULL AND algorithm=$1 AND created_at > $2)",
+		tableConstant.U_SIGNING_KEYS,
+	)
+
+	if err := tx.Get(&due, query, key.Algorithm, currentDate.Add(-rotationInterval)); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if !due {
+		tx.Rollback()
+		return false, nil
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET retired_at=$1 WHERE retired_at IS NULL", tableConstant.U_SIGNING_KEYS)
+	if _, err := tx.Exec(query, currentDate); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO %s (kid, algorithm, private_key, public_key, created_at) values ($1, $2, $3, $4, $5)",
+		tableConstant.U_SIGNING_KEYS,
+	)
+
+	if _, err := tx.Exec(query, key.Kid, key.Algorithm, key.PrivateKey, key.PublicKey, currentDate); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Замена сохранённого закрытого ключа (шифрование ключа, записанного в открытом виде) */
+func (r *SigningKeyPostgres) UpdateSigningKeyPrivateKey(kid, privateKey string) error {
+	query := fmt.Sprintf("UPDATE %s SET private_key=$1 WHERE kid=$2", tableConstant.U_SIGNING_KEYS)
+	_, err := r.db.Exec(query, privateKey, kid)
+
+	return err
+}
+
+/* Удаление ключей, выведенных из ротации раньше срока хранения */
+func (r *SigningKeyPostgres) DeleteRetiredSigningKeys(retention time.Duration) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE retired_at IS NOT NULL AND retired_at < $1", tableConstant.U_SIGNING_KEYS)
+	_, err := r.db.Exec(query, time.Now().Add(-retention))
+
+	return err
+}
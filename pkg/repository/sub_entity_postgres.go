@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	actionConstant "main-server/pkg/constant/action"
+	objectConstant "main-server/pkg/constant/object"
+	statusConstant "main-server/pkg/constant/status"
+	tableConstant "main-server/pkg/constant/table"
+	rbacModel "main-server/pkg/model/rbac"
+	subEntityModel "main-server/pkg/model/sub_entity"
+	userModel "main-server/pkg/model/user"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/satori/go.uuid"
+)
+
+type SubEntityPostgres struct {
+	db       *sqlx.DB
+	enforcer *Enforcer
+	object   *ObjectPostgres
+	entity   *EntityPostgres
+}
+
+/* Функция создания нового экземпляра структуры SubEntityPostgres */
+func NewSubEntityPostgres(
+	db *sqlx.DB,
+	enforcer *Enforcer,
+	object *ObjectPostgres,
+	entity *EntityPostgres,
+) *SubEntityPostgres {
+	return &SubEntityPostgres{
+		db:       db,
+		enforcer: enforcer,
+		object:   object,
+		entity:   entity,
+	}
+}
+
+/* Преобразование записи таблицы в модель информации о помещении */
+func subEntityToInfo(subEntity *subEntityModel.SubEntityDbModel) (subEntityModel.SubEntityInfoModel, error) {
+	var subEntityData subEntityModel.SubEntityDataModel
+	if err := json.Unmarshal([]byte(subEntity.Data), &subEntityData); err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	return subEntityModel.SubEntityInfoModel{
+		Uuid:      subEntity.Uuid,
+		Data:      subEntityData,
+		Rooms:     subEntity.Rooms,
+		Area:      subEntity.Area,
+		Floor:     subEntity.Floor,
+		Price:     subEntity.Price,
+		Status:    subEntity.Status,
+		CreatedAt: subEntity.CreatedAt,
+	}, nil
+}
+
+/* Создание нового помещения в рамках объекта */
+func (r *SubEntityPostgres) CreateSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityCreateModel) (subEntityModel.SubEntityInfoModel, error) {
+	entity, err := r.entity.Get("uuid", data.EntityUuid, true)
+	if err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	// Добавлять помещения могут пользователи, имеющие право на изменение объекта
+	access, err := enforceAny(r.enforcer, user, actionConstant.MODIFY, data.EntityUuid)
+	if err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	if !access {
+		return subEntityModel.SubEntityInfoModel{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	if data.Status == "" {
+		data.Status = statusConstant.SUB_ENTITY_AVAILABLE
+	}
+
+	subEntityData := subEntityModel.SubEntityDataModel{
+		Title:       data.Title,
+		Description: data.Description,
+	}
+
+	dataJson, err := json.Marshal(subEntityData)
+	if err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	// Начало транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (uuid, data, rooms, area, floor, price, status, created_at, updated_at, entities_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		tableConstant.CB_SUB_ENTITIES,
+	)
+
+	subEntityUuid := uuid.NewV4()
+	currentDate := time.Now()
+
+	_, err = tx.Exec(
+		query, subEntityUuid, dataJson, data.Rooms, data.Area, data.Floor,
+		data.Price, data.Status, currentDate, currentDate, entity.Id,
+	)
+	if err != nil {
+		tx.Rollback()
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	// Регистрация помещения как дочернего информационного ресурса объекта
+	var resource rbacModel.ResourceModel
+	resource.ParentUuid = &data.EntityUuid
+	resource.TypeResource = objectConstant.SUB_ENTITY
+	resource.Resource.ResourceUuid = subEntityUuid.String()
+	resource.Resource.Description = fmt.Sprintf("Помещение объекта: %s", data.Title)
+
+	_, err = r.object.AddResource(&resource)
+	if err != nil {
+		tx.Rollback()
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	return subEntityModel.SubEntityInfoModel{
+		Uuid:      subEntityUuid.String(),
+		Data:      subEntityData,
+		Rooms:     data.Rooms,
+		Area:      data.Area,
+		Floor:     data.Floor,
+		Price:     data.Price,
+		Status:    data.Status,
+		CreatedAt: currentDate,
+	}, nil
+}
+
+/* Обновление информации о помещении */
+func (r *SubEntityPostgres) SubEntityUpdate(user userModel.UserIdentityModel, data subEntityModel.SubEntityUpdateModel) (subEntityModel.SubEntityUpdateModel, error) {
+	if _, err := r.Get("uuid", data.Uuid, true); err != nil {
+		return subEntityModel.SubEntityUpdateModel{}, err
+	}
+
+	// Права на объект и вышестоящие ресурсы наследуются помещением
+	access, err := enforceAny(r.enforcer, user, actionConstant.MODIFY, data.Uuid)
+	if err != nil {
+		return subEntityModel.SubEntityUpdateModel{}, err
+	}
+
+	if !access {
+		return subEntityModel.SubEntityUpdateModel{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	dataJson, err := json.Marshal(subEntityModel.SubEntityDataModel{
+		Title:       data.Title,
+		Description: data.Description,
+	})
+	if err != nil {
+		return subEntityModel.SubEntityUpdateModel{}, err
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE %s tl SET data=$1, rooms=$2, area=$3, floor=$4, price=$5, status=$6, updated_at=$7
+		WHERE tl.uuid=$8`,
+		tableConstant.CB_SUB_ENTITIES,
+	)
+
+	_, err = r.db.Exec(
+		query, dataJson, data.Rooms, data.Area, data.Floor,
+		data.Price, data.Status, time.Now(), data.Uuid,
+	)
+	if err != nil {
+		return subEntityModel.SubEntityUpdateModel{}, err
+	}
+
+	return data, nil
+}
+
+/* Получение информации об одном помещении */
+func (r *SubEntityPostgres) GetSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (subEntityModel.SubEntityInfoModel, error) {
+	subEntity, err := r.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	access, err := enforceAny(r.enforcer, user, actionConstant.READ, data.Uuid)
+	if err != nil {
+		return subEntityModel.SubEntityInfoModel{}, err
+	}
+
+	if !access {
+		return subEntityModel.SubEntityInfoModel{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	return subEntityToInfo(subEntity)
+}
+
+/* Получение среза помещений объекта */
+func (r *SubEntityPostgres) GetSubEntities(user userModel.UserIdentityModel, data subEntityModel.SubEntityCountModel) (subEntityModel.SubEntityAnyCountModel, error) {
+	entity, err := r.entity.Get("uuid", data.Uuid, true)
+	if err != nil {
+		return subEntityModel.SubEntityAnyCountModel{}, err
+	}
+
+	access, err := enforceAny(r.enforcer, user, actionConstant.READ, data.Uuid)
+	if err != nil {
+		return subEntityModel.SubEntityAnyCountModel{}, err
+	}
+
+	if !access {
+		return subEntityModel.SubEntityAnyCountModel{}, errors.New("Ошибка! Нет доступа!")
+	}
+
+	var subEntities []subEntityModel.SubEntityDbModel
+	args := []interface{}{entity.Id}
+
+	query := fmt.Sprintf("SELECT * FROM %s tl WHERE tl.entities_id = $1", tableConstant.CB_SUB_ENTITIES)
+
+	// Фильтрация помещений по статусу
+	if data.Status != nil {
+		args = append(args, *data.Status)
+		query += fmt.Sprintf(" AND tl.status = $%d", len(args))
+	}
+
+	args = append(args, data.Limit, data.Count)
+	query += fmt.Sprintf(" ORDER BY tl.created_at DESC, tl.id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	if err := r.db.Select(&subEntities, query, args...); err != nil {
+		return subEntityModel.SubEntityAnyCountModel{}, err
+	}
+
+	var subEntitiesInfo []subEntityModel.SubEntityInfoModel
+	for i := range subEntities {
+		info, err := subEntityToInfo(&subEntities[i])
+		if err != nil {
+			return subEntityModel.SubEntityAnyCountModel{}, err
+		}
+
+		subEntitiesInfo = append(subEntitiesInfo, info)
+	}
+
+	return subEntityModel.SubEntityAnyCountModel{
+		SubEntities: subEntitiesInfo,
+		Count:       len(subEntitiesInfo),
+	}, nil
+}
+
+/* Удаление помещения вместе с его информационным ресурсом и правилами доступа */
+func (r *SubEntityPostgres) DeleteSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (bool, error) {
+	if _, err := r.Get("uuid", data.Uuid, true); err != nil {
+		return false, err
+	}
+
+	access, err := enforceAny(r.enforcer, user, actionConstant.DELETE, data.Uuid)
+	if err != nil {
+		return false, err
+	}
+
+	if !access {
+		return false, errors.New("Ошибка! Нет доступа!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s tl WHERE tl.uuid = $1", tableConstant.CB_SUB_ENTITIES)
+	if _, err := tx.Exec(query, data.Uuid); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s tl WHERE tl.value = $1", tableConstant.AC_OBJECTS)
+	if _, err := tx.Exec(query, data.Uuid); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if _, err := r.enforcer.RemoveFilteredPolicy(2, data.Uuid); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Получение экземпляра помещения из таблицы */
+func (r *SubEntityPostgres) Get(column string, value interface{}, check bool) (*subEntityModel.SubEntityDbModel, error) {
+	var subEntities []subEntityModel.SubEntityDbModel
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s=$1", tableConstant.CB_SUB_ENTITIES, column)
+
+	var err error
+
+	switch value.(type) {
+	case int:
+		err = r.db.Select(&subEntities, query, value.(int))
+		break
+	case string:
+		err = r.db.Select(&subEntities, query, value.(string))
+		break
+	}
+
+	if len(subEntities) <= 0 {
+		if check {
+			return nil, errors.New(fmt.Sprintf("Ошибка: помещения по запросу %s:%v не найдено!", column, value))
+		}
+
+		return nil, nil
+	}
+
+	return &subEntities[len(subEntities)-1], err
+}
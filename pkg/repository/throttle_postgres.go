@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	tableConstant "main-server/pkg/constant/table"
+	"main-server/pkg/model/email"
+	userModel "main-server/pkg/model/user"
+	smtpService "main-server/pkg/service/smtp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/* Единая ошибка авторизации: не позволяет определить, существует ли учётная запись */
+var ErrInvalidCredentials = errors.New("Неверный email или пароль! Повторите попытку")
+
+/* Единая ошибка сброса пароля (токен некорректен, просрочен или уже использован) */
+var ErrInvalidResetToken = errors.New("Некорректный токен сброса пароля")
+
+type ThrottlePostgres struct {
+	db *sqlx.DB
+}
+
+/* Функция создания нового экземпляра структуры ThrottlePostgres */
+func NewThrottlePostgres(db *sqlx.DB) *ThrottlePostgres {
+	return &ThrottlePostgres{db: db}
+}
+
+/* Ключ счётчика учётной записи (email без учёта регистра) */
+func throttleAccountKey(userEmail string) string {
+	return strings.ToLower(strings.TrimSpace(userEmail))
+}
+
+/* Экспоненциальная задержка после исчерпания бесплатных попыток */
+func throttleDelay(failures, free int) time.Duration {
+	if failures <= free {
+		return 0
+	}
+
+	delay := authConstant.THROTTLE_BASE_DELAY
+	for i := free + 1; i < failures && delay < authConstant.THROTTLE_MAX_DELAY; i++ {
+		delay *= 2
+	}
+
+	if delay > authConstant.THROTTLE_MAX_DELAY {
+		delay = authConstant.THROTTLE_MAX_DELAY
+	}
+
+	return delay
+}
+
+/*
+* Время, оставшееся до снятия ограничения для email или IP-адреса (0 - ограничений нет).
+* Разница вычисляется в БД, так как даты хранятся без часового пояса
+ */
+func (r *ThrottlePostgres) Check(action, userEmail, ip string) (time.Duration, error) {
+	var seconds []float64
+
+	query := fmt.Sprintf(
+		`SELECT EXTRACT(EPOCH FROM (locked_until - $2)) FROM %s
+		WHERE action=$1 AND locked_until > $2 AND ((scope=$3 AND key=$4) OR (scope=$5 AND key=$6))
+		ORDER BY locked_until DESC LIMIT 1`,
+		tableConstant.U_AUTH_THROTTLE,
+	)
+
+	err := r.db.Select(&seconds, query, action, time.Now(),
+		authConstant.THROTTLE_SCOPE_ACCOUNT, throttleAccountKey(userEmail),
+		authConstant.THROTTLE_SCOPE_IP, ip,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(seconds) <= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(seconds[0] * float64(time.Second)), nil
+}
+
+/* Увеличение счётчика неудачных попыток; счётчик сбрасывается, если с прошлой неудачи прошло больше THROTTLE_WINDOW */
+func (r *ThrottlePostgres) registerFailure(scope, key, action string) (int, error) {
+	currentDate := time.Now()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s AS tl (scope, key, action, failures, last_failure_at) values ($1, $2, $3, 1, $4)
+		ON CONFLICT (scope, key, action) DO UPDATE SET
+			failures = CASE WHEN tl.last_failure_at < $5 THEN 1 ELSE tl.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures`,
+		tableConstant.U_AUTH_THROTTLE,
+	)
+
+	var failures int
+	if err := r.db.Get(&failures, query, scope, key, action, currentDate, currentDate.Add(-authConstant.THROTTLE_WINDOW)); err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+/* Установка времени, до которого действие недоступно */
+func (r *ThrottlePostgres) lock(scope, key, action string, lockedUntil time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET locked_until=$1 WHERE scope=$2 AND key=$3 AND action=$4", tableConstant.U_AUTH_THROTTLE)
+	_, err := r.db.Exec(query, lockedUntil, scope, key, action)
+
+	return err
+}
+
+/* Учёт неудачной попытки для email и IP-адреса с назначением задержки или временной блокировки */
+func (r *ThrottlePostgres) Fail(action, userEmail, ip string) error {
+	currentDate := time.Now()
+
+	if ip != "" {
+		failures, err := r.registerFailure(authConstant.THROTTLE_SCOPE_IP, ip, action)
+		if err != nil {
+			return err
+		}
+
+		if delay := throttleDelay(failures, authConstant.THROTTLE_IP_FREE); delay > 0 {
+			if err := r.lock(authConstant.THROTTLE_SCOPE_IP, ip, action, currentDate.Add(delay)); err != nil {
+				return err
+			}
+		}
+	}
+
+	key := throttleAccountKey(userEmail)
+	if key == "" {
+		return nil
+	}
+
+	failures, err := r.registerFailure(authConstant.THROTTLE_SCOPE_ACCOUNT, key, action)
+	if err != nil {
+		return err
+	}
+
+	if failures >= authConstant.THROTTLE_LOCKOUT_ATTEMPTS {
+		lockedUntil := currentDate.Add(authConstant.THROTTLE_LOCKOUT_TTL)
+		if err := r.lock(authConstant.THROTTLE_SCOPE_ACCOUNT, key, action, lockedUntil); err != nil {
+			return err
+		}
+
+		// Уведомление о блокировке входа отправляется один раз - в момент блокировки
+		if action == authConstant.THROTTLE_SIGN_IN && failures == authConstant.THROTTLE_LOCKOUT_ATTEMPTS {
+			r.sendLockoutNotice(key, ip, lockedUntil)
+		}
+
+		return nil
+	}
+
+	if delay := throttleDelay(failures, authConstant.THROTTLE_ACCOUNT_FREE); delay > 0 {
+		return r.lock(authConstant.THROTTLE_SCOPE_ACCOUNT, key, action, currentDate.Add(delay))
+	}
+
+	return nil
+}
+
+/* Сброс счётчика учётной записи после успешного действия (счётчик IP-адреса сохраняется) */
+func (r *ThrottlePostgres) Reset(action, userEmail string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE scope=$1 AND key=$2 AND action=$3", tableConstant.U_AUTH_THROTTLE)
+	_, err := r.db.Exec(query, authConstant.THROTTLE_SCOPE_ACCOUNT, throttleAccountKey(userEmail), action)
+
+	return err
+}
+
+/* Уведомление пользователя о временной блокировке входа (только для существующих учётных записей) */
+func (r *ThrottlePostgres) sendLockoutNotice(key, ip string, lockedUntil time.Time) {
+	var users []userModel.UserModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE LOWER(email)=$1 LIMIT 1", tableConstant.U_USERS)
+	if err := r.db.Select(&users, query, key); err != nil || len(users) <= 0 {
+		return
+	}
+
+	err := smtpService.SendMessage(users[0].Email, smtpService.BuildMessage(email.Mail{
+		Sender:  viper.GetString("smtp.email"),
+		To:      []string{users[0].Email},
+		Subject: "Вход в аккаунт временно заблокирован",
+		Body: fmt.Sprintf(`<html>
+		<head>
+			<meta charset="utf-8" />
+			<title></title>
+		</head>
+		<style>
+			body {background-color: #FEFEF9;}
+			h2   {color: #181511;}
+		</style>
+		<body>
+			<h2>Вход в аккаунт временно заблокирован</h2>
+			<br><text>Зафиксировано слишком много неудачных попыток входа в Ваш аккаунт (последняя попытка с IP-адреса %s).</text>
+			</br><text>Вход будет недоступен до %s.</text>
+			<br><br><br>
+			<text>Если это были не Вы, рекомендуем сменить пароль и подключить двухфакторную аутентификацию.</text>
+		</body>
+	</html>`, ip, lockedUntil.Format("02.01.2006 15:04")),
+	}))
+
+	if err != nil {
+		logrus.Error(err.Error())
+	}
+}
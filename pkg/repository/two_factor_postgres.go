@@ -0,0 +1,683 @@
+package repository
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
+	tableConstant "main-server/pkg/constant/table"
+	userModel "main-server/pkg/model/user"
+	utils "main-server/pkg/util"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+)
+
+/* Неверный код TOTP или код восстановления */
+var ErrInvalidTwoFactorCode = errors.New("Ошибка: неверный код подтверждения!")
+
+type TwoFactorPostgres struct {
+	db       *sqlx.DB
+	enforcer *Enforcer
+	user     *UserPostgres
+}
+
+/* Функция создания нового экземпляра структуры TwoFactorPostgres */
+func NewTwoFactorPostgres(db *sqlx.DB, enforcer *Enforcer, user *UserPostgres) *TwoFactorPostgres {
+	return &TwoFactorPostgres{
+		db:       db,
+		enforcer: enforcer,
+		user:     user,
+	}
+}
+
+/* Хэширование одноразовых значений (коды восстановления, токены второго шага авторизации) */
+func hashOneTimeValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+/* Генерация случайной строки из size байт в шестнадцатеричном виде */
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+/* Приведение кода восстановления к каноничному виду (без разделителей, в нижнем регистре) */
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+/* Получение настроек 2FA пользователя (nil - 2FA не подключалась) */
+func getTwoFactor(db *sqlx.DB, usersId int) (*userModel.TwoFactorDbModel, error) {
+	var items []userModel.TwoFactorDbModel
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE users_id=$1 LIMIT 1", tableConstant.U_TWO_FACTOR)
+	if err := db.Select(&items, query, usersId); err != nil {
+		return nil, err
+	}
+
+	if len(items) <= 0 {
+		return nil, nil
+	}
+
+	return &items[0], nil
+}
+
+/* Проверка, обязательна ли 2FA для одной из ролей пользователя в домене */
+func isTwoFactorRequired(db *sqlx.DB, enforcer *Enforcer, usersId, domainsId int) (bool, error) {
+	roles, err := enforcer.GetRolesForUser(strconv.Itoa(usersId), strconv.Itoa(domainsId))
+	if err != nil {
+		return false, err
+	}
+
+	rolesId := []int{}
+	for _, role := range roles {
+		id, err := strconv.Atoi(role)
+		if err != nil {
+			continue
+		}
+
+		rolesId = append(rolesId, id)
+	}
+
+	if len(rolesId) <= 0 {
+		return false, nil
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE roles_id = ANY($1)", tableConstant.AC_ROLES_2FA)
+	if err := db.Get(&count, query, pq.Array(rolesId)); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+/*
+* Формирование промежуточного токена второго шага авторизации, если он необходим
+* (2FA подключена пользователем или обязательна для его роли). nil - второй шаг не нужен
+ */
+func twoFactorChallengeForLogin(db *sqlx.DB, enforcer *Enforcer, usersId, domainsId int, login twoFactorLogin) (*userModel.TwoFactorChallengeModel, error) {
+	twoFactor, err := getTwoFactor(db, usersId)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := twoFactor != nil && twoFactor.ConfirmedAt != nil
+
+	if !enabled {
+		required, err := isTwoFactorRequired(db, enforcer, usersId, domainsId)
+		if err != nil {
+			return nil, err
+		}
+
+		if !required {
+			return nil, nil
+		}
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	currentDate := time.Now()
+	expiresAt := currentDate.Add(authConstant.TOKEN_TLL_2FA)
+
+	// Просроченные токены пользователя больше не нужны
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1 AND expires_at <= $2", tableConstant.U_TWO_FACTOR_CHALL)
+	if _, err := db.Exec(query, usersId, currentDate); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (users_id, token_hash, created_at, expires_at, auth_types_id, token_api, token_api_refresh)
+		values ($1, $2, $3, $4, $5, $6, $7)`,
+		tableConstant.U_TWO_FACTOR_CHALL,
+	)
+	if _, err := db.Exec(
+		query, usersId, hashOneTimeValue(token), currentDate, expiresAt,
+		login.AuthTypesId, login.TokenApi, login.TokenApiRefresh,
+	); err != nil {
+		return nil, err
+	}
+
+	return &userModel.TwoFactorChallengeModel{
+		ChallengeToken:     token,
+		ExpiresAt:          expiresAt,
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+/*
+* Способ входа, с которого начался первый шаг авторизации: после второго шага сессия создаётся
+* с тем же типом аутентификации и токенами внешнего провайдера
+ */
+type twoFactorLogin struct {
+	AuthTypesId     int
+	TokenApi        *string
+	TokenApiRefresh *string
+}
+
+/* Получение email владельца действующего промежуточного токена (ключ учёта неудачных попыток второго шага) */
+func (r *TwoFactorPostgres) GetChallengeEmail(token string) (string, error) {
+	challenge, err := r.getChallenge(token)
+	if err != nil {
+		return "", err
+	}
+
+	var emails []string
+
+	query := fmt.Sprintf("SELECT email FROM %s WHERE id=$1 LIMIT 1", tableConstant.U_USERS)
+	if err := r.db.Select(&emails, query, challenge.UsersId); err != nil {
+		return "", err
+	}
+
+	if len(emails) <= 0 {
+		return "", errors.New("Ошибка: токен второго шага авторизации недействителен или просрочен!")
+	}
+
+	return emails[0], nil
+}
+
+/* Получение действующего промежуточного токена второго шага авторизации */
+func (r *TwoFactorPostgres) getChallenge(token string) (*userModel.TwoFactorChallengeDbModel, error) {
+	var items []userModel.TwoFactorChallengeDbModel
+
+	query := fmt.Sprintf(
+		"SELECT * FROM %s WHERE token_hash=$1 AND expires_at > $2 AND attempts < $3 LIMIT 1",
+		tableConstant.U_TWO_FACTOR_CHALL,
+	)
+	if err := r.db.Select(&items, query, hashOneTimeValue(token), time.Now(), authConstant.TWO_FACTOR_MAX_ATTEMPTS); err != nil {
+		return nil, err
+	}
+
+	if len(items) <= 0 {
+		return nil, errors.New("Ошибка: токен второго шага авторизации недействителен или просрочен!")
+	}
+
+	return &items[0], nil
+}
+
+/* Проверка кода TOTP с фиксацией использованного временного шага (повторно код не принимается) */
+func verifyTOTPCode(tx *sql.Tx, twoFactor *userModel.TwoFactorDbModel, code string) (bool, error) {
+	counter, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastCounter)
+	if !ok {
+		return false, nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_counter=$1 WHERE id=$2 AND last_counter < $1", tableConstant.U_TWO_FACTOR)
+	result, err := tx.Exec(query, counter, twoFactor.Id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+/* Проверка кода TOTP или одноразового кода восстановления (код восстановления помечается использованным) */
+func verifyTwoFactorCode(tx *sql.Tx, twoFactor *userModel.TwoFactorDbModel, code string) (bool, error) {
+	ok, err := verifyTOTPCode(tx, twoFactor, code)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET used_at=$1 WHERE users_id=$2 AND code_hash=$3 AND used_at IS NULL",
+		tableConstant.U_RECOVERY_CODES,
+	)
+	result, err := tx.Exec(query, time.Now(), twoFactor.UsersId, hashOneTimeValue(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+/* Генерация нового набора кодов восстановления (ранее выданные коды становятся недействительными) */
+func replaceRecoveryCodes(tx *sql.Tx, usersId int) ([]string, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.U_RECOVERY_CODES)
+	if _, err := tx.Exec(query, usersId); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (users_id, code_hash) values ($1, $2)", tableConstant.U_RECOVERY_CODES)
+
+	codes := []string{}
+	for i := 0; i < authConstant.RECOVERY_CODES_COUNT; i++ {
+		value, err := randomHex(5)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(query, usersId, hashOneTimeValue(value)); err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, value[:5]+"-"+value[5:])
+	}
+
+	return codes, nil
+}
+
+/* Создание (или пересоздание) неподтверждённого секрета TOTP пользователя */
+func (r *TwoFactorPostgres) enroll(usersId int) (userModel.TwoFactorEnrollModel, error) {
+	twoFactor, err := getTwoFactor(r.db, usersId)
+	if err != nil {
+		return userModel.TwoFactorEnrollModel{}, err
+	}
+
+	if twoFactor != nil && twoFactor.ConfirmedAt != nil {
+		return userModel.TwoFactorEnrollModel{}, errors.New("Ошибка: двухфакторная аутентификация уже подключена!")
+	}
+
+	user, err := r.user.Get("id", usersId, true)
+	if err != nil {
+		return userModel.TwoFactorEnrollModel{}, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return userModel.TwoFactorEnrollModel{}, err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (users_id, secret, last_counter, created_at) values ($1, $2, 0, $3)
+		ON CONFLICT (users_id) DO UPDATE SET secret=EXCLUDED.secret, last_counter=0, created_at=EXCLUDED.created_at, confirmed_at=NULL`,
+		tableConstant.U_TWO_FACTOR,
+	)
+	if _, err := r.db.Exec(query, usersId, secret, time.Now()); err != nil {
+		return userModel.TwoFactorEnrollModel{}, err
+	}
+
+	issuer := viper.GetString("two_factor.issuer")
+	if issuer == "" {
+		issuer = authConstant.TWO_FACTOR_ISSUER
+	}
+
+	return userModel.TwoFactorEnrollModel{
+		Secret: secret,
+		Uri:    utils.TOTPProvisioningURI(issuer, user.Email, secret),
+	}, nil
+}
+
+/* Начало подключения 2FA: выдача секрета и URI для приложения-аутентификатора */
+func (r *TwoFactorPostgres) Enroll(usersId int) (userModel.TwoFactorEnrollModel, error) {
+	return r.enroll(usersId)
+}
+
+/* Подтверждение подключения 2FA кодом из приложения и выдача кодов восстановления */
+func (r *TwoFactorPostgres) Confirm(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error) {
+	twoFactor, err := getTwoFactor(r.db, usersId)
+	if err != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	if twoFactor == nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, errors.New("Ошибка: подключение двухфакторной аутентификации не начато!")
+	}
+
+	if twoFactor.ConfirmedAt != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, errors.New("Ошибка: двухфакторная аутентификация уже подключена!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	ok, err := verifyTOTPCode(tx, twoFactor, data.Code)
+	if err != nil {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	if !ok {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, ErrInvalidTwoFactorCode
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET confirmed_at=$1 WHERE id=$2", tableConstant.U_TWO_FACTOR)
+	if _, err := tx.Exec(query, time.Now(), twoFactor.Id); err != nil {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	codes, err := replaceRecoveryCodes(tx, usersId)
+	if err != nil {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	return userModel.TwoFactorRecoveryCodesModel{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+/* Отключение 2FA (код TOTP или код восстановления); недоступно, если 2FA обязательна для роли пользователя */
+func (r *TwoFactorPostgres) Disable(user userModel.UserIdentityModel, data userModel.TwoFactorCodeModel) (bool, error) {
+	required, err := isTwoFactorRequired(r.db, r.enforcer, user.UserId, user.DomainId)
+	if err != nil {
+		return false, err
+	}
+
+	if required {
+		return false, errors.New("Ошибка: двухфакторная аутентификация обязательна для роли пользователя!")
+	}
+
+	twoFactor, err := getTwoFactor(r.db, user.UserId)
+	if err != nil {
+		return false, err
+	}
+
+	if twoFactor == nil || twoFactor.ConfirmedAt == nil {
+		return false, errors.New("Ошибка: двухфакторная аутентификация не подключена!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := verifyTwoFactorCode(tx, twoFactor, data.Code)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if !ok {
+		tx.Rollback()
+		return false, ErrInvalidTwoFactorCode
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.U_RECOVERY_CODES)
+	if _, err := tx.Exec(query, user.UserId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE users_id=$1", tableConstant.U_TWO_FACTOR)
+	if _, err := tx.Exec(query, user.UserId); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Перевыпуск кодов восстановления (подтверждается кодом TOTP) */
+func (r *TwoFactorPostgres) RegenerateRecoveryCodes(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error) {
+	twoFactor, err := getTwoFactor(r.db, usersId)
+	if err != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	if twoFactor == nil || twoFactor.ConfirmedAt == nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, errors.New("Ошибка: двухфакторная аутентификация не подключена!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	ok, err := verifyTOTPCode(tx, twoFactor, data.Code)
+	if err != nil {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	if !ok {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := replaceRecoveryCodes(tx, usersId)
+	if err != nil {
+		tx.Rollback()
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return userModel.TwoFactorRecoveryCodesModel{}, err
+	}
+
+	return userModel.TwoFactorRecoveryCodesModel{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+/* Получение состояния 2FA пользователя */
+func (r *TwoFactorPostgres) GetStatus(user userModel.UserIdentityModel) (userModel.TwoFactorStatusModel, error) {
+	twoFactor, err := getTwoFactor(r.db, user.UserId)
+	if err != nil {
+		return userModel.TwoFactorStatusModel{}, err
+	}
+
+	required, err := isTwoFactorRequired(r.db, r.enforcer, user.UserId, user.DomainId)
+	if err != nil {
+		return userModel.TwoFactorStatusModel{}, err
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE users_id=$1 AND used_at IS NULL", tableConstant.U_RECOVERY_CODES)
+	if err := r.db.Get(&count, query, user.UserId); err != nil {
+		return userModel.TwoFactorStatusModel{}, err
+	}
+
+	return userModel.TwoFactorStatusModel{
+		Enabled:            twoFactor != nil && twoFactor.ConfirmedAt != nil,
+		Required:           required,
+		RecoveryCodesCount: count,
+	}, nil
+}
+
+/*
+* Подключение 2FA на втором шаге авторизации (для пользователей, у роли которых 2FA обязательна,
+* но ещё не подключена). Подтверждение происходит при входе через SignIn
+ */
+func (r *TwoFactorPostgres) SetupChallenge(data userModel.TwoFactorChallengeTokenModel) (userModel.TwoFactorEnrollModel, error) {
+	challenge, err := r.getChallenge(data.ChallengeToken)
+	if err != nil {
+		return userModel.TwoFactorEnrollModel{}, err
+	}
+
+	return r.enroll(challenge.UsersId)
+}
+
+/* Второй шаг авторизации: обмен промежуточного токена и кода на пару токенов */
+func (r *TwoFactorPostgres) SignIn(data userModel.TwoFactorSignInModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	challenge, err := r.getChallenge(data.ChallengeToken)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	twoFactor, err := getTwoFactor(r.db, challenge.UsersId)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if twoFactor == nil {
+		return userModel.UserAuthDataModel{}, errors.New("Ошибка: необходимо подключить двухфакторную аутентификацию!")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	var ok bool
+	if twoFactor.ConfirmedAt != nil {
+		ok, err = verifyTwoFactorCode(tx, twoFactor, data.Code)
+	} else {
+		// Первый вход после подключения 2FA через SetupChallenge - подтверждение секрета
+		ok, err = verifyTOTPCode(tx, twoFactor, data.Code)
+		if err == nil && ok {
+			query := fmt.Sprintf("UPDATE %s SET confirmed_at=$1 WHERE id=$2", tableConstant.U_TWO_FACTOR)
+			_, err = tx.Exec(query, time.Now(), twoFactor.Id)
+		}
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if !ok {
+		tx.Rollback()
+
+		query := fmt.Sprintf("UPDATE %s SET attempts=attempts+1 WHERE id=$1", tableConstant.U_TWO_FACTOR_CHALL)
+		if _, err := r.db.Exec(query, challenge.Id); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		return userModel.UserAuthDataModel{}, ErrInvalidTwoFactorCode
+	}
+
+	if err := checkUserBan(r.db, challenge.UsersId); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Промежуточный токен одноразовый
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=$1", tableConstant.U_TWO_FACTOR_CHALL)
+	if _, err := tx.Exec(query, challenge.Id); err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	user, err := r.user.Get("id", challenge.UsersId, true)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Сессия создаётся для того способа входа, с которого начался первый шаг авторизации
+	var authTypes userModel.AuthTypeModel
+	if challenge.AuthTypesId != nil {
+		query = fmt.Sprintf("SELECT * FROM %s WHERE id=$1 LIMIT 1", tableConstant.U_AUTH_TYPES)
+		err = r.db.Get(&authTypes, query, *challenge.AuthTypesId)
+	} else {
+		query = fmt.Sprintf("SELECT * FROM %s WHERE value=$1 LIMIT 1", tableConstant.U_AUTH_TYPES)
+		err = r.db.Get(&authTypes, query, authConstant.AUTH_TYPE_LOCAL)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	tokens, err := startSession(tx, user.Id, user.Uuid, authTypes.Uuid, info, challenge.TokenApi, challenge.TokenApiRefresh)
+	if err != nil {
+		tx.Rollback()
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return tokens, tx.Commit()
+}
+
+/* Получение роли домена по её значению */
+func (r *TwoFactorPostgres) getRole(domainsId int, value string) (int, error) {
+	var rolesId []int
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE value=$1 AND domains_id=$2 LIMIT 1", tableConstant.AC_ROLES)
+	if err := r.db.Select(&rolesId, query, value, domainsId); err != nil {
+		return 0, err
+	}
+
+	if len(rolesId) <= 0 {
+		return 0, errors.New(fmt.Sprintf("Ошибка: роли %s в данном домене не существует!", value))
+	}
+
+	return rolesId[0], nil
+}
+
+/* Получение ролей домена, для которых 2FA обязательна */
+func (r *TwoFactorPostgres) GetRequiredRoles(domainsId int) (userModel.TwoFactorRolesModel, error) {
+	roles := []userModel.TwoFactorRoleModel{}
+
+	query := fmt.Sprintf(
+		`SELECT r.uuid AS role_uuid, r.value, tl.created_at FROM %s tl
+		JOIN %s r ON r.id = tl.roles_id
+		WHERE r.domains_id = $1 ORDER BY r.value`,
+		tableConstant.AC_ROLES_2FA, tableConstant.AC_ROLES,
+	)
+	if err := r.db.Select(&roles, query, domainsId); err != nil {
+		return userModel.TwoFactorRolesModel{}, err
+	}
+
+	return userModel.TwoFactorRolesModel{
+		Roles: roles,
+	}, nil
+}
+
+/* Включение обязательной 2FA для роли */
+func (r *TwoFactorPostgres) RequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error) {
+	rolesId, err := r.getRole(admin.DomainId, data.Value)
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (roles_id, users_id, created_at) values ($1, $2, $3) ON CONFLICT (roles_id) DO NOTHING",
+		tableConstant.AC_ROLES_2FA,
+	)
+	if _, err := r.db.Exec(query, rolesId, admin.UserId, time.Now()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* Отключение обязательной 2FA для роли */
+func (r *TwoFactorPostgres) UnrequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error) {
+	rolesId, err := r.getRole(admin.DomainId, data.Value)
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE roles_id=$1", tableConstant.AC_ROLES_2FA)
+	result, err := r.db.Exec(query, rolesId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	authConstant "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestOAuth2LoginRequiresTwoFactor(t *testing.T) {
+	db := newTestDB(t)
+	enforcer := newTestEnforcer(t)
+
+	db.MustExec(`INSERT INTO ac_domains (id, value) VALUES (1, 'rental')`)
+	db.MustExec(`INSERT INTO ac_roles (id, value, domains_id) VALUES (10, 'ROLE_CLIENT', 1)`)
+	db.MustExec(`INSERT INTO u_auth_types (id, value) VALUES (1, $1), (2, $2)`, authConstant.AUTH_TYPE_LOCAL, authConstant.AUTH_TYPE_GOOGLE)
+	db.MustExec(`INSERT INTO u_users (id, email, password) VALUES (5, 'user@example.com', '')`)
+	db.MustExec(`INSERT INTO u_users_auth_types (users_id, auth_types_id, subject, email) VALUES (5, 2, 'google-subject', 'user@example.com')`)
+	db.MustExec(`INSERT INTO u_two_factor (users_id, secret, confirmed_at) VALUES (5, 'secret', CURRENT_TIMESTAMP)`)
+
+	if _, err := enforcer.AddRoleForUserInDomain("5", "10", "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	auth := NewAuthPostgres(db, enforcer, UserPostgres{})
+
+	data, err := auth.loginUserOAuth2(
+		userModel.UserRegisterOAuth2Model{Subject: "google-subject", Email: "user@example.com"},
+		&oauth2.Token{AccessToken: "provider-access", RefreshToken: "provider-refresh"},
+		authConstant.AUTH_TYPE_GOOGLE,
+		userModel.SessionInfoModel{DomainsId: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Challenge == nil || data.AccessToken != "" {
+		t.Fatalf("expected a two-factor challenge instead of a session, got %+v", data)
+	}
+
+	var challenge userModel.TwoFactorChallengeDbModel
+	if err := db.Get(&challenge, `SELECT * FROM u_two_factor_challenges WHERE users_id = 5`); err != nil {
+		t.Fatal(err)
+	}
+
+	if challenge.AuthTypesId == nil || *challenge.AuthTypesId != 2 {
+		t.Fatalf("expected challenge for auth type 2, got %v", challenge.AuthTypesId)
+	}
+
+	if challenge.TokenApi == nil || *challenge.TokenApi != "provider-access" {
+		t.Fatalf("expected provider token to be kept until the second step, got %v", challenge.TokenApi)
+	}
+}
@@ -3,12 +3,22 @@ package repository
 import (
 	"errors"
 	"fmt"
+	objectConstant "main-server/pkg/constant/object"
 	tableConstant "main-server/pkg/constant/table"
 	rbacModel "main-server/pkg/model/rbac"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
 
+/* Типы объектов, на значения которых опирается приложение: их нельзя удалить или переименовать */
+var systemTypesObjects = []string{
+	objectConstant.COMPANY,
+	objectConstant.PROJECT,
+	objectConstant.ENTITY,
+	objectConstant.SUB_ENTITY,
+}
+
 type TypeObjectPostgres struct {
 	db *sqlx.DB
 }
@@ -46,3 +56,137 @@ func (r *TypeObjectPostgres) Get(column string, value interface{}, check bool) (
 
 	return &types[len(types)-1], err
 }
+
+/* Проверка принадлежности значения к системным типам объектов */
+func isSystemTypeObject(value string) bool {
+	for _, item := range systemTypesObjects {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+/* Проверка существования таблицы, в которой хранятся объекты данного типа */
+func (r *TypeObjectPostgres) checkTableName(tableName string) error {
+	var exists bool
+
+	query := "SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1)"
+	if err := r.db.Get(&exists, query, tableName); err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("Таблица %s не найдена!", tableName)
+	}
+
+	return nil
+}
+
+/* Получение списка типов объектов */
+func (r *TypeObjectPostgres) GetTypes() (rbacModel.TypeObjectsModel, error) {
+	types := []rbacModel.TypeObjectDbModel{}
+
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY id", tableConstant.AC_TYPES_OBJECTS)
+	if err := r.db.Select(&types, query); err != nil {
+		return rbacModel.TypeObjectsModel{}, err
+	}
+
+	return rbacModel.TypeObjectsModel{Types: types}, nil
+}
+
+/* Регистрация нового типа объектов с таблицей, в которой они хранятся */
+func (r *TypeObjectPostgres) CreateType(usersId int, data rbacModel.TypeObjectCreateModel) (rbacModel.TypeObjectDbModel, error) {
+	value := strings.TrimSpace(data.Value)
+	if err := checkRbacValue(value); err != nil {
+		return rbacModel.TypeObjectDbModel{}, err
+	}
+
+	tableName := strings.TrimSpace(data.TableName)
+	if err := r.checkTableName(tableName); err != nil {
+		return rbacModel.TypeObjectDbModel{}, err
+	}
+
+	exists, err := r.Get("value", value, false)
+	if err != nil {
+		return rbacModel.TypeObjectDbModel{}, err
+	}
+
+	if exists != nil {
+		return rbacModel.TypeObjectDbModel{}, errors.New("Тип объекта с данным значением уже существует!")
+	}
+
+	var result rbacModel.TypeObjectDbModel
+
+	query := fmt.Sprintf("INSERT INTO %s (value, description, table_name, users_id) values ($1, $2, $3, $4) RETURNING *", tableConstant.AC_TYPES_OBJECTS)
+	if err := r.db.Get(&result, query, value, data.Description, tableName, usersId); err != nil {
+		return rbacModel.TypeObjectDbModel{}, err
+	}
+
+	return result, nil
+}
+
+/* Изменение описания или таблицы типа объектов (таблица системных типов не изменяется) */
+func (r *TypeObjectPostgres) UpdateType(data rbacModel.TypeObjectUpdateModel) (rbacModel.TypeObjectDbModel, error) {
+	item, err := r.Get("value", strings.TrimSpace(data.Value), true)
+	if err != nil {
+		return rbacModel.TypeObjectDbModel{}, err
+	}
+
+	tableName := item.TableName
+	if data.TableName != nil && strings.TrimSpace(*data.TableName) != item.TableName {
+		if item.Value != nil && isSystemTypeObject(*item.Value) {
+			return rbacModel.TypeObjectDbModel{}, errors.New("Таблицу системного типа объектов изменить нельзя!")
+		}
+
+		tableName = strings.TrimSpace(*data.TableName)
+		if err := r.checkTableName(tableName); err != nil {
+			return rbacModel.TypeObjectDbModel{}, err
+		}
+	}
+
+	description := item.Description
+	if data.Description != nil {
+		description = *data.Description
+	}
+
+	var result rbacModel.TypeObjectDbModel
+
+	query := fmt.Sprintf("UPDATE %s SET description=$1, table_name=$2 WHERE id=$3 RETURNING *", tableConstant.AC_TYPES_OBJECTS)
+	if err := r.db.Get(&result, query, description, tableName, item.Id); err != nil {
+		return rbacModel.TypeObjectDbModel{}, err
+	}
+
+	return result, nil
+}
+
+/* Удаление типа объектов, для которого не создано ни одного объекта */
+func (r *TypeObjectPostgres) DeleteType(data rbacModel.TypeObjectValueModel) (bool, error) {
+	item, err := r.Get("value", strings.TrimSpace(data.Value), true)
+	if err != nil {
+		return false, err
+	}
+
+	if item.Value != nil && isSystemTypeObject(*item.Value) {
+		return false, errors.New("Системный тип объектов удалить нельзя!")
+	}
+
+	var used bool
+
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE types_objects_id=$1)", tableConstant.AC_OBJECTS)
+	if err := r.db.Get(&used, query, item.Id); err != nil {
+		return false, err
+	}
+
+	if used {
+		return false, errors.New("Существуют объекты данного типа, тип не может быть удалён!")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id=$1", tableConstant.AC_TYPES_OBJECTS)
+	if _, err := r.db.Exec(query, item.Id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -11,19 +11,17 @@ import (
 	companyModel "main-server/pkg/model/company"
 	rbacModel "main-server/pkg/model/rbac"
 	userModel "main-server/pkg/model/user"
+	passwordService "main-server/pkg/service/password"
 	"strconv"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"github.com/samber/lo"
-	"github.com/spf13/viper"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserPostgres struct {
 	db       *sqlx.DB
-	enforcer *casbin.Enforcer
+	enforcer *Enforcer
 	domain   *DomainPostgres
 	role     *RolePostgres
 }
@@ -32,7 +30,7 @@ type UserPostgres struct {
 * Функция создания экземпляра сервиса
  */
 func NewUserPostgres(
-	db *sqlx.DB, enforcer *casbin.Enforcer,
+	db *sqlx.DB, enforcer *Enforcer,
 	domain *DomainPostgres, role *RolePostgres,
 ) *UserPostgres {
 	return &UserPostgres{
@@ -100,11 +98,26 @@ func (r *UserPostgres) GetProfile(c *gin.Context) (userModel.UserProfileModel, e
 func (r *UserPostgres) UpdateProfile(c *gin.Context, data userModel.UserProfileUpdateDataModel) (userModel.UserJSONBModel, error) {
 	usersId, _ := c.Get(middlewareConstant.USER_CTX)
 
-	userJsonb, err := json.Marshal(data)
+	// Пароль хранится только в виде хэша в u_users и в данные профиля не попадает
+	userJsonb, err := json.Marshal(data.ProfileData())
 	if err != nil {
 		return userModel.UserJSONBModel{}, err
 	}
 
+	// Смена пароля доступна только при наличии локального входа (иначе пароль устанавливается отдельно)
+	if data.Password != nil {
+		id, _ := usersId.(int)
+
+		hasPassword, err := hasLocalIdentity(r.db, id)
+		if err != nil {
+			return userModel.UserJSONBModel{}, err
+		}
+
+		if !hasPassword {
+			return userModel.UserJSONBModel{}, errors.New("Пароль для аккаунта не установлен! Воспользуйтесь установкой пароля в профиле")
+		}
+	}
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return userModel.UserJSONBModel{}, err
@@ -144,14 +157,14 @@ func (r *UserPostgres) UpdateProfile(c *gin.Context, data userModel.UserProfileU
 
 	// Change password
 	if data.Password != nil {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*data.Password), viper.GetInt("crypt.cost"))
+		hashedPassword, err := passwordService.Hash(*data.Password)
 		if err != nil {
 			tx.Rollback()
 			return userModel.UserJSONBModel{}, err
 		}
 
 		query := fmt.Sprintf("UPDATE %s SET password=$1 WHERE id=$2", tableConstant.U_USERS)
-		_, err = r.db.Exec(query, string(hashedPassword), usersId)
+		_, err = r.db.Exec(query, hashedPassword, usersId)
 
 		if err != nil {
 			tx.Rollback()
@@ -180,12 +193,13 @@ func (r *UserPostgres) GetUserCompany(userId, domainId int) (companyModel.Compan
 					AND trule.v0 = $2 
 					AND trule.ptype = 'p'
 					AND (trule.v3 = $3 OR trule.v3 = $4)
+					AND trule.v4 = $5
 	`, tableConstant.AC_OBJECTS, tableConstant.AC_TYPES_OBJECTS, tableConstant.AC_RULES)
 
 	var companies []companyModel.CompanyRuleModelEx
 	err := r.db.Select(&companies, query,
 		objectConstant.COMPANY, userId,
-		actionConstant.ADMINISTRATION, actionConstant.MANAGEMENT,
+		actionConstant.ADMINISTRATION, actionConstant.MANAGEMENT, actionConstant.ALLOW,
 	)
 
 	if err != nil {
@@ -197,10 +211,11 @@ func (r *UserPostgres) GetUserCompany(userId, domainId int) (companyModel.Compan
 	}
 
 	company := companies[len(companies)-1]
-	policies := r.enforcer.GetFilteredPolicy(0, strconv.Itoa(userId), strconv.Itoa(domainId), company.Value)
+	policies := r.enforcer.GetFilteredPolicy(0, strconv.Itoa(userId), strconv.Itoa(domainId), company.Value, "", actionConstant.ALLOW)
 
+	// Правило: субъект, домен, объект, действие, эффект
 	rules := lo.Map(policies, func(x []string, _ int) string {
-		return x[len(x)-1]
+		return x[3]
 	})
 
 	var companyInfo companyModel.CompanyInfoModel
@@ -227,7 +242,7 @@ func (r *UserPostgres) GetUserCompany(userId, domainId int) (companyModel.Compan
 
 /* Проверка доступа пользователя */
 func (r *UserPostgres) AccessCheck(userId, domainId int, value rbacModel.RoleValueModel) (bool, error) {
-	role, err := r.role.Get("value", value.Value, true)
+	role, err := r.role.GetInDomain(value.Value, domainId, true)
 	if err != nil {
 		return false, err
 	}
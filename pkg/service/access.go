@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	rbacModel "main-server/pkg/model/rbac"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса объяснения решений о доступе */
+type AccessService struct {
+	repo repository.Access
+	user repository.User
+}
+
+/* Создание нового экземпляра структуры */
+func NewAccessService(repo repository.Access, user repository.User) *AccessService {
+	return &AccessService{
+		repo: repo,
+		user: user,
+	}
+}
+
+/* Объект не существует или у пользователя нет правил для него (ответы не различаются) */
+var ErrAccessObjectNotFound = errors.New("Ошибка: объект не найден!")
+
+/*
+* Объяснение решения о доступе текущего пользователя к объекту. Объект, на который (и на родительские ресурсы
+* которого) у пользователя нет правил, не раскрывается, а цепочка родительских ресурсов возвращается только
+* при разрешённом доступе: иначе по ответам можно восстановить дерево объектов
+ */
+func (s *AccessService) Explain(usersId, domainsId int, data rbacModel.AccessExplainModel) (rbacModel.AccessExplainResultModel, error) {
+	result, err := s.repo.Explain(usersId, domainsId, data)
+	if err != nil {
+		return rbacModel.AccessExplainResultModel{}, err
+	}
+
+	if !result.Allowed && len(result.Rules) <= 0 {
+		return rbacModel.AccessExplainResultModel{}, ErrAccessObjectNotFound
+	}
+
+	if !result.Allowed {
+		result.Objects = nil
+	}
+
+	return result, nil
+}
+
+/* Объяснение решения о доступе произвольного пользователя (по uuid) к объекту */
+func (s *AccessService) ExplainForUser(domainsId int, data rbacModel.AccessExplainUserModel) (rbacModel.AccessExplainResultModel, error) {
+	user, err := s.user.Get("uuid", data.UserUuid, true)
+	if err != nil {
+		return rbacModel.AccessExplainResultModel{}, err
+	}
+
+	return s.repo.Explain(user.Id, domainsId, data.AccessExplainModel)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"main-server/config"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/* Каталог, за пределами которого файлы в выгрузку не попадают */
+const accountFilesRoot = "public"
+
+/* Структура сервиса */
+type AccountService struct {
+	repo repository.Account
+}
+
+/* Создание нового экземпляра структуры */
+func NewAccountService(repo repository.Account) *AccountService {
+	return &AccountService{
+		repo: repo,
+	}
+}
+
+/* Выгрузка персональных данных пользователя в формате JSON */
+func (s *AccountService) GetExport(usersId int) (userModel.AccountExportModel, error) {
+	return s.repo.GetExportData(usersId)
+}
+
+/* Открытие загруженного пользователем файла (только внутри каталога accountFilesRoot) */
+func openAccountFile(path string) (*os.File, error) {
+	path = filepath.Clean(path)
+	if path != accountFilesRoot && !strings.HasPrefix(path, accountFilesRoot+string(filepath.Separator)) {
+		return nil, os.ErrNotExist
+	}
+
+	return os.Open(path)
+}
+
+/* Выгрузка персональных данных в ZIP-архиве: account.json и загруженные пользователем файлы (files/) */
+func (s *AccountService) GetExportArchive(usersId int) ([]byte, error) {
+	data, err := s.repo.GetExportData(usersId)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	archive := zip.NewWriter(buffer)
+
+	for index := range data.Files {
+		file, err := openAccountFile(data.Files[index].Filepath)
+		if err != nil {
+			// Отсутствующий на диске файл указывается в account.json без пути в архиве
+			continue
+		}
+
+		filename := filepath.Base(data.Files[index].Filename)
+		if filename == "." || filename == string(filepath.Separator) {
+			filename = filepath.Base(data.Files[index].Filepath)
+		}
+
+		name := fmt.Sprintf("files/%d_%s", index+1, filename)
+
+		writer, err := archive.Create(name)
+		if err == nil {
+			_, err = io.Copy(writer, file)
+		}
+		file.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		data.Files[index].Archive = name
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := archive.Create("account.json")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+/* Получение состояния запроса на удаление аккаунта */
+func (s *AccountService) GetDeletion(usersId int) (userModel.AccountDeletionModel, error) {
+	return s.repo.GetDeletion(usersId)
+}
+
+/* Запрос на удаление аккаунта по истечении периода ожидания */
+func (s *AccountService) RequestDeletion(usersId int, data userModel.AccountDeleteModel) (userModel.AccountDeletionModel, error) {
+	return s.repo.RequestDeletion(usersId, data.Password)
+}
+
+/* Отмена запроса на удаление аккаунта */
+func (s *AccountService) CancelDeletion(usersId int) (bool, error) {
+	return s.repo.CancelDeletion(usersId)
+}
+
+/* Запуск фоновой задачи удаления аккаунтов, период ожидания которых истёк */
+func (s *AccountService) StartPurge() {
+	go func() {
+		ticker := time.NewTicker(config.AppAccountConfig.PurgeInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			count, err := s.repo.PurgeAccounts()
+			if err != nil {
+				logrus.Errorf("error purging deleted accounts: %s", err.Error())
+				continue
+			}
+
+			if count > 0 {
+				logrus.Printf("deleted accounts: %d", count)
+			}
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	articleModel "main-server/pkg/model/article"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type ArticleService struct {
+	repo repository.Article
+}
+
+/* Function for create new struct of ArticleService */
+func NewArticleService(repo repository.Article) *ArticleService {
+	return &ArticleService{
+		repo: repo,
+	}
+}
+
+/* Method for create new article */
+func (s *ArticleService) CreateArticle(user userModel.UserIdentityModel, data articleModel.ArticleCreateRequestModel) (articleModel.ArticleModel, error) {
+	return s.repo.CreateArticle(user, data)
+}
+
+/* Method for update article */
+func (s *ArticleService) UpdateArticle(user userModel.UserIdentityModel, data articleModel.ArticleUpdateRequestModel) (articleModel.ArticleModel, error) {
+	return s.repo.UpdateArticle(user, data)
+}
+
+/* Method for delete article */
+func (s *ArticleService) DeleteArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.DeleteArticle(user, data)
+}
+
+/* Method for get article of user */
+func (s *ArticleService) GetUserArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error) {
+	return s.repo.GetUserArticle(user, data)
+}
+
+/* Method for get any count articles of user */
+func (s *ArticleService) GetUserArticles(user userModel.UserIdentityModel, data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetUserArticles(user, data)
+}
+
+/* Method for get any count articles waiting for moderation */
+func (s *ArticleService) GetUncheckedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetUncheckedArticles(data)
+}
+
+/* Method for approve or reject article */
+func (s *ArticleService) ModerateArticle(data articleModel.ArticleModerationModel, status string) (articleModel.ArticleSuccessModel, error) {
+	return s.repo.ModerateArticle(data, status)
+}
+
+/* Method for get approved article */
+func (s *ArticleService) GetApprovedArticle(data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error) {
+	return s.repo.GetApprovedArticle(data)
+}
+
+/* Method for get any count approved articles */
+func (s *ArticleService) GetApprovedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error) {
+	return s.repo.GetApprovedArticles(data)
+}
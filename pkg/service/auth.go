@@ -2,8 +2,13 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	authConstant "main-server/pkg/constant/auth"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	passwordService "main-server/pkg/service/password"
+	"math"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
@@ -12,20 +17,54 @@ import (
 /* Structure for current repository */
 type AuthService struct {
 	repo         repository.Authorization
+	throttle     repository.Throttle
 	tokenService TokenService
 }
 
 /* Function for create a new repository */
-func NewAuthService(repo repository.Authorization, tokenService TokenService) *AuthService {
+func NewAuthService(repo repository.Authorization, throttle repository.Throttle, tokenService TokenService) *AuthService {
 	return &AuthService{
 		repo:         repo,
+		throttle:     throttle,
 		tokenService: tokenService,
 	}
 }
 
+/* Error returned when the number of failed attempts is exceeded (RetryAfter - time until the restriction is lifted) */
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("Слишком много неудачных попыток! Повторите попытку через %d сек.", e.Seconds())
+}
+
+/* Time until the restriction is lifted, rounded up to seconds */
+func (e *TooManyAttemptsError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+/* Checking the backoff / lockout for the account and IP address */
+func checkThrottle(throttle repository.Throttle, action, email, ip string) error {
+	wait, err := throttle.Check(action, email, ip)
+	if err != nil {
+		return err
+	}
+
+	if wait > 0 {
+		return &TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
 /* Create user */
-func (s *AuthService) CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error) {
-	return s.repo.CreateUser(user)
+func (s *AuthService) CreateUser(user userModel.UserRegisterModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	if err := passwordService.Validate(user.Password); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return s.repo.CreateUser(user, info)
 }
 
 /* Upload profile image */
@@ -33,30 +72,63 @@ func (s *AuthService) UploadProfileImage(c *gin.Context, filepath string) (bool,
 	return s.repo.UploadProfileImage(c, filepath)
 }
 
-/* Login user */
-func (s *AuthService) LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error) {
-	return s.repo.LoginUser(user)
+/* Login user (failed attempts are counted per account and per IP address) */
+func (s *AuthService) LoginUser(user userModel.UserLoginModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	if err := checkThrottle(s.throttle, authConstant.THROTTLE_SIGN_IN, user.Email, info.Ip); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	data, err := s.repo.LoginUser(user, info)
+	if errors.Is(err, repository.ErrInvalidCredentials) {
+		if err := s.throttle.Fail(authConstant.THROTTLE_SIGN_IN, user.Email, info.Ip); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	// Счётчик сбрасывается только после второго шага авторизации (если он требуется)
+	if data.Challenge != nil {
+		return data, nil
+	}
+
+	return data, s.throttle.Reset(authConstant.THROTTLE_SIGN_IN, user.Email)
+}
+
+/* Login user with external OAuth2 provider (Google, VK, OpenID Connect) */
+func (s *AuthService) LoginUserOAuth2(provider, code, state string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.LoginUserOAuth2(provider, code, state, info)
+}
+
+/* Login user with an authorization code obtained by the client through the provider SDK (without state) */
+func (s *AuthService) LoginUserOAuth2SDK(provider, code string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	return s.repo.LoginUserOAuth2SDK(provider, code, info)
 }
 
-/* Login user with Google OAuth2 */
-func (s *AuthService) LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error) {
-	return s.repo.LoginUserOAuth2(code)
+/* Getting the provider authorization URL (with state, PKCE and nonce) */
+func (s *AuthService) GetOAuth2URL(provider string) (string, error) {
+	return s.repo.GetOAuth2URL(provider)
 }
 
 /**
  * Функция для обновления токена доступа по токену обновления
  * @param {userModel.TokenLogoutDataModel} data - Подробная информация об авторизационной информации пользователя
  * @param {string} refreshToken - Токен обновления
+ * @param {userModel.SessionInfoModel} info - Данные об устройстве пользователя
  * @returns {userModel.UserAuthDataModel, error} Пара токенов (access и refresh) или ошибка
  */
-func (s *AuthService) Refresh(data userModel.TokenLogoutDataModel, refreshToken string) (userModel.UserAuthDataModel, error) {
-	token, err := s.tokenService.ParseTokenWithoutValid(refreshToken, viper.GetString("token.signing_key_refresh"))
+func (s *AuthService) Refresh(data userModel.TokenLogoutDataModel, refreshToken string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	token, err := s.tokenService.ParseRefreshTokenWithoutValid(refreshToken, viper.GetString("token.signing_key_refresh"))
 
 	if err != nil {
 		return userModel.UserAuthDataModel{}, err
 	}
 
-	return s.repo.Refresh(data, refreshToken, token)
+	return s.repo.Refresh(data, refreshToken, token, info)
 }
 
 /* Logout user */
@@ -69,18 +141,74 @@ func (s *AuthService) Activate(link string) (bool, error) {
 	return s.repo.Activate(link)
 }
 
-/* Recover password */
-func (s *AuthService) RecoveryPassword(email string) (bool, error) {
+/* Checking whether the user's account is activated */
+func (s *AuthService) CheckActivation(usersId int) (bool, error) {
+	return s.repo.CheckActivation(usersId)
+}
+
+/* Resending the account activation email (no more often than once per cooldown interval) */
+func (s *AuthService) ResendActivation(email, ip string) (bool, error) {
+	// Учёт ведётся по email независимо от существования учётной записи
+	if err := checkThrottle(s.throttle, authConstant.THROTTLE_ACTIVATION, email, ip); err != nil {
+		return false, err
+	}
+
+	if err := s.throttle.Fail(authConstant.THROTTLE_ACTIVATION, email, ip); err != nil {
+		return false, err
+	}
+
+	return s.repo.ResendActivation(email)
+}
+
+/* Recover password (every request is counted, since each one sends an email) */
+func (s *AuthService) RecoveryPassword(email, ip string) (bool, error) {
+	if err := checkThrottle(s.throttle, authConstant.THROTTLE_RECOVERY, email, ip); err != nil {
+		return false, err
+	}
+
+	if err := s.throttle.Fail(authConstant.THROTTLE_RECOVERY, email, ip); err != nil {
+		return false, err
+	}
+
 	return s.repo.RecoveryPassword(email)
 }
 
 /* Reset password */
-func (s *AuthService) ResetPassword(data userModel.ResetPasswordModel) (bool, error) {
+func (s *AuthService) ResetPassword(data userModel.ResetPasswordModel, ip string) (bool, error) {
+	if err := passwordService.Validate(data.Password); err != nil {
+		return false, err
+	}
+
+	if err := checkThrottle(s.throttle, authConstant.THROTTLE_RESET, "", ip); err != nil {
+		return false, err
+	}
+
 	token, err := s.tokenService.ParseResetToken(data.Token, viper.GetString("token.signing_key_reset"))
 
 	if err != nil {
-		return false, errors.New("Некорректный токен сброса пароля")
+		if err := s.throttle.Fail(authConstant.THROTTLE_RESET, "", ip); err != nil {
+			return false, err
+		}
+
+		return false, repository.ErrInvalidResetToken
+	}
+
+	if err := checkThrottle(s.throttle, authConstant.THROTTLE_RESET, token.Email, ip); err != nil {
+		return false, err
+	}
+
+	result, err := s.repo.ResetPassword(data, token)
+	if errors.Is(err, repository.ErrInvalidResetToken) {
+		if err := s.throttle.Fail(authConstant.THROTTLE_RESET, token.Email, ip); err != nil {
+			return false, err
+		}
+
+		return false, err
+	}
+
+	if err != nil {
+		return false, err
 	}
 
-	return s.repo.ResetPassword(data, token)
+	return result, s.throttle.Reset(authConstant.THROTTLE_RESET, token.Email)
 }
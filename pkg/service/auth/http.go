@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+/* Максимальное время запроса к провайдеру аутентификации */
+const providerRequestTimeout = 15 * time.Second
+
+/* HTTP-клиент для запросов к провайдерам (http.DefaultClient не ограничивает время ожидания ответа) */
+var httpClient = &http.Client{Timeout: providerRequestTimeout}
+
+/* Контекст с HTTP-клиентом провайдеров для запросов библиотеки oauth2 (обмен кода, обновление токена) */
+func withHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+}
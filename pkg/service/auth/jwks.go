@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Время жизни кэша ключей
+	jwksCacheTTL = 1 * time.Hour
+
+	// Минимальный интервал между повторными запросами ключей (при неизвестном kid)
+	jwksRefreshInterval = 1 * time.Minute
+)
+
+/* Ключ из набора JWKS */
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+/* Кэш открытых ключей издателя (JWKS) */
+type jwksCache struct {
+	mu        sync.Mutex
+	url       string
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func newJWKSCache(url string) *jwksCache {
+	return &jwksCache{
+		url:  url,
+		keys: map[string]interface{}{},
+	}
+}
+
+/* Получение открытого ключа по идентификатору kid */
+func (c *jwksCache) get(kid string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expired := time.Since(c.fetchedAt) > jwksCacheTTL
+	key, ok := c.keys[kid]
+
+	// Если ключ не найден (ротация ключей у издателя), набор запрашивается повторно
+	if expired || (!ok && time.Since(c.fetchedAt) > jwksRefreshInterval) {
+		if err := c.fetch(); err != nil {
+			return nil, err
+		}
+
+		key, ok = c.keys[kid]
+	}
+
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("Ошибка: ключ %s не найден в JWKS издателя!", kid))
+	}
+
+	return key, nil
+}
+
+func (c *jwksCache) fetch() error {
+	response, err := httpClient.Get(c.url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("Ошибка: JWKS вернул статус %d!", response.StatusCode))
+	}
+
+	var data struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err = json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return err
+	}
+
+	keys := map[string]interface{}{}
+	for _, item := range data.Keys {
+		if item.Use != "" && item.Use != "sig" {
+			continue
+		}
+
+		key, err := item.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[item.Kid] = key
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+
+	return nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+/* Преобразование JWK в открытый ключ RSA или ECDSA */
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("unsupported curve " + k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, errors.New("unsupported key type " + k.Kty)
+}
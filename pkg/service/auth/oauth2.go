@@ -3,9 +3,9 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	route "main-server/pkg/constant/route"
 	userModel "main-server/pkg/model/user"
-	"net/http"
 
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
@@ -15,41 +15,59 @@ type VerifyEmailModel struct {
 	VerifyEmail bool `json:"verified_email" binding:"required"`
 }
 
-func RefreshAccessToken(c context.Context, refreshToken string) (userModel.TokenDataModel, error) {
-	url := route.OAUTH2_REFRESH_TOKEN_ROUTE + viper.GetString("oauth2.client_id")
-	url = url + "&client_secret=" + viper.GetString("oauth2.client_secret")
-	url = url + "&refresh_token=" + refreshToken + "&grant_type=refresh_token"
+/* Провайдер аутентификации Google OAuth2 */
+type GoogleProvider struct {
+	config       oauth2.Config
+	tokenInfoURL string
+	userInfoURL  string
+	revokeURL    string
+}
 
-	response, err := http.Post(url, "application/x-www-form-urlencoded", nil)
+/* Создание провайдера Google OAuth2 (адреса API могут быть переопределены в конфигурации) */
+func NewGoogleProvider(config oauth2.Config) *GoogleProvider {
+	provider := &GoogleProvider{
+		config:       config,
+		tokenInfoURL: route.OAUTH2_TOKEN_INFO_ROUTE,
+		userInfoURL:  route.OAUTH2_USER_INFO_ROUTE,
+		revokeURL:    route.OAUTH2_REVOKE_TOKEN_ROUTE,
+	}
 
-	var j userModel.TokenDataModel
+	if value := viper.GetString("oauth2.token_info_url"); value != "" {
+		provider.tokenInfoURL = value
+	}
 
-	err = json.NewDecoder(response.Body).Decode(&j)
+	if value := viper.GetString("oauth2.user_info_url"); value != "" {
+		provider.userInfoURL = value
+	}
 
-	if err != nil {
-		return userModel.TokenDataModel{}, err
+	if value := viper.GetString("oauth2.revoke_url"); value != "" {
+		provider.revokeURL = value
 	}
 
-	return j, nil
+	return provider
 }
 
-/*func RefreshToken(c context.Context, token *oauth2.Token) (*oauth2.Token, error) {
-	tokenSource := configs.AppOAuth2Config.GoogleLogin.TokenSource(c, token)
-	newToken, err := tokenSource.Token()
-
-	if err != nil {
-		return nil, err
-	}
+func (p *GoogleProvider) AuthCodeURL(state string, params AuthParams) (string, error) {
+	return p.config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", codeChallengeS256(params.CodeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
 
-	if newToken.AccessToken != token.AccessToken {
-		return newToken, nil
+func (p *GoogleProvider) Exchange(ctx context.Context, code string, params *AuthParams) (*oauth2.Token, error) {
+	if params == nil {
+		return p.config.Exchange(withHTTPClient(ctx), code)
 	}
 
-	return token, nil
-}*/
+	return p.config.Exchange(withHTTPClient(ctx), code, oauth2.SetAuthURLParam("code_verifier", params.CodeVerifier))
+}
 
-func GetInfoToken(accessToken string) (interface{}, error) {
-	response, err := http.Get(route.OAUTH2_TOKEN_INFO_ROUTE + accessToken)
+func (p *GoogleProvider) GetInfoToken(accessToken string) (interface{}, error) {
+	response, err := httpClient.Get(p.tokenInfoURL + accessToken)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
 
 	var j interface{}
 
@@ -62,8 +80,12 @@ func GetInfoToken(accessToken string) (interface{}, error) {
 	return j, nil
 }
 
-func VerifyAccessToken(accessToken string) (bool, error) {
-	response, err := http.Get(route.OAUTH2_TOKEN_INFO_ROUTE + accessToken)
+func (p *GoogleProvider) VerifyAccessToken(accessToken string) (bool, error) {
+	response, err := httpClient.Get(p.tokenInfoURL + accessToken)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
 
 	var j VerifyEmailModel
 
@@ -76,8 +98,12 @@ func VerifyAccessToken(accessToken string) (bool, error) {
 	return j.VerifyEmail, nil
 }
 
-func GetUserInfo(token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
-	response, err := http.Get(route.OAUTH2_USER_INFO_ROUTE + token.AccessToken)
+func (p *GoogleProvider) GetUserInfo(token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
+	response, err := httpClient.Get(p.userInfoURL + token.AccessToken)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+	defer response.Body.Close()
 
 	var data userModel.UserRegisterOAuth2Model
 
@@ -87,18 +113,27 @@ func GetUserInfo(token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error)
 		return userModel.UserRegisterOAuth2Model{}, err
 	}
 
+	if data.Subject == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: Google не вернул идентификатор пользователя!")
+	}
+
 	return data, nil
 }
 
-func RevokeToken(accessToken string) (bool, error) {
-	response, err := http.Post(
-		route.OAUTH2_REVOKE_TOKEN_ROUTE+accessToken,
-		"Content-type:application/x-www-form-urlencoded",
+func (p *GoogleProvider) RefreshToken(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	return p.config.TokenSource(withHTTPClient(ctx), &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+}
+
+func (p *GoogleProvider) RevokeToken(accessToken string) (bool, error) {
+	response, err := httpClient.Post(
+		p.revokeURL+accessToken,
+		"application/x-www-form-urlencoded",
 		nil)
 
 	if err != nil {
 		return false, err
 	}
+	defer response.Body.Close()
 
 	return (response.StatusCode == 200), nil
 }
@@ -0,0 +1,386 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"main-server/config"
+	userModel "main-server/pkg/model/user"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"golang.org/x/oauth2"
+)
+
+/* Допустимое расхождение часов с издателем при проверке ID-токена */
+const idTokenLeeway = 1 * time.Minute
+
+/* Документ .well-known/openid-configuration */
+type oidcDiscoveryModel struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	RevocationEndpoint    string `json:"revocation_endpoint"`
+}
+
+/* Стандартные claims ID-токена и ответа userinfo OpenID Connect */
+type oidcUserInfoModel struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     *bool  `json:"email_verified"`
+	FamilyName        string `json:"family_name"`
+	GivenName         string `json:"given_name"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+/* Провайдер аутентификации OpenID Connect */
+type OIDCProvider struct {
+	mu          sync.Mutex
+	discovered  bool
+	config      oauth2.Config
+	issuer      string
+	userInfoURL string
+	revokeURL   string
+	keys        *jwksCache
+}
+
+/*
+* Создание провайдера OpenID Connect.
+* Если указан issuer, недостающие адреса будут получены из документа discovery при первом обращении
+ */
+func NewOIDCProvider(data config.OIDCProviderConfig) *OIDCProvider {
+	provider := &OIDCProvider{
+		config:      data.OAuth2Config(),
+		issuer:      strings.TrimRight(data.Issuer, "/"),
+		userInfoURL: data.UserInfoURL,
+		revokeURL:   data.RevokeURL,
+	}
+
+	if data.JWKSURL != "" {
+		provider.keys = newJWKSCache(data.JWKSURL)
+	}
+
+	return provider
+}
+
+/* Получение адресов провайдера из .well-known/openid-configuration */
+func (p *OIDCProvider) discover() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovered || p.issuer == "" {
+		return nil
+	}
+
+	response, err := httpClient.Get(p.issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("Ошибка: discovery вернул статус %d!", response.StatusCode))
+	}
+
+	var data oidcDiscoveryModel
+	if err = json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return err
+	}
+
+	if strings.TrimRight(data.Issuer, "/") != p.issuer {
+		return errors.New("Ошибка: issuer в документе discovery не совпадает с настройками провайдера!")
+	}
+
+	if p.config.Endpoint.AuthURL == "" {
+		p.config.Endpoint.AuthURL = data.AuthorizationEndpoint
+	}
+
+	if p.config.Endpoint.TokenURL == "" {
+		p.config.Endpoint.TokenURL = data.TokenEndpoint
+	}
+
+	if p.userInfoURL == "" {
+		p.userInfoURL = data.UserInfoEndpoint
+	}
+
+	if p.revokeURL == "" {
+		p.revokeURL = data.RevocationEndpoint
+	}
+
+	if p.keys == nil && data.JWKSURI != "" {
+		p.keys = newJWKSCache(data.JWKSURI)
+	}
+
+	p.discovered = true
+
+	return nil
+}
+
+/* Адрес авторизации с PKCE (S256) и nonce */
+func (p *OIDCProvider) AuthCodeURL(state string, params AuthParams) (string, error) {
+	if err := p.discover(); err != nil {
+		return "", err
+	}
+
+	return p.config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", codeChallengeS256(params.CodeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		oauth2.SetAuthURLParam("nonce", params.Nonce),
+	), nil
+}
+
+/* Обмен кода с PKCE и проверкой ID-токена (подпись, issuer, audience, nonce) */
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, params *AuthParams) (*oauth2.Token, error) {
+	if params == nil {
+		return nil, errors.New("Ошибка: не передан параметр state авторизационного запроса!")
+	}
+
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+
+	token, err := p.config.Exchange(withHTTPClient(ctx), code, oauth2.SetAuthURLParam("code_verifier", params.CodeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	if rawIdToken, ok := token.Extra("id_token").(string); ok && rawIdToken != "" {
+		if _, err := p.verifyIDToken(rawIdToken, params.Nonce); err != nil {
+			return nil, err
+		}
+	} else if p.keys != nil {
+		return nil, errors.New("Ошибка: провайдер не вернул ID-токен!")
+	}
+
+	return token, nil
+}
+
+/* Проверка ID-токена по ключам JWKS издателя (при пустом nonce он не проверяется) */
+func (p *OIDCProvider) verifyIDToken(rawIdToken, nonce string) (jwt.MapClaims, error) {
+	if p.keys == nil {
+		return nil, errors.New("Ошибка: для провайдера не задан адрес JWKS!")
+	}
+
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+
+	_, err := parser.ParseWithClaims(rawIdToken, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, errors.New("invalid signing method")
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.get(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if exp, ok := claims["exp"].(float64); !ok || now.After(time.Unix(int64(exp), 0).Add(idTokenLeeway)) {
+		return nil, errors.New("Ошибка: срок действия ID-токена истёк!")
+	}
+
+	if iat, ok := claims["iat"].(float64); ok && now.Add(idTokenLeeway).Before(time.Unix(int64(iat), 0)) {
+		return nil, errors.New("Ошибка: ID-токен выпущен в будущем!")
+	}
+
+	if iss, _ := claims["iss"].(string); p.issuer != "" && strings.TrimRight(iss, "/") != p.issuer {
+		return nil, errors.New("Ошибка: ID-токен выпущен другим издателем!")
+	}
+
+	if !audienceContains(claims["aud"], p.config.ClientID) {
+		return nil, errors.New("Ошибка: ID-токен выпущен для другого клиента!")
+	}
+
+	if nonce != "" {
+		if value, _ := claims["nonce"].(string); value != nonce {
+			return nil, errors.New("Ошибка: некорректный nonce ID-токена!")
+		}
+	}
+
+	return claims, nil
+}
+
+/* Проверка наличия клиента в claim aud (строка или массив) */
+func audienceContains(aud interface{}, clientId string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == clientId
+	case []interface{}:
+		for _, item := range value {
+			if item == clientId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/* Запрос к userinfo endpoint с токеном доступа */
+func (p *OIDCProvider) userInfo(accessToken string) (*oidcUserInfoModel, error) {
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+
+	if p.userInfoURL == "" {
+		return nil, errors.New("Ошибка: для провайдера не задан адрес userinfo!")
+	}
+
+	request, err := http.NewRequest(http.MethodGet, p.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("Ошибка: userinfo вернул статус %d!", response.StatusCode))
+	}
+
+	var data oidcUserInfoModel
+
+	if err = json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+/* Преобразование claims ID-токена в модель пользователя OpenID Connect */
+func idTokenUserInfo(claims jwt.MapClaims) (*oidcUserInfoModel, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var info oidcUserInfoModel
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+/*
+* Получение данных пользователя: из claims ID-токена, а при отсутствии в нём email - из userinfo
+ */
+func (p *OIDCProvider) GetUserInfo(token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
+	var data *oidcUserInfoModel
+
+	if rawIdToken, ok := token.Extra("id_token").(string); ok && rawIdToken != "" && p.keys != nil {
+		claims, err := p.verifyIDToken(rawIdToken, "")
+		if err != nil {
+			return userModel.UserRegisterOAuth2Model{}, err
+		}
+
+		if data, err = idTokenUserInfo(claims); err != nil {
+			return userModel.UserRegisterOAuth2Model{}, err
+		}
+	}
+
+	if data == nil || data.Email == "" {
+		var err error
+		if data, err = p.userInfo(token.AccessToken); err != nil {
+			return userModel.UserRegisterOAuth2Model{}, err
+		}
+	}
+
+	if data.Email == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: провайдер не предоставил email-адрес пользователя!")
+	}
+
+	if data.EmailVerified != nil && !*data.EmailVerified {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: email-адрес пользователя не подтверждён провайдером!")
+	}
+
+	name := data.Name
+	if name == "" {
+		name = strings.TrimSpace(data.GivenName + " " + data.FamilyName)
+	}
+	if name == "" {
+		name = data.PreferredUsername
+	}
+
+	if data.Subject == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: провайдер не вернул идентификатор пользователя!")
+	}
+
+	return userModel.UserRegisterOAuth2Model{
+		Subject:    data.Subject,
+		Email:      data.Email,
+		FamilyName: data.FamilyName,
+		GivenName:  data.GivenName,
+		Name:       name,
+	}, nil
+}
+
+/* Токен действителен, если userinfo его принимает (без userinfo - проверка выполнена при входе) */
+func (p *OIDCProvider) VerifyAccessToken(accessToken string) (bool, error) {
+	if err := p.discover(); err != nil {
+		return false, err
+	}
+
+	if p.userInfoURL == "" {
+		return true, nil
+	}
+
+	if _, err := p.userInfo(accessToken); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (p *OIDCProvider) RefreshToken(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	if token.RefreshToken == "" {
+		return token, nil
+	}
+
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+
+	return p.config.TokenSource(withHTTPClient(ctx), &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+}
+
+/* Отзыв токена (RFC 7009), если провайдер предоставляет revocation endpoint */
+func (p *OIDCProvider) RevokeToken(accessToken string) (bool, error) {
+	if err := p.discover(); err != nil {
+		return false, err
+	}
+
+	if p.revokeURL == "" {
+		return true, nil
+	}
+
+	form := url.Values{}
+	form.Set("token", accessToken)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+
+	response, err := httpClient.PostForm(p.revokeURL, form)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	return (response.StatusCode == http.StatusOK), nil
+}
@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"main-server/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+/* Тестовый издатель OpenID Connect: discovery, JWKS (с ротацией ключей) и token endpoint */
+type stubIssuer struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	idToken string
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	t.Helper()
+
+	issuer := &stubIssuer{keys: map[string]*rsa.PrivateKey{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscoveryModel{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/authorize",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			UserInfoEndpoint:      issuer.server.URL + "/userinfo",
+			JWKSURI:               issuer.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+
+		keys := []jsonWebKey{}
+		for kid, key := range issuer.keys {
+			keys = append(keys, jsonWebKey{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     issuer.idToken,
+		})
+	})
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+/* Замена набора ключей издателя одним новым ключом */
+func (s *stubIssuer) rotate(t *testing.T, kid string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = map[string]*rsa.PrivateKey{kid: key}
+}
+
+/* ID-токен, который вернёт token endpoint */
+func (s *stubIssuer) setIDToken(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idToken = value
+}
+
+func (s *stubIssuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	s.mu.Lock()
+	key := s.keys[kid]
+	s.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func (s *stubIssuer) claims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   s.server.URL,
+		"aud":   "client",
+		"sub":   "subject",
+		"email": "user@example.com",
+		"nonce": nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func (s *stubIssuer) provider() *OIDCProvider {
+	return NewOIDCProvider(config.OIDCProviderConfig{
+		Name:        "stub",
+		Issuer:      s.server.URL,
+		ClientID:    "client",
+		RedirectURL: "https://app.test/callback",
+	})
+}
+
+func TestOIDCDiscovery(t *testing.T) {
+	issuer := newStubIssuer(t)
+	provider := issuer.provider()
+
+	address, err := provider.AuthCodeURL("state", AuthParams{CodeVerifier: "verifier", Nonce: "nonce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(address, issuer.server.URL+"/authorize?") {
+		t.Fatalf("expected the discovered authorization endpoint, got %s", address)
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := parsed.Query()
+	if query.Get("state") != "state" || query.Get("nonce") != "nonce" ||
+		query.Get("code_challenge") != codeChallengeS256("verifier") || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization parameters: %s", parsed.RawQuery)
+	}
+
+	if provider.config.Endpoint.TokenURL != issuer.server.URL+"/token" || provider.keys == nil {
+		t.Fatal("expected token endpoint and JWKS to be discovered")
+	}
+
+	// Документ discovery другого издателя не принимается
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscoveryModel{Issuer: "https://evil.test"})
+	}))
+	defer other.Close()
+
+	mismatched := NewOIDCProvider(config.OIDCProviderConfig{Issuer: other.URL, ClientID: "client"})
+	if _, err := mismatched.AuthCodeURL("state", AuthParams{}); err == nil {
+		t.Fatal("expected discovery with a foreign issuer to be rejected")
+	}
+}
+
+func TestOIDCExchangeVerifiesNonce(t *testing.T) {
+	issuer := newStubIssuer(t)
+	issuer.rotate(t, "k1")
+
+	provider := issuer.provider()
+	params := &AuthParams{CodeVerifier: "verifier", Nonce: "expected"}
+
+	issuer.setIDToken(issuer.sign(t, "k1", issuer.claims("expected")))
+	if _, err := provider.Exchange(context.Background(), "code", params); err != nil {
+		t.Fatal(err)
+	}
+
+	issuer.setIDToken(issuer.sign(t, "k1", issuer.claims("replayed")))
+	if _, err := provider.Exchange(context.Background(), "code", params); err == nil {
+		t.Fatal("expected an ID token with a foreign nonce to be rejected")
+	}
+
+	if _, err := provider.Exchange(context.Background(), "code", nil); err == nil {
+		t.Fatal("expected exchange without state parameters to be rejected")
+	}
+}
+
+func TestOIDCJWKSRotation(t *testing.T) {
+	issuer := newStubIssuer(t)
+	issuer.rotate(t, "k1")
+
+	provider := issuer.provider()
+	if err := provider.discover(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.verifyIDToken(issuer.sign(t, "k1", issuer.claims("")), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	issuer.rotate(t, "k2")
+	rotated := issuer.sign(t, "k2", issuer.claims(""))
+
+	// Повторный запрос ключей при неизвестном kid ограничен по частоте
+	if _, err := provider.verifyIDToken(rotated, ""); err == nil {
+		t.Fatal("expected the unknown key to be rejected until the refresh interval passes")
+	}
+
+	provider.keys.mu.Lock()
+	provider.keys.fetchedAt = time.Now().Add(-2 * jwksRefreshInterval)
+	provider.keys.mu.Unlock()
+
+	if _, err := provider.verifyIDToken(rotated, ""); err != nil {
+		t.Fatalf("expected the rotated key to be fetched: %s", err)
+	}
+}
+
+func TestOIDCRejectsInvalidIDTokens(t *testing.T) {
+	issuer := newStubIssuer(t)
+	issuer.rotate(t, "k1")
+
+	provider := issuer.provider()
+	if err := provider.discover(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]func(claims jwt.MapClaims){
+		"nonce": func(claims jwt.MapClaims) { claims["nonce"] = "other" },
+		"aud":   func(claims jwt.MapClaims) { claims["aud"] = "other-client" },
+		"iss":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.test" },
+		"exp":   func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+	}
+
+	for name, modify := range cases {
+		claims := issuer.claims("expected")
+		modify(claims)
+
+		if _, err := provider.verifyIDToken(issuer.sign(t, "k1", claims), "expected"); err == nil {
+			t.Errorf("%s: expected the ID token to be rejected", name)
+		}
+	}
+
+	if _, err := provider.verifyIDToken(issuer.sign(t, "k1", issuer.claims("expected")), "expected"); err != nil {
+		t.Fatalf("expected a valid ID token to be accepted: %s", err)
+	}
+}
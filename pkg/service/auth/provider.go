@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main-server/config"
+	authConstant "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+/*
+* Провайдер внешней аутентификации (OAuth2 / OpenID Connect).
+* Название провайдера совпадает со значением типа аутентификации в u_auth_types
+ */
+type Provider interface {
+	// Адрес страницы авторизации провайдера
+	AuthCodeURL(state string, params AuthParams) (string, error)
+
+	// Обмен кода авторизации на токен доступа (params - параметры запроса, выданного AuthCodeURL)
+	Exchange(ctx context.Context, code string, params *AuthParams) (*oauth2.Token, error)
+
+	// Получение информации о пользователе
+	GetUserInfo(token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error)
+
+	// Проверка действительности токена доступа провайдера
+	VerifyAccessToken(accessToken string) (bool, error)
+
+	// Обновление токена доступа провайдера
+	RefreshToken(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error)
+
+	// Отзыв токена доступа провайдера
+	RevokeToken(accessToken string) (bool, error)
+}
+
+var providers = map[string]Provider{}
+
+/* Регистрация провайдера внешней аутентификации */
+func RegisterProvider(name string, provider Provider) {
+	providers[strings.ToLower(name)] = provider
+}
+
+/* Получение провайдера по значению типа аутентификации */
+func GetProvider(name string) (Provider, error) {
+	provider, ok := providers[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("Ошибка: провайдер аутентификации %s не поддерживается!", name))
+	}
+
+	return provider, nil
+}
+
+/* Список названий зарегистрированных провайдеров */
+func ProviderNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+* Регистрация провайдеров из конфигурации: Google (oauth2), VK (vk_oauth2)
+* и произвольное количество провайдеров OpenID Connect (oidc_providers)
+ */
+func InitProviders() error {
+	providers = map[string]Provider{}
+
+	if viper.GetString("oauth2.client_id") != "" {
+		RegisterProvider(authConstant.AUTH_TYPE_GOOGLE, NewGoogleProvider(config.AppOAuth2Config.GoogleLogin))
+	}
+
+	if viper.GetString("vk_oauth2.client_id") != "" {
+		RegisterProvider(authConstant.AUTH_TYPE_VK, NewVKProvider(config.AppVKAuthConfig.VkAuth, config.AppVKAuthConfig.ApiURL))
+	}
+
+	for _, item := range config.AppOIDCConfig {
+		if item.Name == "" {
+			return errors.New("Ошибка: не указано название провайдера OpenID Connect!")
+		}
+
+		if item.Name == authConstant.AUTH_TYPE_LOCAL {
+			return errors.New(fmt.Sprintf("Ошибка: название провайдера %s зарезервировано!", item.Name))
+		}
+
+		RegisterProvider(item.Name, NewOIDCProvider(item))
+	}
+
+	return nil
+}
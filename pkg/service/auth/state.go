@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+)
+
+/* Время жизни параметра state авторизационного запроса */
+const AuthStateTTL = 10 * time.Minute
+
+/* Параметры авторизационного запроса, необходимые при обмене кода */
+type AuthParams struct {
+	CodeVerifier string // PKCE code_verifier
+	Nonce        string // Значение nonce для проверки ID-токена
+}
+
+/* Новый авторизационный запрос: параметр state и связанные с ним параметры обмена кода */
+type AuthRequest struct {
+	State  string
+	Params AuthParams
+}
+
+/* Генерация случайной строки (base64url, без выравнивания) */
+func randomString(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+/* Вычисление code_challenge для PKCE (метод S256) */
+func codeChallengeS256(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+/* Генерация параметров нового авторизационного запроса (state, PKCE code_verifier, nonce) */
+func NewAuthRequest() (AuthRequest, error) {
+	state, err := randomString(24)
+	if err != nil {
+		return AuthRequest{}, err
+	}
+
+	verifier, err := randomString(48)
+	if err != nil {
+		return AuthRequest{}, err
+	}
+
+	nonce, err := randomString(24)
+	if err != nil {
+		return AuthRequest{}, err
+	}
+
+	return AuthRequest{
+		State: state,
+		Params: AuthParams{
+			CodeVerifier: verifier,
+			Nonce:        nonce,
+		},
+	}, nil
+}
+
+/* Формирование адреса страницы авторизации провайдера для выданного авторизационного запроса */
+func AuthCodeURL(name string, request AuthRequest) (string, error) {
+	provider, err := GetProvider(name)
+	if err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(request.State, request.Params)
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	route "main-server/pkg/constant/route"
+	userModel "main-server/pkg/model/user"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+/* Ответ VK API на запрос users.get */
+type vkUsersGetModel struct {
+	Response []struct {
+		Id        int    `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	} `json:"response"`
+	Error *struct {
+		ErrorCode int    `json:"error_code"`
+		ErrorMsg  string `json:"error_msg"`
+	} `json:"error"`
+}
+
+/* Провайдер аутентификации VK OAuth2 */
+type VKProvider struct {
+	config oauth2.Config
+	apiURL string
+}
+
+/* Создание провайдера VK OAuth2 */
+func NewVKProvider(config oauth2.Config, apiURL string) *VKProvider {
+	return &VKProvider{
+		config: config,
+		apiURL: strings.TrimRight(apiURL, "/"),
+	}
+}
+
+/* VK не поддерживает PKCE - используется только параметр state */
+func (p *VKProvider) AuthCodeURL(state string, params AuthParams) (string, error) {
+	return p.config.AuthCodeURL(state), nil
+}
+
+func (p *VKProvider) Exchange(ctx context.Context, code string, params *AuthParams) (*oauth2.Token, error) {
+	return p.config.Exchange(withHTTPClient(ctx), code)
+}
+
+/*
+* Получение идентификатора пользователя VK из ответа на обмен кода.
+* VK возвращает user_id (и email) не в отдельном методе, а вместе с токеном доступа
+ */
+func vkUserId(token *oauth2.Token) (string, error) {
+	switch value := token.Extra("user_id").(type) {
+	case float64:
+		return fmt.Sprintf("%.0f", value), nil
+	case string:
+		if value != "" {
+			return value, nil
+		}
+	}
+
+	return "", errors.New("Ошибка: VK не вернул идентификатор пользователя!")
+}
+
+/* Запрос users.get к VK API (при пустом userId возвращается владелец токена) */
+func (p *VKProvider) usersGet(accessToken, userId string) (*vkUsersGetModel, error) {
+	params := url.Values{}
+	if userId != "" {
+		params.Set("user_ids", userId)
+	}
+	params.Set("access_token", accessToken)
+	params.Set("v", route.VK_API_VERSION)
+
+	response, err := httpClient.Get(p.apiURL + route.VK_USERS_GET_ROUTE + "?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var data vkUsersGetModel
+
+	if err = json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	if data.Error != nil {
+		return nil, errors.New(data.Error.ErrorMsg)
+	}
+
+	if len(data.Response) <= 0 {
+		return nil, errors.New("Ошибка: пользователь VK не найден!")
+	}
+
+	return &data, nil
+}
+
+/* Получение информации о пользователе VK по токену доступа */
+func (p *VKProvider) GetUserInfo(token *oauth2.Token) (userModel.UserRegisterOAuth2Model, error) {
+	// Email-адрес передаётся только в ответе на обмен кода (при scope=email)
+	email, _ := token.Extra("email").(string)
+	if email == "" {
+		return userModel.UserRegisterOAuth2Model{}, errors.New("Ошибка: VK не предоставил email-адрес пользователя!")
+	}
+
+	userId, err := vkUserId(token)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	data, err := p.usersGet(token.AccessToken, userId)
+	if err != nil {
+		return userModel.UserRegisterOAuth2Model{}, err
+	}
+
+	info := data.Response[0]
+
+	return userModel.UserRegisterOAuth2Model{
+		Subject:    userId,
+		Email:      email,
+		FamilyName: info.LastName,
+		GivenName:  info.FirstName,
+		Name:       strings.TrimSpace(info.FirstName + " " + info.LastName),
+	}, nil
+}
+
+/* Токен VK считается действительным, если по нему доступен профиль владельца */
+func (p *VKProvider) VerifyAccessToken(accessToken string) (bool, error) {
+	if _, err := p.usersGet(accessToken, ""); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/* VK не выдаёт токен обновления - используется ранее полученный токен доступа */
+func (p *VKProvider) RefreshToken(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	return token, nil
+}
+
+/* VK не поддерживает отзыв токена доступа */
+func (p *VKProvider) RevokeToken(accessToken string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	route "main-server/pkg/constant/route"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+/* Тестовый сервер VK: обмен кода (oauth.vk.com/access_token) и метод users.get */
+func newStubVK(t *testing.T, email string) (*VKProvider, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		response := map[string]interface{}{
+			"access_token": "vk-access",
+			"expires_in":   86400,
+			"user_id":      1234567,
+		}
+
+		if email != "" {
+			response["email"] = email
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+
+	mux.HandleFunc("/method"+route.VK_USERS_GET_ROUTE, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if query.Get("access_token") != "vk-access" || query.Get("v") != route.VK_API_VERSION {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{"error_code": 5, "error_msg": "User authorization failed"},
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": []map[string]interface{}{
+				{"id": 1234567, "first_name": "Иван", "last_name": "Петров"},
+			},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider := NewVKProvider(oauth2.Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   server.URL + "/authorize",
+			TokenURL:  server.URL + "/access_token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}, server.URL+"/method/")
+
+	return provider, server
+}
+
+func TestVKLoginFlow(t *testing.T) {
+	provider, _ := newStubVK(t, "user@example.com")
+
+	token, err := provider.Exchange(context.Background(), "code", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.Exchange(context.Background(), "invalid", nil); err == nil {
+		t.Fatal("expected an invalid code to be rejected")
+	}
+
+	valid, err := provider.VerifyAccessToken(token.AccessToken)
+	if err != nil || !valid {
+		t.Fatalf("expected the access token to be verified, got %v %v", valid, err)
+	}
+
+	if valid, _ := provider.VerifyAccessToken("foreign"); valid {
+		t.Fatal("expected a foreign access token to be rejected")
+	}
+
+	data, err := provider.GetUserInfo(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Subject != "1234567" || data.Email != "user@example.com" ||
+		data.GivenName != "Иван" || data.FamilyName != "Петров" || data.Name != "Иван Петров" {
+		t.Fatalf("unexpected user info: %+v", data)
+	}
+}
+
+func TestVKLoginWithoutEmail(t *testing.T) {
+	provider, _ := newStubVK(t, "")
+
+	token, err := provider.Exchange(context.Background(), "code", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Без разрешения email VK не передаёт адрес - зарегистрировать пользователя нельзя
+	if _, err := provider.GetUserInfo(token); err == nil {
+		t.Fatal("expected login without email to be rejected")
+	}
+}
@@ -13,6 +13,11 @@ func NewAuthTypeService(role repository.AuthType) *AuthTypeService {
 	return &AuthTypeService{authType: role}
 }
 
+/* Method for registering an auth type value (no-op if it already exists) */
+func (s *AuthTypeService) AddAuthType(value string) error {
+	return s.authType.AddAuthType(value)
+}
+
 func (s *AuthTypeService) Get(column string, value interface{}, check bool) (*userModel.AuthTypeModel, error) {
 	return s.authType.Get(column, value, check)
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса */
+type BanService struct {
+	repo repository.Ban
+}
+
+/* Создание нового экземпляра структуры */
+func NewBanService(repo repository.Ban) *BanService {
+	return &BanService{
+		repo: repo,
+	}
+}
+
+/* Блокировка пользователя */
+func (s *BanService) BanUser(admin userModel.UserIdentityModel, data userModel.BanCreateModel) (userModel.BanModel, error) {
+	return s.repo.BanUser(admin, data)
+}
+
+/* Снятие блокировки с пользователя */
+func (s *BanService) UnbanUser(data userModel.BanUserUuidModel) (bool, error) {
+	return s.repo.UnbanUser(data)
+}
+
+/* Получение списка блокировок */
+func (s *BanService) GetBans(data userModel.BanCountModel) (userModel.BanAnyCountModel, error) {
+	return s.repo.GetBans(data)
+}
+
+/* Проверка блокировки пользователя */
+func (s *BanService) CheckBan(usersId int) error {
+	return s.repo.CheckBan(usersId)
+}
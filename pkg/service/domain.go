@@ -18,3 +18,28 @@ func NewDomainService(repo repository.Domain) *DomainService {
 func (s *DomainService) Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error) {
 	return s.repo.Get(column, value, check)
 }
+
+/* Определение домена запроса по значению */
+func (s *DomainService) Resolve(value string) (*rbacModel.DomainModel, error) {
+	return s.repo.Resolve(value)
+}
+
+/* Получение списка доменов */
+func (s *DomainService) GetDomains() (rbacModel.DomainsModel, error) {
+	return s.repo.GetDomains()
+}
+
+/* Создание нового домена */
+func (s *DomainService) CreateDomain(usersId int, data rbacModel.DomainCreateModel) (rbacModel.DomainModel, error) {
+	return s.repo.CreateDomain(usersId, data)
+}
+
+/* Изменение домена */
+func (s *DomainService) UpdateDomain(data rbacModel.DomainUpdateModel) (rbacModel.DomainModel, error) {
+	return s.repo.UpdateDomain(data)
+}
+
+/* Удаление домена */
+func (s *DomainService) DeleteDomain(data rbacModel.DomainUuidModel) (bool, error) {
+	return s.repo.DeleteDomain(data)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса */
+type EmailChangeService struct {
+	repo repository.EmailChange
+}
+
+/* Создание нового экземпляра структуры */
+func NewEmailChangeService(repo repository.EmailChange) *EmailChangeService {
+	return &EmailChangeService{
+		repo: repo,
+	}
+}
+
+/* Получение состояния запроса на смену email-адреса */
+func (s *EmailChangeService) GetEmailChange(usersId int) (userModel.EmailChangeStatusModel, error) {
+	return s.repo.GetEmailChange(usersId)
+}
+
+/* Запрос на смену email-адреса (письма отправляются на новый и текущий адреса) */
+func (s *EmailChangeService) RequestEmailChange(usersId int, data userModel.EmailChangeModel) (bool, error) {
+	return s.repo.RequestEmailChange(usersId, data)
+}
+
+/* Подтверждение смены email-адреса (сессии, кроме текущей, завершаются) */
+func (s *EmailChangeService) ConfirmEmailChange(usersId int, data userModel.EmailChangeTokenModel, currentSessionUuid string) (bool, error) {
+	return s.repo.ConfirmEmailChange(usersId, data.Token, currentSessionUuid)
+}
+
+/* Отмена смены email-адреса по ссылке из письма */
+func (s *EmailChangeService) CancelEmailChange(data userModel.EmailChangeTokenModel) (bool, error) {
+	return s.repo.CancelEmailChange(data.Token)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	entityModel "main-server/pkg/model/entity"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type EntityService struct {
+	repo repository.Entity
+}
+
+/* Function for create new struct of EntityService */
+func NewEntityService(repo repository.Entity) *EntityService {
+	return &EntityService{
+		repo: repo,
+	}
+}
+
+/* Method for create new entity in project */
+func (s *EntityService) CreateEntity(user userModel.UserIdentityModel, data entityModel.EntityCreateModel) (entityModel.EntityDbDataEx, error) {
+	return s.repo.CreateEntity(user, data)
+}
+
+/* Method for update entity */
+func (s *EntityService) EntityUpdate(user userModel.UserIdentityModel, data entityModel.EntityUpdateModel) (entityModel.EntityUpdateModel, error) {
+	return s.repo.EntityUpdate(user, data)
+}
+
+/* Method for get information about entity */
+func (s *EntityService) GetEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (entityModel.EntityDbDataEx, error) {
+	return s.repo.GetEntity(user, data)
+}
+
+/* Method for get any count entities of project */
+func (s *EntityService) GetEntities(user userModel.UserIdentityModel, data entityModel.EntityCountModel) (entityModel.EntityAnyCountModel, error) {
+	return s.repo.GetEntities(user, data)
+}
+
+/* Method for delete entity */
+func (s *EntityService) DeleteEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (bool, error) {
+	return s.repo.DeleteEntity(user, data)
+}
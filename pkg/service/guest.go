@@ -0,0 +1,38 @@
+package service
+
+import (
+	guestModel "main-server/pkg/model/guest"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type GuestService struct {
+	repo repository.Guest
+}
+
+/* Function for create new struct of GuestService */
+func NewGuestService(repo repository.Guest) *GuestService {
+	return &GuestService{
+		repo: repo,
+	}
+}
+
+/* Method for get public list of companies */
+func (s *GuestService) GetCompanies(data guestModel.GuestCompanyQueryModel) (guestModel.GuestCompanyAnyCountModel, error) {
+	return s.repo.GetCompanies(data)
+}
+
+/* Method for get public list of projects */
+func (s *GuestService) GetProjects(data guestModel.GuestProjectQueryModel) (guestModel.GuestProjectAnyCountModel, error) {
+	return s.repo.GetProjects(data)
+}
+
+/* Method for get public list of available sub entities */
+func (s *GuestService) GetSubEntities(data guestModel.GuestSubEntityQueryModel) (guestModel.GuestSubEntityAnyCountModel, error) {
+	return s.repo.GetSubEntities(data)
+}
+
+/* Method for get public information about sub entity */
+func (s *GuestService) GetSubEntity(data guestModel.GuestUuidModel) (guestModel.GuestSubEntityModel, error) {
+	return s.repo.GetSubEntity(data)
+}
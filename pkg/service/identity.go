@@ -0,0 +1,76 @@
+package service
+
+import (
+	authConstant "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+	authService "main-server/pkg/service/auth"
+	passwordService "main-server/pkg/service/password"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+/* Структура сервиса */
+type IdentityService struct {
+	repo repository.Identity
+}
+
+/* Создание нового экземпляра структуры */
+func NewIdentityService(repo repository.Identity) *IdentityService {
+	return &IdentityService{
+		repo: repo,
+	}
+}
+
+/* Получение привязанных способов входа и провайдеров, доступных для привязки */
+func (s *IdentityService) GetIdentities(usersId int) (userModel.IdentitiesModel, error) {
+	identities, err := s.repo.GetIdentities(usersId)
+	if err != nil {
+		return userModel.IdentitiesModel{}, err
+	}
+
+	linked := lo.Map(identities, func(item userModel.IdentityModel, _ int) string {
+		return item.AuthType
+	})
+
+	available := []string{}
+	for _, name := range append([]string{authConstant.AUTH_TYPE_LOCAL}, authService.ProviderNames()...) {
+		if !lo.Contains(linked, name) {
+			available = append(available, name)
+		}
+	}
+
+	if identities == nil {
+		identities = []userModel.IdentityModel{}
+	}
+
+	return userModel.IdentitiesModel{
+		Identities: identities,
+		Available:  available,
+	}, nil
+}
+
+/* Адрес страницы авторизации провайдера для привязки его учётной записи к пользователю */
+func (s *IdentityService) GetLinkURL(usersId int, provider string) (string, error) {
+	return s.repo.GetLinkURL(usersId, provider)
+}
+
+/* Привязка учётной записи провайдера по коду авторизации */
+func (s *IdentityService) LinkIdentity(usersId int, data userModel.IdentityLinkModel) (bool, error) {
+	return s.repo.LinkIdentity(usersId, data.Provider, data.Code, data.State)
+}
+
+/* Отвязка способа входа (local - удаление пароля) */
+func (s *IdentityService) UnlinkIdentity(usersId int, data userModel.IdentityProviderModel) (bool, error) {
+	return s.repo.UnlinkIdentity(usersId, strings.ToLower(data.Provider))
+}
+
+/* Установка пароля для аккаунта, созданного через провайдера */
+func (s *IdentityService) SetPassword(usersId int, data userModel.PasswordSetModel) (bool, error) {
+	if err := passwordService.Validate(data.Password); err != nil {
+		return false, err
+	}
+
+	return s.repo.SetPassword(usersId, data.Password)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	authConstant "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+	"time"
+)
+
+/* Интроспекция (RFC 7662) и отзыв (RFC 7009) токенов для сервисов платформы */
+type IntrospectionService struct {
+	tokenService *TokenService
+	revocation   repository.Revocation
+	session      repository.Session
+	ban          repository.Ban
+	user         repository.User
+}
+
+func NewIntrospectionService(
+	tokenService *TokenService,
+	revocation repository.Revocation,
+	session repository.Session,
+	ban repository.Ban,
+	user repository.User,
+) *IntrospectionService {
+	return &IntrospectionService{
+		tokenService: tokenService,
+		revocation:   revocation,
+		session:      session,
+		ban:          ban,
+		user:         user,
+	}
+}
+
+/*
+* Интроспекция токена доступа: токен активен, если подпись и срок действия корректны, токен не отозван,
+* пользователь не заблокирован и сессия не завершена. Для неактивного токена подробности не раскрываются
+ */
+func (s *IntrospectionService) Introspect(data userModel.TokenIntrospectInputModel, domainsId int) (userModel.TokenIntrospectionModel, error) {
+	inactive := userModel.TokenIntrospectionModel{Active: false}
+
+	token, err := s.tokenService.ParseToken(data.Token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	if err := s.ban.CheckBan(token.UsersId); err != nil {
+		return inactive, nil
+	}
+
+	if token.SessionUuid != nil {
+		if err := s.session.CheckSession(token.UsersId, *token.SessionUuid); err != nil {
+			return inactive, nil
+		}
+	}
+
+	roles, err := s.user.GetAllRoles(userModel.UserIdentityModel{
+		UserId:   token.UsersId,
+		UserUuid: token.UsersUuid,
+		DomainId: domainsId,
+	})
+	if err != nil {
+		return userModel.TokenIntrospectionModel{}, err
+	}
+
+	return userModel.TokenIntrospectionModel{
+		Active:      true,
+		TokenType:   authConstant.TOKEN_TYPE_HINT_ACCESS,
+		Sub:         token.UsersUuid,
+		AuthType:    token.AuthType.Value,
+		Roles:       roles.Roles,
+		SessionUuid: token.SessionUuid,
+		Jti:         token.TokenId,
+		Exp:         token.ExpiresAt,
+		Iat:         token.IssuedAt,
+	}, nil
+}
+
+/*
+* Отзыв токена доступа или обновления. Подсказка token_type_hint определяет порядок поиска;
+* неизвестные и некорректные токены игнорируются (RFC 7009, раздел 2.2)
+ */
+func (s *IntrospectionService) Revoke(data userModel.TokenIntrospectInputModel) error {
+	if data.TokenTypeHint != authConstant.TOKEN_TYPE_HINT_ACCESS {
+		revoked, err := s.revocation.RevokeRefreshToken(data.Token)
+		if err != nil || revoked {
+			return err
+		}
+	}
+
+	token, err := s.tokenService.ParseTokenWithoutValid(data.Token)
+	if err != nil {
+		return nil
+	}
+
+	return s.revocation.RevokeAccessToken(token.TokenId, time.Unix(token.ExpiresAt, 0))
+}
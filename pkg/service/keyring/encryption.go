@@ -0,0 +1,75 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"main-server/config"
+	"strings"
+)
+
+/* Префикс зашифрованного закрытого ключа (версия формата: nonce || ciphertext AES-256-GCM в base64) */
+const encryptedKeyPrefix = "enc:v1:"
+
+/* Закрытый ключ хранится в БД в открытом виде (записан до включения шифрования) */
+func isPlaintextKey(value string) bool {
+	return strings.HasPrefix(value, "-----BEGIN")
+}
+
+func newCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(config.AppKeyringConfig.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+/* Шифрование закрытого ключа (kid - дополнительные данные: шифротекст нельзя перенести в другую запись) */
+func encryptPrivateKey(kid, privateKey string) (string, error) {
+	aead, err := newCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(privateKey), []byte(kid))
+
+	return encryptedKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+/* Расшифровка закрытого ключа; ключи в открытом виде не принимаются */
+func decryptPrivateKey(kid, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedKeyPrefix) {
+		return "", errors.New("private key is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedKeyPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newCipher()
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted private key")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	privateKey, err := aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return "", err
+	}
+
+	return string(privateKey), nil
+}
@@ -0,0 +1,398 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"main-server/config"
+	userModel "main-server/pkg/model/user"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Интервал проверки необходимости ротации и синхронизации ключей с БД
+	rotationCheckInterval = 10 * time.Minute
+
+	// Минимальный интервал между повторными загрузками ключей (при неизвестном kid)
+	unknownKidReloadInterval = 10 * time.Second
+
+	// Размер ключа RSA
+	rsaKeyBits = 2048
+)
+
+/* Хранилище ключей подписи (общее для всех экземпляров сервера) */
+type Store interface {
+	GetSigningKeys() ([]userModel.SigningKeyDbModel, error)
+	IsRotationDue(algorithm string, rotationInterval time.Duration) (bool, error)
+	CreateSigningKey(key userModel.SigningKeyDbModel, rotationInterval time.Duration) (bool, error)
+	DeleteRetiredSigningKeys(retention time.Duration) error
+	UpdateSigningKeyPrivateKey(kid, privateKey string) error
+}
+
+/* Ключ подписи с разобранным ключевым материалом */
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey interface{}
+	publicKey  interface{}
+}
+
+/* Набор ключей подписи токенов доступа */
+type Keyring struct {
+	store      Store
+	mu         sync.RWMutex
+	keys       map[string]*signingKey
+	active     *signingKey
+	reloadedAt time.Time
+}
+
+var defaultKeyring *Keyring
+
+/* Инициализация набора ключей: при отсутствии действующего ключа он создаётся */
+func Init(store Store) error {
+	keyring := &Keyring{
+		store: store,
+		keys:  map[string]*signingKey{},
+	}
+
+	if err := keyring.encryptPlaintextKeys(); err != nil {
+		return err
+	}
+
+	if err := keyring.rotate(); err != nil {
+		return err
+	}
+
+	defaultKeyring = keyring
+
+	return nil
+}
+
+/* Запуск плановой ротации ключей в фоне */
+func StartRotation() {
+	go func() {
+		ticker := time.NewTicker(rotationCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := getKeyring().rotate(); err != nil {
+				logrus.Errorf("error rotating signing keys: %s", err.Error())
+			}
+		}
+	}()
+}
+
+func getKeyring() *Keyring {
+	if defaultKeyring == nil {
+		logrus.Fatal("signing keyring is not initialized")
+	}
+
+	return defaultKeyring
+}
+
+/* Подпись токена действующим ключом (идентификатор ключа передаётся в заголовке kid) */
+func Sign(claims jwt.Claims) (string, error) {
+	keyring := getKeyring()
+
+	keyring.mu.RLock()
+	key := keyring.active
+	keyring.mu.RUnlock()
+
+	if key == nil {
+		return "", errors.New("Отсутствует действующий ключ подписи")
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.privateKey)
+}
+
+/* Получение открытого ключа для проверки подписи токена (функция jwt.Keyfunc) */
+func Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, errors.New("token kid is not found")
+	}
+
+	key, err := getKeyring().get(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return key.publicKey, nil
+}
+
+/* Набор открытых ключей (действующего и выведенных из ротации) в формате JWKS */
+func JWKS() userModel.JWKSModel {
+	keyring := getKeyring()
+
+	keyring.mu.RLock()
+	defer keyring.mu.RUnlock()
+
+	jwks := userModel.JWKSModel{Keys: []userModel.JWKModel{}}
+	for _, key := range keyring.keys {
+		jwk := userModel.JWKModel{
+			Kid: key.kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+/* Поиск ключа по kid; неизвестный kid (ротация на другом экземпляре) приводит к перезагрузке ключей */
+func (k *Keyring) get(kid string) (*signingKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	reloadedAt := k.reloadedAt
+	k.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	if time.Since(reloadedAt) > unknownKidReloadInterval {
+		if err := k.reload(); err != nil {
+			return nil, err
+		}
+
+		k.mu.RLock()
+		key, ok = k.keys[kid]
+		k.mu.RUnlock()
+
+		if ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("signing key %s is not found", kid)
+}
+
+/*
+* Создание нового ключа, если действующий старше интервала ротации (или алгоритм изменён в конфигурации),
+* удаление ключей, выведенных из ротации дольше срока хранения, и синхронизация с БД
+ */
+func (k *Keyring) rotate() error {
+	settings := config.AppKeyringConfig
+
+	due, err := k.store.IsRotationDue(settings.Algorithm, settings.RotationInterval)
+	if err != nil {
+		return err
+	}
+
+	if due {
+		key, err := generateKey(settings.Algorithm)
+		if err != nil {
+			return err
+		}
+
+		created, err := k.store.CreateSigningKey(key, settings.RotationInterval)
+		if err != nil {
+			return err
+		}
+
+		if created {
+			logrus.Printf("signing key %s (%s) created", key.Kid, key.Algorithm)
+		}
+	}
+
+	if err := k.store.DeleteRetiredSigningKeys(settings.Retention); err != nil {
+		return err
+	}
+
+	return k.reload()
+}
+
+/* Шифрование закрытых ключей, сохранённых в БД в открытом виде до включения шифрования */
+func (k *Keyring) encryptPlaintextKeys() error {
+	models, err := k.store.GetSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	for _, model := range models {
+		if !isPlaintextKey(model.PrivateKey) {
+			continue
+		}
+
+		encrypted, err := encryptPrivateKey(model.Kid, model.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		if err := k.store.UpdateSigningKeyPrivateKey(model.Kid, encrypted); err != nil {
+			return err
+		}
+
+		logrus.Printf("signing key %s encrypted", model.Kid)
+	}
+
+	return nil
+}
+
+/* Загрузка ключей из БД; действующим считается самый новый не выведенный из ротации ключ */
+func (k *Keyring) reload() error {
+	models, err := k.store.GetSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	k.mu.RLock()
+	previous := k.keys
+	k.mu.RUnlock()
+
+	keys := map[string]*signingKey{}
+	var active *signingKey
+
+	for _, model := range models {
+		key, ok := previous[model.Kid]
+		if !ok {
+			key, err = parseKey(model)
+			if err != nil {
+				logrus.Errorf("error parsing signing key %s: %s", model.Kid, err.Error())
+				continue
+			}
+		}
+
+		keys[key.kid] = key
+
+		// Ключи упорядочены по дате создания (от новых к старым)
+		if active == nil && model.RetiredAt == nil {
+			active = key
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.active = active
+	k.reloadedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+/* Генерация новой пары ключей с сериализацией в PEM (PKCS #8 / PKIX) */
+func generateKey(algorithm string) (userModel.SigningKeyDbModel, error) {
+	var privateKey, publicKey interface{}
+
+	switch algorithm {
+	case config.KEYRING_ALG_EDDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return userModel.SigningKeyDbModel{}, err
+		}
+
+		privateKey, publicKey = private, public
+	default:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return userModel.SigningKeyDbModel{}, err
+		}
+
+		privateKey, publicKey = private, &private.PublicKey
+	}
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return userModel.SigningKeyDbModel{}, err
+	}
+
+	publicDer, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return userModel.SigningKeyDbModel{}, err
+	}
+
+	kid := uuid.NewV4().String()
+
+	// Закрытый ключ сохраняется в БД только в зашифрованном виде
+	encrypted, err := encryptPrivateKey(kid, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer})))
+	if err != nil {
+		return userModel.SigningKeyDbModel{}, err
+	}
+
+	return userModel.SigningKeyDbModel{
+		Kid:        kid,
+		Algorithm:  algorithm,
+		PrivateKey: encrypted,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})),
+	}, nil
+}
+
+/* Расшифровка закрытого ключа и разбор ключевого материала из PEM */
+func parseKey(model userModel.SigningKeyDbModel) (*signingKey, error) {
+	privatePem, err := decryptPrivateKey(model.Kid, model.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateBlock, _ := pem.Decode([]byte(privatePem))
+	publicBlock, _ := pem.Decode([]byte(model.PublicKey))
+	if privateBlock == nil || publicBlock == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(publicBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{
+		kid:        model.Kid,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}
+
+	switch model.Algorithm {
+	case config.KEYRING_ALG_RS256:
+		if _, ok := publicKey.(*rsa.PublicKey); !ok {
+			return nil, errors.New("key type does not match RS256")
+		}
+		key.method = jwt.SigningMethodRS256
+	case config.KEYRING_ALG_EDDSA:
+		if _, ok := publicKey.(ed25519.PublicKey); !ok {
+			return nil, errors.New("key type does not match EdDSA")
+		}
+		key.method = SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", model.Algorithm)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,125 @@
+package keyring
+
+import (
+	"bytes"
+	"main-server/config"
+	userModel "main-server/pkg/model/user"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+/* Хранилище ключей в памяти */
+type memoryStore struct {
+	mu   sync.Mutex
+	keys []userModel.SigningKeyDbModel
+}
+
+func (s *memoryStore) GetSigningKeys() ([]userModel.SigningKeyDbModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]userModel.SigningKeyDbModel{}, s.keys...), nil
+}
+
+func (s *memoryStore) IsRotationDue(algorithm string, rotationInterval time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.RetiredAt == nil && key.Algorithm == algorithm && time.Since(key.CreatedAt) < rotationInterval {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *memoryStore) CreateSigningKey(key userModel.SigningKeyDbModel, rotationInterval time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key.CreatedAt = time.Now()
+	s.keys = append([]userModel.SigningKeyDbModel{key}, s.keys...)
+
+	return true, nil
+}
+
+func (s *memoryStore) DeleteRetiredSigningKeys(retention time.Duration) error {
+	return nil
+}
+
+func (s *memoryStore) UpdateSigningKeyPrivateKey(kid, privateKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.keys {
+		if s.keys[i].Kid == kid {
+			s.keys[i].PrivateKey = privateKey
+		}
+	}
+
+	return nil
+}
+
+func setTestKeyringConfig(encryptionKey []byte) {
+	config.AppKeyringConfig = config.KeyringConfig{
+		Algorithm:        config.KEYRING_ALG_EDDSA,
+		RotationInterval: time.Hour,
+		Retention:        24 * time.Hour,
+		EncryptionKey:    encryptionKey,
+	}
+}
+
+func TestPrivateKeysAreEncryptedAtRest(t *testing.T) {
+	setTestKeyringConfig(bytes.Repeat([]byte{1}, 32))
+
+	// Ключ, сохранённый в открытом виде до включения шифрования
+	legacy, err := generateKey(config.KEYRING_ALG_EDDSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if legacy.PrivateKey, err = decryptPrivateKey(legacy.Kid, legacy.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	legacy.CreatedAt = time.Now()
+
+	store := &memoryStore{keys: []userModel.SigningKeyDbModel{legacy}}
+	if err := Init(store); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, _ := store.GetSigningKeys()
+	for _, key := range keys {
+		if !strings.HasPrefix(key.PrivateKey, encryptedKeyPrefix) || strings.Contains(key.PrivateKey, "PRIVATE KEY") {
+			t.Fatalf("signing key %s is stored unencrypted", key.Kid)
+		}
+	}
+
+	signed, err := Sign(jwt.StandardClaims{Subject: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jwt.Parse(signed, Keyfunc); err != nil {
+		t.Fatalf("expected a token signed with the decrypted key to be valid: %s", err)
+	}
+
+	// Шифротекст привязан к kid и к ключу шифрования
+	if _, err := decryptPrivateKey("other", keys[0].PrivateKey); err == nil {
+		t.Fatal("expected a private key moved to another kid to be rejected")
+	}
+
+	setTestKeyringConfig(bytes.Repeat([]byte{2}, 32))
+	if _, err := decryptPrivateKey(keys[0].Kid, keys[0].PrivateKey); err == nil {
+		t.Fatal("expected decryption with another encryption key to fail")
+	}
+
+	if _, err := parseKey(legacy); err == nil {
+		t.Fatal("expected a plaintext private key to be rejected")
+	}
+}
@@ -0,0 +1,116 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"main-server/config"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+
+	// Длина соли и хэша в байтах
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+/* Параметры argon2id */
+type argon2idHasher struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+func newArgon2idHasher() *argon2idHasher {
+	return &argon2idHasher{
+		memory:  config.AppPasswordConfig.Argon2Memory,
+		time:    config.AppPasswordConfig.Argon2Time,
+		threads: config.AppPasswordConfig.Argon2Threads,
+	}
+}
+
+/* Разобранный хэш в формате $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash> */
+type argon2idHash struct {
+	version uint32
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2id(encoded string) (argon2idHash, error) {
+	var result argon2idHash
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return result, ErrUnknownHash
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &result.version); err != nil {
+		return result, ErrUnknownHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &result.memory, &result.time, &result.threads); err != nil {
+		return result, ErrUnknownHash
+	}
+
+	var err error
+	if result.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return result, ErrUnknownHash
+	}
+
+	if result.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(result.key) == 0 {
+		return result, ErrUnknownHash
+	}
+
+	return result, nil
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, argon2idKeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.memory, h.time, h.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *argon2idHasher) Verify(encoded, password string) (bool, error) {
+	hash, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	if hash.version != argon2.Version {
+		return false, ErrUnknownHash
+	}
+
+	key := argon2.IDKey([]byte(password), hash.salt, hash.time, hash.memory, hash.threads, uint32(len(hash.key)))
+
+	return subtle.ConstantTimeCompare(key, hash.key) == 1, nil
+}
+
+func (h *argon2idHasher) Matches(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	hash, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return hash.memory != h.memory || hash.time != h.time || hash.threads != h.threads ||
+		len(hash.salt) != argon2idSaltLength || len(hash.key) != argon2idKeyLength
+}
@@ -0,0 +1,95 @@
+package password
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"main-server/config"
+	"sync"
+)
+
+/* Хэш пароля в неизвестном формате (алгоритм не удалось определить) */
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+/* Алгоритм хэширования паролей */
+type Hasher interface {
+	// Формирование хэша пароля с текущими параметрами
+	Hash(password string) (string, error)
+
+	// Проверка пароля по сохранённому хэшу
+	Verify(encoded, password string) (bool, error)
+
+	// Признак того, что хэш сформирован этим алгоритмом
+	Matches(encoded string) bool
+
+	// Признак того, что хэш сформирован с устаревшими параметрами
+	NeedsRehash(encoded string) bool
+}
+
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+/* Алгоритм, которым хэшируются новые пароли */
+func defaultHasher() Hasher {
+	if config.AppPasswordConfig.Algorithm == config.PASSWORD_ALG_BCRYPT {
+		return newBcryptHasher()
+	}
+
+	return newArgon2idHasher()
+}
+
+/* Все поддерживаемые алгоритмы (для определения алгоритма сохранённого хэша) */
+func knownHashers() []Hasher {
+	return []Hasher{newArgon2idHasher(), newBcryptHasher()}
+}
+
+/* Определение алгоритма по сохранённому хэшу */
+func detect(encoded string) (Hasher, error) {
+	for _, hasher := range knownHashers() {
+		if hasher.Matches(encoded) {
+			return hasher, nil
+		}
+	}
+
+	return nil, ErrUnknownHash
+}
+
+/* Хэширование пароля алгоритмом по умолчанию */
+func Hash(password string) (string, error) {
+	return defaultHasher().Hash(password)
+}
+
+/*
+* Проверка пароля по сохранённому хэшу. rehash - признак того, что хэш следует пересчитать
+* (сформирован другим алгоритмом или с устаревшими параметрами)
+ */
+func Verify(encoded, password string) (ok bool, rehash bool, err error) {
+	hasher, err := detect(encoded)
+	if err != nil {
+		return false, false, err
+	}
+
+	ok, err = hasher.Verify(encoded, password)
+	if err != nil || !ok {
+		return false, false, err
+	}
+
+	current := defaultHasher()
+	rehash = !current.Matches(encoded) || current.NeedsRehash(encoded)
+
+	return true, rehash, nil
+}
+
+/* Проверка пароля по фиктивному хэшу: выравнивает время ответа для несуществующих учётных записей */
+func VerifyDummy(password string) {
+	dummyHashOnce.Do(func() {
+		value := make([]byte, 16)
+		rand.Read(value)
+
+		dummyHash, _ = Hash(hex.EncodeToString(value))
+	})
+
+	Verify(dummyHash, password)
+}
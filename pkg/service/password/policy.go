@@ -0,0 +1,75 @@
+package password
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"main-server/config"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	blocklist   = map[string]struct{}{}
+	blocklistMu sync.RWMutex
+)
+
+/* Загрузка списка распространённых паролей из локального файла (строки с # - комментарии) */
+func LoadBlocklist() error {
+	file, err := os.Open(config.AppPasswordConfig.BlocklistPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	values := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		values[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	blocklistMu.Lock()
+	blocklist = values
+	blocklistMu.Unlock()
+
+	return nil
+}
+
+func isBlocked(password string) bool {
+	blocklistMu.RLock()
+	defer blocklistMu.RUnlock()
+
+	_, ok := blocklist[strings.ToLower(password)]
+	return ok
+}
+
+/* Проверка нового пароля на соответствие политике: длина и отсутствие в списке распространённых паролей */
+func Validate(password string) error {
+	length := utf8.RuneCountInString(password)
+
+	if length < config.AppPasswordConfig.MinLength {
+		return fmt.Errorf("Пароль должен содержать не менее %d символов!", config.AppPasswordConfig.MinLength)
+	}
+
+	if length > config.AppPasswordConfig.MaxLength {
+		return fmt.Errorf("Пароль должен содержать не более %d символов!", config.AppPasswordConfig.MaxLength)
+	}
+
+	if isBlocked(password) {
+		return errors.New("Пароль слишком распространён, выберите другой!")
+	}
+
+	return nil
+}
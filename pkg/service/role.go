@@ -26,3 +26,23 @@ func (s *RoleService) Get(column string, value interface{}, check bool) (*rbacMo
 func (s *RoleService) HasRole(usersId, domainsId int, roleValue string) (bool, error) {
 	return s.repo.HasRole(usersId, domainsId, roleValue)
 }
+
+/* Получение списка ролей домена */
+func (s *RoleService) GetRoles(data rbacModel.DomainUuidModel) (rbacModel.RolesModel, error) {
+	return s.repo.GetRoles(data)
+}
+
+/* Создание новой роли в домене */
+func (s *RoleService) CreateRole(usersId int, data rbacModel.RoleCreateModel) (rbacModel.RoleModel, error) {
+	return s.repo.CreateRole(usersId, data)
+}
+
+/* Изменение роли */
+func (s *RoleService) UpdateRole(data rbacModel.RoleUpdateModel) (rbacModel.RoleModel, error) {
+	return s.repo.UpdateRole(data)
+}
+
+/* Удаление роли, не назначенной ни одному пользователю */
+func (s *RoleService) DeleteRole(data rbacModel.RoleUuidModel) (bool, error) {
+	return s.repo.DeleteRole(data)
+}
@@ -2,11 +2,16 @@ package service
 
 import (
 	adminModel "main-server/pkg/model/admin"
+	articleModel "main-server/pkg/model/article"
 	companyModel "main-server/pkg/model/company"
 	emailModel "main-server/pkg/model/email"
+	entityModel "main-server/pkg/model/entity"
 	excelModel "main-server/pkg/model/excel"
+	guestModel "main-server/pkg/model/guest"
 	projectModel "main-server/pkg/model/project"
 	rbacModel "main-server/pkg/model/rbac"
+	serviceModel "main-server/pkg/model/service"
+	subEntityModel "main-server/pkg/model/sub_entity"
 	userModel "main-server/pkg/model/user"
 	infoModel "main-server/pkg/module/excel_analysis/model"
 	repository "main-server/pkg/repository"
@@ -15,24 +20,76 @@ import (
 )
 
 type Authorization interface {
-	CreateUser(user userModel.UserRegisterModel) (userModel.UserAuthDataModel, error)
+	CreateUser(user userModel.UserRegisterModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	UploadProfileImage(c *gin.Context, filepath string) (bool, error)
-	LoginUser(user userModel.UserLoginModel) (userModel.UserAuthDataModel, error)
-	LoginUserOAuth2(code string) (userModel.UserAuthDataModel, error)
-	Refresh(data userModel.TokenLogoutDataModel, refreshToken string) (userModel.UserAuthDataModel, error)
+	LoginUser(user userModel.UserLoginModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginUserOAuth2(provider, code, state string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	LoginUserOAuth2SDK(provider, code string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	GetOAuth2URL(provider string) (string, error)
+	Refresh(data userModel.TokenLogoutDataModel, refreshToken string, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
 	Logout(tokens userModel.TokenLogoutDataModel) (bool, error)
 	Activate(link string) (bool, error)
-	RecoveryPassword(email string) (bool, error)
-	ResetPassword(data userModel.ResetPasswordModel) (bool, error)
+	CheckActivation(usersId int) (bool, error)
+	ResendActivation(email, ip string) (bool, error)
+	RecoveryPassword(email, ip string) (bool, error)
+	ResetPassword(data userModel.ResetPasswordModel, ip string) (bool, error)
+}
+
+type TwoFactor interface {
+	Enroll(usersId int) (userModel.TwoFactorEnrollModel, error)
+	Confirm(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error)
+	Disable(user userModel.UserIdentityModel, data userModel.TwoFactorCodeModel) (bool, error)
+	RegenerateRecoveryCodes(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error)
+	GetStatus(user userModel.UserIdentityModel) (userModel.TwoFactorStatusModel, error)
+	SetupChallenge(data userModel.TwoFactorChallengeTokenModel) (userModel.TwoFactorEnrollModel, error)
+	SignIn(data userModel.TwoFactorSignInModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error)
+	GetRequiredRoles(domainsId int) (userModel.TwoFactorRolesModel, error)
+	RequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error)
+	UnrequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error)
+}
+
+type Session interface {
+	GetSessions(usersId int, currentSessionUuid string) (userModel.SessionsModel, error)
+	RevokeSession(usersId int, data userModel.SessionUuidModel) (bool, error)
+	RevokeOtherSessions(usersId int, currentSessionUuid string) (bool, error)
+	CheckSession(usersId int, sessionUuid string) error
+}
+
+type Identity interface {
+	GetIdentities(usersId int) (userModel.IdentitiesModel, error)
+	GetLinkURL(usersId int, provider string) (string, error)
+	LinkIdentity(usersId int, data userModel.IdentityLinkModel) (bool, error)
+	UnlinkIdentity(usersId int, data userModel.IdentityProviderModel) (bool, error)
+	SetPassword(usersId int, data userModel.PasswordSetModel) (bool, error)
+}
+
+type Account interface {
+	GetExport(usersId int) (userModel.AccountExportModel, error)
+	GetExportArchive(usersId int) ([]byte, error)
+	GetDeletion(usersId int) (userModel.AccountDeletionModel, error)
+	RequestDeletion(usersId int, data userModel.AccountDeleteModel) (userModel.AccountDeletionModel, error)
+	CancelDeletion(usersId int) (bool, error)
+	StartPurge()
+}
+
+type EmailChange interface {
+	GetEmailChange(usersId int) (userModel.EmailChangeStatusModel, error)
+	RequestEmailChange(usersId int, data userModel.EmailChangeModel) (bool, error)
+	ConfirmEmailChange(usersId int, data userModel.EmailChangeTokenModel, currentSessionUuid string) (bool, error)
+	CancelEmailChange(data userModel.EmailChangeTokenModel) (bool, error)
 }
 
 type Token interface {
-	ParseToken(token, signingKey string) (userModel.TokenOutputParse, error)
-	ParseTokenWithoutValid(token, signingKey string) (userModel.TokenOutputParse, error)
+	ParseToken(token string) (userModel.TokenOutputParse, error)
+	ParseTokenWithoutValid(token string) (userModel.TokenOutputParse, error)
+	ParseRefreshTokenWithoutValid(token, signingKey string) (userModel.TokenOutputParse, error)
 	ParseResetToken(pToken, signingKey string) (userModel.ResetTokenOutputParse, error)
+	ParseServiceToken(token string) (serviceModel.ServiceTokenOutputParse, error)
+	GetJWKS() userModel.JWKSModel
 }
 
 type AuthType interface {
+	AddAuthType(value string) error
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*userModel.AuthTypeModel, error)
@@ -46,6 +103,13 @@ type User interface {
 	GetAllRoles(user userModel.UserIdentityModel) (*userModel.UserRoleModel, error)
 }
 
+type Ban interface {
+	BanUser(admin userModel.UserIdentityModel, data userModel.BanCreateModel) (userModel.BanModel, error)
+	UnbanUser(data userModel.BanUserUuidModel) (bool, error)
+	GetBans(data userModel.BanCountModel) (userModel.BanAnyCountModel, error)
+	CheckBan(usersId int) error
+}
+
 type Admin interface {
 	GetAllUsers(c *gin.Context) (adminModel.UsersResponseModel, error)
 	CreateCompany(c *gin.Context, data adminModel.CompanyModel) (adminModel.CompanyModel, error)
@@ -53,13 +117,27 @@ type Admin interface {
 }
 
 type Domain interface {
+	GetDomains() (rbacModel.DomainsModel, error)
+	CreateDomain(usersId int, data rbacModel.DomainCreateModel) (rbacModel.DomainModel, error)
+	UpdateDomain(data rbacModel.DomainUpdateModel) (rbacModel.DomainModel, error)
+	DeleteDomain(data rbacModel.DomainUuidModel) (bool, error)
+	Resolve(value string) (*rbacModel.DomainModel, error)
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*rbacModel.DomainModel, error)
 }
 
+type Access interface {
+	Explain(usersId, domainsId int, data rbacModel.AccessExplainModel) (rbacModel.AccessExplainResultModel, error)
+	ExplainForUser(domainsId int, data rbacModel.AccessExplainUserModel) (rbacModel.AccessExplainResultModel, error)
+}
+
 type Role interface {
 	HasRole(usersId, domainsId int, roleValue string) (bool, error)
+	GetRoles(data rbacModel.DomainUuidModel) (rbacModel.RolesModel, error)
+	CreateRole(usersId int, data rbacModel.RoleCreateModel) (rbacModel.RoleModel, error)
+	UpdateRole(data rbacModel.RoleUpdateModel) (rbacModel.RoleModel, error)
+	DeleteRole(data rbacModel.RoleUuidModel) (bool, error)
 
 	// CRUD
 	Get(column string, value interface{}, check bool) (*rbacModel.RoleModel, error)
@@ -73,6 +151,41 @@ type Project interface {
 	GetProjects(userId, domainId int, data projectModel.ProjectCountModel) (projectModel.ProjectAnyCountModel, error)
 }
 
+type Entity interface {
+	CreateEntity(user userModel.UserIdentityModel, data entityModel.EntityCreateModel) (entityModel.EntityDbDataEx, error)
+	EntityUpdate(user userModel.UserIdentityModel, data entityModel.EntityUpdateModel) (entityModel.EntityUpdateModel, error)
+	GetEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (entityModel.EntityDbDataEx, error)
+	GetEntities(user userModel.UserIdentityModel, data entityModel.EntityCountModel) (entityModel.EntityAnyCountModel, error)
+	DeleteEntity(user userModel.UserIdentityModel, data entityModel.EntityUuidModel) (bool, error)
+}
+
+type SubEntity interface {
+	CreateSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityCreateModel) (subEntityModel.SubEntityInfoModel, error)
+	SubEntityUpdate(user userModel.UserIdentityModel, data subEntityModel.SubEntityUpdateModel) (subEntityModel.SubEntityUpdateModel, error)
+	GetSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (subEntityModel.SubEntityInfoModel, error)
+	GetSubEntities(user userModel.UserIdentityModel, data subEntityModel.SubEntityCountModel) (subEntityModel.SubEntityAnyCountModel, error)
+	DeleteSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (bool, error)
+}
+
+type Guest interface {
+	GetCompanies(data guestModel.GuestCompanyQueryModel) (guestModel.GuestCompanyAnyCountModel, error)
+	GetProjects(data guestModel.GuestProjectQueryModel) (guestModel.GuestProjectAnyCountModel, error)
+	GetSubEntities(data guestModel.GuestSubEntityQueryModel) (guestModel.GuestSubEntityAnyCountModel, error)
+	GetSubEntity(data guestModel.GuestUuidModel) (guestModel.GuestSubEntityModel, error)
+}
+
+type Article interface {
+	CreateArticle(user userModel.UserIdentityModel, data articleModel.ArticleCreateRequestModel) (articleModel.ArticleModel, error)
+	UpdateArticle(user userModel.UserIdentityModel, data articleModel.ArticleUpdateRequestModel) (articleModel.ArticleModel, error)
+	DeleteArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleSuccessModel, error)
+	GetUserArticle(user userModel.UserIdentityModel, data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error)
+	GetUserArticles(user userModel.UserIdentityModel, data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error)
+	GetUncheckedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error)
+	ModerateArticle(data articleModel.ArticleModerationModel, status string) (articleModel.ArticleSuccessModel, error)
+	GetApprovedArticle(data articleModel.ArticleUuidModel) (articleModel.ArticleModel, error)
+	GetApprovedArticles(data articleModel.ArticleCountModel) (articleModel.ArticlesModel, error)
+}
+
 type Company interface {
 	GetManagers(userId, domainId int, data companyModel.ManagerCountModel) (companyModel.ManagerAnyCountModel, error)
 	GetManager(user userModel.UserIdentityModel, data companyModel.ManagerUuidModel) (companyModel.ManagerCompanyModel, error)
@@ -81,7 +194,19 @@ type Company interface {
 }
 
 type ServiceMain interface {
-	SendEmail(user *userModel.UserIdentityModel, body *emailModel.MessageInputModel) (bool, error)
+	SendEmail(clientId string, body *emailModel.MessageInputModel) (bool, error)
+}
+
+type Introspection interface {
+	Introspect(data userModel.TokenIntrospectInputModel, domainsId int) (userModel.TokenIntrospectionModel, error)
+	Revoke(data userModel.TokenIntrospectInputModel) error
+}
+
+type ServiceClient interface {
+	CreateClient(data serviceModel.ServiceClientCreateModel) (serviceModel.ServiceClientSecretModel, error)
+	GetClients() (serviceModel.ServiceClientsModel, error)
+	RevokeClient(data serviceModel.ServiceClientIdModel) (bool, error)
+	IssueToken(data serviceModel.ServiceTokenRequestModel, ip string) (serviceModel.ServiceTokenModel, error)
 }
 
 type ExcelAnalysis interface {
@@ -95,34 +220,74 @@ type Object interface {
 	Get(column string, value interface{}, check bool) (*rbacModel.ObjectDbModel, error)
 }
 
+type TypeObject interface {
+	GetTypes() (rbacModel.TypeObjectsModel, error)
+	CreateType(usersId int, data rbacModel.TypeObjectCreateModel) (rbacModel.TypeObjectDbModel, error)
+	UpdateType(data rbacModel.TypeObjectUpdateModel) (rbacModel.TypeObjectDbModel, error)
+	DeleteType(data rbacModel.TypeObjectValueModel) (bool, error)
+
+	// CRUD
+	Get(column string, value interface{}, check bool) (*rbacModel.TypeObjectDbModel, error)
+}
+
 type Service struct {
 	Authorization
 	Token
+	Introspection
 	User
 	Admin
+	Ban
+	Session
+	Identity
+	Account
+	EmailChange
+	TwoFactor
+	AuthType
 	Domain
 	Role
+	Access
 	Project
+	Entity
+	SubEntity
+	Guest
+	Article
 	Company
 	ServiceMain
+	ServiceClient
 	ExcelAnalysis
 	Object
+	TypeObject
 }
 
 func NewService(repos *repository.Repository) *Service {
-	tokenService := NewTokenService(repos.Role, repos.User, repos.AuthType)
+	tokenService := NewTokenService(repos.Role, repos.User, repos.AuthType, repos.ServiceClient, repos.Revocation)
 
 	return &Service{
 		Token:         tokenService,
-		Authorization: NewAuthService(repos.Authorization, *tokenService),
+		Introspection: NewIntrospectionService(tokenService, repos.Revocation, repos.Session, repos.Ban, repos.User),
+		Authorization: NewAuthService(repos.Authorization, repos.Throttle, *tokenService),
 		User:          NewUserService(repos.User),
 		Admin:         NewAdminService(repos.Admin),
+		Ban:           NewBanService(repos.Ban),
+		Session:       NewSessionService(repos.Session),
+		Identity:      NewIdentityService(repos.Identity),
+		Account:       NewAccountService(repos.Account),
+		EmailChange:   NewEmailChangeService(repos.EmailChange),
+		TwoFactor:     NewTwoFactorService(repos.TwoFactor, repos.Throttle),
+		AuthType:      NewAuthTypeService(repos.AuthType),
 		Domain:        NewDomainService(repos.Domain),
 		Role:          NewRoleService(repos.Role),
+		Access:        NewAccessService(repos.Access, repos.User),
 		Project:       NewProjectService(repos.Project),
+		Entity:        NewEntityService(repos.Entity),
+		SubEntity:     NewSubEntityService(repos.SubEntity),
+		Guest:         NewGuestService(repos.Guest),
+		Article:       NewArticleService(repos.Article),
 		Company:       NewCompanyService(repos.Company),
 		ServiceMain:   NewServiceMainService(repos.ServiceMain),
+		ServiceClient: NewServiceClientService(repos.ServiceClient, repos.Throttle),
 		ExcelAnalysis: NewExcelAnalysisService(repos.ExcelAnalysis),
 		Object:        NewObjectService(repos.Object),
+		TypeObject:    NewTypeObjectService(repos.TypeObject),
 	}
 }
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	authConstant "main-server/pkg/constant/auth"
+	serviceModel "main-server/pkg/model/service"
+	repository "main-server/pkg/repository"
+)
+
+/* Ошибки выдачи токена сервисному клиенту (соответствуют кодам ошибок OAuth2) */
+var (
+	ErrUnsupportedGrantType = errors.New("Поддерживается только grant_type=client_credentials")
+	ErrInvalidClient        = repository.ErrInvalidClient
+	ErrInvalidScope         = repository.ErrInvalidScope
+)
+
+type ServiceClientService struct {
+	repo     repository.ServiceClient
+	throttle repository.Throttle
+}
+
+func NewServiceClientService(repo repository.ServiceClient, throttle repository.Throttle) *ServiceClientService {
+	return &ServiceClientService{
+		repo:     repo,
+		throttle: throttle,
+	}
+}
+
+/* Регистрация сервисного клиента */
+func (s *ServiceClientService) CreateClient(data serviceModel.ServiceClientCreateModel) (serviceModel.ServiceClientSecretModel, error) {
+	return s.repo.CreateClient(data)
+}
+
+/* Получение всех сервисных клиентов */
+func (s *ServiceClientService) GetClients() (serviceModel.ServiceClientsModel, error) {
+	return s.repo.GetClients()
+}
+
+/* Отключение сервисного клиента */
+func (s *ServiceClientService) RevokeClient(data serviceModel.ServiceClientIdModel) (bool, error) {
+	return s.repo.RevokeClient(data)
+}
+
+/* Выдача токена по client credentials (неудачные попытки учитываются по client_id и IP-адресу) */
+func (s *ServiceClientService) IssueToken(data serviceModel.ServiceTokenRequestModel, ip string) (serviceModel.ServiceTokenModel, error) {
+	if data.GrantType != authConstant.SERVICE_GRANT_CLIENT_CREDENTIALS {
+		return serviceModel.ServiceTokenModel{}, ErrUnsupportedGrantType
+	}
+
+	wait, err := s.throttle.Check(authConstant.THROTTLE_SERVICE, data.ClientId, ip)
+	if err != nil {
+		return serviceModel.ServiceTokenModel{}, err
+	}
+
+	if wait > 0 {
+		return serviceModel.ServiceTokenModel{}, &TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	token, err := s.repo.IssueToken(data)
+	if errors.Is(err, repository.ErrInvalidClient) {
+		if err := s.throttle.Fail(authConstant.THROTTLE_SERVICE, data.ClientId, ip); err != nil {
+			return serviceModel.ServiceTokenModel{}, err
+		}
+
+		return serviceModel.ServiceTokenModel{}, err
+	}
+
+	if err != nil {
+		return serviceModel.ServiceTokenModel{}, err
+	}
+
+	return token, s.throttle.Reset(authConstant.THROTTLE_SERVICE, data.ClientId)
+}
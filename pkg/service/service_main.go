@@ -2,7 +2,6 @@ package service
 
 import (
 	emailModel "main-server/pkg/model/email"
-	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
 )
 
@@ -16,6 +15,6 @@ func NewServiceMainService(repo repository.ServiceMain) *ServiceMainService {
 	}
 }
 
-func (s *ServiceMainService) SendEmail(user *userModel.UserIdentityModel, body *emailModel.MessageInputModel) (bool, error) {
-	return s.repo.SendEmail(user, body)
+func (s *ServiceMainService) SendEmail(clientId string, body *emailModel.MessageInputModel) (bool, error) {
+	return s.repo.SendEmail(clientId, body)
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса */
+type SessionService struct {
+	repo repository.Session
+}
+
+/* Создание нового экземпляра структуры */
+func NewSessionService(repo repository.Session) *SessionService {
+	return &SessionService{
+		repo: repo,
+	}
+}
+
+/* Получение списка активных сессий пользователя */
+func (s *SessionService) GetSessions(usersId int, currentSessionUuid string) (userModel.SessionsModel, error) {
+	return s.repo.GetSessions(usersId, currentSessionUuid)
+}
+
+/* Завершение сессии пользователя */
+func (s *SessionService) RevokeSession(usersId int, data userModel.SessionUuidModel) (bool, error) {
+	return s.repo.RevokeSession(usersId, data)
+}
+
+/* Завершение всех сессий пользователя, кроме текущей */
+func (s *SessionService) RevokeOtherSessions(usersId int, currentSessionUuid string) (bool, error) {
+	return s.repo.RevokeOtherSessions(usersId, currentSessionUuid)
+}
+
+/* Проверка, что сессия пользователя не завершена */
+func (s *SessionService) CheckSession(usersId int, sessionUuid string) error {
+	return s.repo.CheckSession(usersId, sessionUuid)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	subEntityModel "main-server/pkg/model/sub_entity"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Structure for this service */
+type SubEntityService struct {
+	repo repository.SubEntity
+}
+
+/* Function for create new struct of SubEntityService */
+func NewSubEntityService(repo repository.SubEntity) *SubEntityService {
+	return &SubEntityService{
+		repo: repo,
+	}
+}
+
+/* Method for create new sub entity in entity */
+func (s *SubEntityService) CreateSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityCreateModel) (subEntityModel.SubEntityInfoModel, error) {
+	return s.repo.CreateSubEntity(user, data)
+}
+
+/* Method for update sub entity */
+func (s *SubEntityService) SubEntityUpdate(user userModel.UserIdentityModel, data subEntityModel.SubEntityUpdateModel) (subEntityModel.SubEntityUpdateModel, error) {
+	return s.repo.SubEntityUpdate(user, data)
+}
+
+/* Method for get information about sub entity */
+func (s *SubEntityService) GetSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (subEntityModel.SubEntityInfoModel, error) {
+	return s.repo.GetSubEntity(user, data)
+}
+
+/* Method for get any count sub entities of entity */
+func (s *SubEntityService) GetSubEntities(user userModel.UserIdentityModel, data subEntityModel.SubEntityCountModel) (subEntityModel.SubEntityAnyCountModel, error) {
+	return s.repo.GetSubEntities(user, data)
+}
+
+/* Method for delete sub entity */
+func (s *SubEntityService) DeleteSubEntity(user userModel.UserIdentityModel, data subEntityModel.SubEntityUuidModel) (bool, error) {
+	return s.repo.DeleteSubEntity(user, data)
+}
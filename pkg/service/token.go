@@ -2,28 +2,39 @@ package service
 
 import (
 	"errors"
+	serviceModel "main-server/pkg/model/service"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	"main-server/pkg/service/keyring"
+
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/samber/lo"
 )
 
 /* Структура TokenService */
 type TokenService struct {
-	role     repository.Role
-	user     repository.User
-	authType repository.AuthType
+	role       repository.Role
+	user       repository.User
+	authType   repository.AuthType
+	client     repository.ServiceClient
+	revocation repository.Revocation
 }
 
 /* Функция создания нового сервиса TokenService */
 func NewTokenService(role repository.Role,
 	user repository.User,
 	authType repository.AuthType,
+	client repository.ServiceClient,
+	revocation repository.Revocation,
 ) *TokenService {
 	return &TokenService{
-		role:     role,
-		user:     user,
-		authType: authType,
+		role:       role,
+		user:       user,
+		authType:   authType,
+		client:     client,
+		revocation: revocation,
 	}
 }
 
@@ -33,30 +44,53 @@ type tokenClaims struct {
 	UsersId     string  `json:"users_id"`      // ID for user
 	AuthTypesId string  `json:"auth_types_id"` // Type auth for user
 	TokenApi    *string `json:"token_api"`     // External token access
+	SessionUuid *string `json:"session_uuid"`  // User session (device)
 }
 
-/* Парсинг токена с предварительной валидацией */
-func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+/* Функция получения ключа для проверки HMAC-подписи токена */
+func hmacKeyfunc(signingKey string) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
 
 		return []byte(signingKey), nil
-	})
-	if err != nil {
-		return userModel.TokenOutputParse{}, err
 	}
+}
 
-	if !token.Valid {
+/*
+* Разбор токена и получение данных пользователя.
+* Без проверки валидности (checkExpiry = false) допускается только истёкший срок действия, подпись проверяется всегда
+ */
+func (s *TokenService) parseTokenClaims(pToken string, keyFunc jwt.Keyfunc, checkExpiry bool) (userModel.TokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, keyFunc)
+	if err != nil {
+		vErr, ok := err.(*jwt.ValidationError)
+		if checkExpiry || !ok || vErr.Errors != jwt.ValidationErrorExpired {
+			return userModel.TokenOutputParse{}, err
+		}
+	} else if !token.Valid {
 		return userModel.TokenOutputParse{}, errors.New("Ошибка: некорректный токен")
 	}
 
+	// Получение данных из токена (с преобразованием к указателю на tokenClaims)
 	claims, ok := token.Claims.(*tokenClaims)
-	if !ok {
+	if !ok || claims.UsersId == "" {
 		return userModel.TokenOutputParse{}, errors.New("Ошибка: некорректный токен")
 	}
 
+	// Действующий токен не должен находиться в списке отозванных (выход, завершение сессии, /auth/revoke)
+	if checkExpiry {
+		revoked, err := s.revocation.IsTokenRevoked(claims.Id)
+		if err != nil {
+			return userModel.TokenOutputParse{}, err
+		}
+
+		if revoked {
+			return userModel.TokenOutputParse{}, errors.New("Ошибка: токен отозван")
+		}
+	}
+
 	user, err := s.user.Get("uuid", claims.UsersId, true)
 	if err != nil {
 		return userModel.TokenOutputParse{}, err
@@ -68,46 +102,63 @@ func (s *TokenService) ParseToken(pToken, signingKey string) (userModel.TokenOut
 	}
 
 	return userModel.TokenOutputParse{
-		UsersId:   user.Id,
-		UsersUuid: claims.UsersId,
-		AuthType:  *authType,
-		TokenApi:  claims.TokenApi,
+		UsersId:     user.Id,
+		UsersUuid:   claims.UsersId,
+		AuthType:    *authType,
+		TokenApi:    claims.TokenApi,
+		SessionUuid: claims.SessionUuid,
+		TokenId:     claims.Id,
+		ExpiresAt:   claims.ExpiresAt,
+		IssuedAt:    claims.IssuedAt,
 	}, nil
 }
 
-/* Parse token without validate check */
-func (s *TokenService) ParseTokenWithoutValid(pToken, signingKey string) (userModel.TokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
+/* Парсинг токена доступа с предварительной валидацией (подпись проверяется по набору ключей) */
+func (s *TokenService) ParseToken(pToken string) (userModel.TokenOutputParse, error) {
+	return s.parseTokenClaims(pToken, keyring.Keyfunc, true)
+}
 
-		return []byte(signingKey), nil
-	})
+/* Парсинг токена доступа без проверки срока действия */
+func (s *TokenService) ParseTokenWithoutValid(pToken string) (userModel.TokenOutputParse, error) {
+	return s.parseTokenClaims(pToken, keyring.Keyfunc, false)
+}
 
-	// Получение данных из токена (с преобразованием к указателю на tokenClaims)
-	claims, ok := token.Claims.(*tokenClaims)
-	if !ok {
-		return userModel.TokenOutputParse{}, errors.New("token claims are not of type")
-	}
+/* Парсинг токена обновления (HMAC) без проверки срока действия */
+func (s *TokenService) ParseRefreshTokenWithoutValid(pToken, signingKey string) (userModel.TokenOutputParse, error) {
+	return s.parseTokenClaims(pToken, hmacKeyfunc(signingKey), false)
+}
 
-	user, err := s.user.Get("uuid", claims.UsersId, true)
+/*
+* Парсинг токена сервисного клиента с проверкой, что клиент не отключён.
+* Возвращаются только области доступа, которые разрешены клиенту на текущий момент
+ */
+func (s *TokenService) ParseServiceToken(pToken string) (serviceModel.ServiceTokenOutputParse, error) {
+	token, err := jwt.ParseWithClaims(pToken, &serviceModel.ServiceTokenClaims{}, keyring.Keyfunc)
 	if err != nil {
-		return userModel.TokenOutputParse{}, err
+		return serviceModel.ServiceTokenOutputParse{}, err
 	}
 
-	authType, err := s.authType.Get("uuid", claims.AuthTypesId, true)
+	claims, ok := token.Claims.(*serviceModel.ServiceTokenClaims)
+	if !ok || !token.Valid || claims.ClientId == "" {
+		return serviceModel.ServiceTokenOutputParse{}, errors.New("Ошибка: некорректный токен")
+	}
+
+	client, err := s.client.GetActiveClient(claims.ClientId)
 	if err != nil {
-		return userModel.TokenOutputParse{}, err
+		return serviceModel.ServiceTokenOutputParse{}, err
 	}
 
-	return userModel.TokenOutputParse{
-		UsersId:  user.Id,
-		AuthType: *authType,
-		TokenApi: claims.TokenApi,
+	return serviceModel.ServiceTokenOutputParse{
+		ClientId: client.ClientId,
+		Scopes:   lo.Intersect(strings.Fields(client.Scopes), strings.Fields(claims.Scope)),
 	}, nil
 }
 
+/* Набор открытых ключей для проверки токенов доступа */
+func (s *TokenService) GetJWKS() userModel.JWKSModel {
+	return keyring.JWKS()
+}
+
 /* Структура тела токена для смены пароля пользователя (частный случай) */
 type tokenResetClaims struct {
 	jwt.StandardClaims
@@ -117,13 +168,7 @@ type tokenResetClaims struct {
 
 /* Parse reset token with validate check */
 func (s *TokenService) ParseResetToken(pToken, signingKey string) (userModel.ResetTokenOutputParse, error) {
-	token, err := jwt.ParseWithClaims(pToken, &tokenResetClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-
-		return []byte(signingKey), nil
-	})
+	token, err := jwt.ParseWithClaims(pToken, &tokenResetClaims{}, hmacKeyfunc(signingKey))
 	if err != nil {
 		return userModel.ResetTokenOutputParse{}, err
 	}
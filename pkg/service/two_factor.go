@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	authConstant "main-server/pkg/constant/auth"
+	userModel "main-server/pkg/model/user"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса */
+type TwoFactorService struct {
+	repo     repository.TwoFactor
+	throttle repository.Throttle
+}
+
+/* Создание нового экземпляра структуры */
+func NewTwoFactorService(repo repository.TwoFactor, throttle repository.Throttle) *TwoFactorService {
+	return &TwoFactorService{
+		repo:     repo,
+		throttle: throttle,
+	}
+}
+
+/* Начало подключения 2FA */
+func (s *TwoFactorService) Enroll(usersId int) (userModel.TwoFactorEnrollModel, error) {
+	return s.repo.Enroll(usersId)
+}
+
+/* Подтверждение подключения 2FA */
+func (s *TwoFactorService) Confirm(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error) {
+	return s.repo.Confirm(usersId, data)
+}
+
+/* Отключение 2FA */
+func (s *TwoFactorService) Disable(user userModel.UserIdentityModel, data userModel.TwoFactorCodeModel) (bool, error) {
+	return s.repo.Disable(user, data)
+}
+
+/* Перевыпуск кодов восстановления */
+func (s *TwoFactorService) RegenerateRecoveryCodes(usersId int, data userModel.TwoFactorCodeModel) (userModel.TwoFactorRecoveryCodesModel, error) {
+	return s.repo.RegenerateRecoveryCodes(usersId, data)
+}
+
+/* Получение состояния 2FA пользователя */
+func (s *TwoFactorService) GetStatus(user userModel.UserIdentityModel) (userModel.TwoFactorStatusModel, error) {
+	return s.repo.GetStatus(user)
+}
+
+/* Подключение 2FA на втором шаге авторизации */
+func (s *TwoFactorService) SetupChallenge(data userModel.TwoFactorChallengeTokenModel) (userModel.TwoFactorEnrollModel, error) {
+	return s.repo.SetupChallenge(data)
+}
+
+/*
+ * Второй шаг авторизации. Неудачные попытки учитываются для учётной записи и IP-адреса по всем
+ * промежуточным токенам: ограничение TWO_FACTOR_MAX_ATTEMPTS действует только в пределах одного токена
+ */
+func (s *TwoFactorService) SignIn(data userModel.TwoFactorSignInModel, info userModel.SessionInfoModel) (userModel.UserAuthDataModel, error) {
+	email, err := s.repo.GetChallengeEmail(data.ChallengeToken)
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if err := checkThrottle(s.throttle, authConstant.THROTTLE_2FA, email, info.Ip); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	tokens, err := s.repo.SignIn(data, info)
+	if errors.Is(err, repository.ErrInvalidTwoFactorCode) {
+		if err := s.throttle.Fail(authConstant.THROTTLE_2FA, email, info.Ip); err != nil {
+			return userModel.UserAuthDataModel{}, err
+		}
+
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	if err := s.throttle.Reset(authConstant.THROTTLE_2FA, email); err != nil {
+		return userModel.UserAuthDataModel{}, err
+	}
+
+	return tokens, s.throttle.Reset(authConstant.THROTTLE_SIGN_IN, email)
+}
+
+/* Получение ролей, для которых 2FA обязательна */
+func (s *TwoFactorService) GetRequiredRoles(domainsId int) (userModel.TwoFactorRolesModel, error) {
+	return s.repo.GetRequiredRoles(domainsId)
+}
+
+/* Включение обязательной 2FA для роли */
+func (s *TwoFactorService) RequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error) {
+	return s.repo.RequireForRole(admin, data)
+}
+
+/* Отключение обязательной 2FA для роли */
+func (s *TwoFactorService) UnrequireForRole(admin userModel.UserIdentityModel, data userModel.TwoFactorRoleValueModel) (bool, error) {
+	return s.repo.UnrequireForRole(admin, data)
+}
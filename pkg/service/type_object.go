@@ -0,0 +1,43 @@
+package service
+
+import (
+	rbacModel "main-server/pkg/model/rbac"
+	repository "main-server/pkg/repository"
+)
+
+/* Структура сервиса типов объектов */
+type TypeObjectService struct {
+	repo repository.TypeObject
+}
+
+/* Функция для создания нового сервиса типов объектов */
+func NewTypeObjectService(repo repository.TypeObject) *TypeObjectService {
+	return &TypeObjectService{
+		repo: repo,
+	}
+}
+
+/* Метод для получения типа объекта */
+func (s *TypeObjectService) Get(column string, value interface{}, check bool) (*rbacModel.TypeObjectDbModel, error) {
+	return s.repo.Get(column, value, check)
+}
+
+/* Получение списка типов объектов */
+func (s *TypeObjectService) GetTypes() (rbacModel.TypeObjectsModel, error) {
+	return s.repo.GetTypes()
+}
+
+/* Регистрация нового типа объектов */
+func (s *TypeObjectService) CreateType(usersId int, data rbacModel.TypeObjectCreateModel) (rbacModel.TypeObjectDbModel, error) {
+	return s.repo.CreateType(usersId, data)
+}
+
+/* Изменение типа объектов */
+func (s *TypeObjectService) UpdateType(data rbacModel.TypeObjectUpdateModel) (rbacModel.TypeObjectDbModel, error) {
+	return s.repo.UpdateType(data)
+}
+
+/* Удаление типа объектов */
+func (s *TypeObjectService) DeleteType(data rbacModel.TypeObjectValueModel) (bool, error) {
+	return s.repo.DeleteType(data)
+}
@@ -5,6 +5,7 @@ import (
 	rbacModel "main-server/pkg/model/rbac"
 	userModel "main-server/pkg/model/user"
 	repository "main-server/pkg/repository"
+	passwordService "main-server/pkg/service/password"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +29,12 @@ func (s *UserService) GetProfile(c *gin.Context) (userModel.UserProfileModel, er
 
 /* Method for update profile user */
 func (s *UserService) UpdateProfile(c *gin.Context, data userModel.UserProfileUpdateDataModel) (userModel.UserJSONBModel, error) {
+	if data.Password != nil {
+		if err := passwordService.Validate(*data.Password); err != nil {
+			return userModel.UserJSONBModel{}, err
+		}
+	}
+
 	return s.repo.UpdateProfile(c, data)
 }
 
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/* Параметры TOTP (RFC 6238), поддерживаемые всеми распространёнными приложениями-аутентификаторами */
+const (
+	TOTP_PERIOD = 30
+	TOTP_DIGITS = 6
+	TOTP_SKEW   = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+/* Генерация нового секрета TOTP (160 бит, base32 без выравнивания) */
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+/* Вычисление одноразового кода для заданного временного шага (RFC 4226) */
+func totpCode(secret string, counter int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", TOTP_DIGITS, value%1000000), nil
+}
+
+/*
+* Проверка кода TOTP с допуском в TOTP_SKEW шагов в обе стороны.
+* Возвращает номер временного шага, которому соответствует код - код принимается,
+* только если этот шаг больше lastCounter (защита от повторного использования кода)
+ */
+func ValidateTOTP(secret, code string, at time.Time, lastCounter int64) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != TOTP_DIGITS {
+		return 0, false
+	}
+
+	current := at.Unix() / TOTP_PERIOD
+	for step := int64(-TOTP_SKEW); step <= TOTP_SKEW; step++ {
+		counter := current + step
+		if counter <= lastCounter {
+			continue
+		}
+
+		expected, err := totpCode(secret, counter)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+/* Формирование URI для QR-кода (формат otpauth://, Key Uri Format) */
+func TOTPProvisioningURI(issuer, account, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprintf("%d", TOTP_DIGITS))
+	values.Set("period", fmt.Sprintf("%d", TOTP_PERIOD))
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + values.Encode()
+}